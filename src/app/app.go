@@ -3,14 +3,18 @@ package app
 
 import (
 	"github.com/newrelic/release-toolkit/src/app/common"
+	"github.com/newrelic/release-toolkit/src/app/config"
 	"github.com/newrelic/release-toolkit/src/app/generate"
 	"github.com/newrelic/release-toolkit/src/app/isempty"
 	"github.com/newrelic/release-toolkit/src/app/isheld"
 	"github.com/newrelic/release-toolkit/src/app/link"
 	"github.com/newrelic/release-toolkit/src/app/nextversion"
+	"github.com/newrelic/release-toolkit/src/app/release"
 	"github.com/newrelic/release-toolkit/src/app/render"
+	"github.com/newrelic/release-toolkit/src/app/tag"
 	"github.com/newrelic/release-toolkit/src/app/update"
 	"github.com/newrelic/release-toolkit/src/app/validate"
+	"github.com/newrelic/release-toolkit/src/git"
 	"github.com/urfave/cli/v2"
 )
 
@@ -28,6 +32,28 @@ func App() *cli.App {
 				Value:   "changelog.yaml",
 				Usage:   "Path to the changelog.yaml file",
 			},
+			// -monorepo makes commands that support it run once for each component listed in the manifest.
+			&cli.StringFlag{
+				Name:    common.MonorepoFlag,
+				EnvVars: common.EnvFor(common.MonorepoFlag),
+				Value:   "",
+				Usage:   "Path to a monorepo manifest listing components, their paths, tag prefixes and changelog files",
+			},
+			// -config points to a file with values for flags not set in the command line or env vars.
+			&cli.StringFlag{
+				Name:    common.ConfigFlag,
+				EnvVars: common.EnvFor(common.ConfigFlag),
+				Value:   "",
+				Usage:   "Path to a config file with values for flags. Defaults to " + config.FileName + " in the git root, if present",
+			},
+			// -git-backend selects how commands reading commits and tags access the git repository.
+			&cli.StringFlag{
+				Name:    common.GitBackendFlag,
+				EnvVars: common.EnvFor(common.GitBackendFlag),
+				Value:   string(git.BackendNative),
+				Usage: "How to read git repositories: native, using a built-in git implementation, or exec, running the git " +
+					"binary, which supports partial clones, sparse checkouts, SHA-256 repositories, worktrees and safe.directory",
+			},
 			// -gha tells commands to output workflow commands as understood by Github Actions.
 			&cli.BoolFlag{
 				Name:    common.GHAFlag,
@@ -45,6 +71,8 @@ func App() *cli.App {
 			validate.Cmd,
 			link.Cmd,
 			isempty.Cmd,
+			release.Cmd,
+			tag.Cmd,
 		},
 	}
 }
@@ -5,6 +5,17 @@ const (
 	// This flag is common and used by most commands.
 	YAMLFlag = "yaml"
 
+	// MonorepoFlag is the command line flag to specify the path to a monorepo manifest. Commands supporting it operate
+	// on all the components listed in the manifest instead of on a single changelog.
+	MonorepoFlag = "monorepo"
+
+	// ConfigFlag is the command line flag to specify the path to a config file holding default values for flags.
+	ConfigFlag = "config"
+
+	// GitBackendFlag is the command line flag to select how commands read git repositories, either with go-git or by
+	// running the git binary.
+	GitBackendFlag = "git-backend"
+
 	// GHAFlag is the flag used by commands to identify if they should output GHA-syntax to stdout.
 	GHAFlag = "gha"
 	// GHAEnv is the env var equivalent for GHAFlag
@@ -12,4 +23,9 @@ const (
 	// variable to differentiate when tests are being run locally or by GitHub Actions.
 	// https://docs.github.com/en/actions/learn-github-actions/environment-variables#default-environment-variables
 	GHAEnv = "GITHUB_ACTIONS"
+	// GHAOutputEnv points to the file where step outputs are written when running on GitHub Actions.
+	GHAOutputEnv = "GITHUB_OUTPUT"
+	// GHAStepSummaryEnv points to the file where the markdown summary of the step is written when running on GitHub
+	// Actions.
+	GHAStepSummaryEnv = "GITHUB_STEP_SUMMARY"
 )
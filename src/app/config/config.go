@@ -0,0 +1,190 @@
+// Package config loads the repository-wide configuration file, which provides default values for command line flags
+// so they do not need to be repeated on every invocation of the toolkit.
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/newrelic/release-toolkit/src/app/common"
+	"github.com/newrelic/release-toolkit/src/changelog/linker/mapper"
+	log "github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// FileName is the name of the config file looked for in the root of the repository if common.ConfigFlag is not set.
+const FileName = ".release-toolkit.yaml"
+
+// gitRootFlag is the flag some commands use to point to the repository, where the config file is discovered.
+const gitRootFlag = "git-root"
+
+// metadataKey is the key under which the loaded Config is stored in cli.App.Metadata.
+const metadataKey = "release-toolkit-config"
+
+var ErrInvalidValue = errors.New("value must be a scalar or a list of scalars")
+
+// Config holds the contents of a config file.
+type Config struct {
+	// Flags holds values for command line flags, keyed by flag name. They apply to any command defining a flag with
+	// that name, including global flags.
+	Flags map[string]interface{} `yaml:",inline"`
+	// Commands holds values for command line flags that apply only to a given command, keyed by command name. They
+	// take precedence over Flags.
+	Commands map[string]map[string]interface{} `yaml:"commands"`
+	// ExcludedDependencies is a list of dependencies that are left out of generated changelogs, in addition to the
+	// ones listed in the manifest pointed to by --excluded-dependencies-manifest.
+	ExcludedDependencies []string `yaml:"excluded-dependencies"`
+	// Dictionary maps dependencies to templates for their changelog links, in the same format used by
+	// link-dependencies --dictionary. Entries in the dictionary file take precedence over these.
+	Dictionary mapper.Dictionary `yaml:"dictionary"`
+	// Handles maps the emails of commit authors to their GitHub handles, so entries generated from their commits credit
+	// them as @handle.
+	Handles map[string]string `yaml:"handles"`
+}
+
+// Load reads a config file from path.
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading config file %q: %w", path, err)
+	}
+
+	cfg := &Config{}
+	err = yaml.Unmarshal(data, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("parsing config file %q: %w", path, err)
+	}
+
+	return cfg, nil
+}
+
+// Apply is a cli.BeforeFunc that loads the config file and uses it to populate flags of the running command, and
+// global flags, that have not been set either on the command line or through their environment variable. Thus,
+// values are taken from command line flags first, then environment variables, then the config file, and finally
+// from flag defaults.
+// The config file is read from the path in common.ConfigFlag if set, or from FileName in the root of the repository
+// otherwise, in which case it is not an error for it to not exist.
+// The loaded config is stored in the app metadata and can be retrieved with FromContext.
+func Apply(cCtx *cli.Context) error {
+	path := cCtx.String(common.ConfigFlag)
+	if path == "" {
+		path = filepath.Join(cCtx.String(gitRootFlag), FileName)
+		if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+			log.Debugf("No config file found at %q", path)
+			return nil
+		}
+	}
+
+	cfg, err := Load(path)
+	if err != nil {
+		return err
+	}
+
+	log.Debugf("Loaded config from %q", path)
+
+	if cCtx.App.Metadata == nil {
+		cCtx.App.Metadata = map[string]interface{}{}
+	}
+	cCtx.App.Metadata[metadataKey] = cfg
+
+	err = cfg.populate(cCtx, cCtx.Command.Name, cCtx.Command.Flags)
+	if err != nil {
+		return err
+	}
+
+	// Global flags belong to the app context, which is the parent of the command context, as cli.Context.Set does not
+	// look for flags in parent contexts.
+	const appContext = 1
+	lineage := cCtx.Lineage()
+	if len(lineage) <= appContext {
+		return nil
+	}
+
+	return cfg.populate(lineage[appContext], cCtx.Command.Name, cCtx.App.Flags)
+}
+
+// FromContext returns the config loaded by Apply, or an empty config if no config file was found.
+func FromContext(cCtx *cli.Context) *Config {
+	if cfg, isConfig := cCtx.App.Metadata[metadataKey].(*Config); isConfig {
+		return cfg
+	}
+
+	return &Config{}
+}
+
+// CommandContext returns a context for cmd, child of cCtx, whose flags take their values from their environment
+// variables, the config loaded by Apply in cCtx, or their defaults, as if cmd was run with no arguments. It allows
+// commands to read the options of others they call in process, and override them through their typed options.
+func CommandContext(cCtx *cli.Context, cmd *cli.Command) (*cli.Context, error) {
+	set := flag.NewFlagSet(cmd.Name, flag.ContinueOnError)
+	for _, f := range cmd.Flags {
+		if err := f.Apply(set); err != nil {
+			return nil, fmt.Errorf("applying flag %q of %s: %w", f.Names()[0], cmd.Name, err)
+		}
+	}
+
+	cmdCtx := cli.NewContext(cCtx.App, set, cCtx)
+	cmdCtx.Command = cmd
+
+	if err := FromContext(cCtx).populate(cmdCtx, cmd.Name, cmd.Flags); err != nil {
+		return nil, err
+	}
+
+	return cmdCtx, nil
+}
+
+// populate sets the flags that have a value in the config for the given command and have not been set already.
+func (c *Config) populate(cCtx *cli.Context, command string, flags []cli.Flag) error {
+	for _, flag := range flags {
+		name := flag.Names()[0]
+		if cCtx.IsSet(name) {
+			continue
+		}
+
+		value, found := c.value(command, name)
+		if !found {
+			continue
+		}
+
+		err := set(cCtx, name, value)
+		if err != nil {
+			return fmt.Errorf("setting %q from config: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+// value returns the value for a flag, looking in the section for the command first.
+func (c *Config) value(command, flag string) (interface{}, bool) {
+	if value, found := c.Commands[command][flag]; found {
+		return value, true
+	}
+
+	value, found := c.Flags[flag]
+	return value, found
+}
+
+func set(cCtx *cli.Context, name string, value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case []interface{}:
+		for _, item := range v {
+			if err := set(cCtx, name, item); err != nil {
+				return err
+			}
+		}
+		return nil
+	case map[string]interface{}:
+		return ErrInvalidValue
+	default:
+		log.Debugf("Setting %q to %v from config", name, v)
+		//nolint:wrapcheck // Wrapped by the caller.
+		return cCtx.Set(name, fmt.Sprint(v))
+	}
+}
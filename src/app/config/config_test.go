@@ -0,0 +1,218 @@
+package config_test
+
+import (
+	"errors"
+	"os"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/newrelic/release-toolkit/src/app/common"
+	"github.com/newrelic/release-toolkit/src/app/config"
+	"github.com/urfave/cli/v2"
+)
+
+const testEnvPrefix = "rt_config_test"
+
+// testApp returns an app with a global flag and a command whose flags are populated from config, and a map where
+// the command stores the values it received.
+func testApp() (*cli.App, map[string]string) {
+	values := map[string]string{}
+
+	return &cli.App{
+		Flags: []cli.Flag{
+			&cli.StringFlag{Name: common.ConfigFlag},
+			&cli.StringFlag{Name: common.YAMLFlag, Value: "changelog.yaml"},
+		},
+		Commands: []*cli.Command{
+			{
+				Name: "test",
+				Flags: []cli.Flag{
+					&cli.StringFlag{Name: "git-root", Value: "./"},
+					&cli.StringFlag{Name: "tag-prefix", EnvVars: common.EnvFor("tag-prefix", testEnvPrefix), Value: "default"},
+					&cli.StringFlag{Name: "bump-cap", Value: "major"},
+					&cli.StringSliceFlag{Name: "excluded-dirs"},
+				},
+				Before: config.Apply,
+				Action: func(cCtx *cli.Context) error {
+					values[common.YAMLFlag] = cCtx.String(common.YAMLFlag)
+					values["tag-prefix"] = cCtx.String("tag-prefix")
+					values["bump-cap"] = cCtx.String("bump-cap")
+					values["excluded-dirs"] = strings.Join(cCtx.StringSlice("excluded-dirs"), ",")
+					values["excluded-dependencies"] = strings.Join(config.FromContext(cCtx).ExcludedDependencies, ",")
+					return nil
+				},
+			},
+		},
+	}, values
+}
+
+const testConfig = `
+yaml: component/changelog.yaml
+tag-prefix: from-config
+bump-cap: major
+excluded-dirs:
+  - docs
+  - .github
+excluded-dependencies:
+  - golangci-lint
+commands:
+  test:
+    bump-cap: minor
+  other:
+    bump-cap: patch
+`
+
+//nolint:paralleltest // Tests set env vars.
+func TestApply(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		config   string
+		env      string
+		args     string
+		expected map[string]string
+	}{
+		{
+			name: "Defaults_Without_Config",
+			expected: map[string]string{
+				"yaml": "changelog.yaml", "tag-prefix": "default", "bump-cap": "major",
+				"excluded-dirs": "", "excluded-dependencies": "",
+			},
+		},
+		{
+			name:   "Config_Overrides_Defaults",
+			config: testConfig,
+			expected: map[string]string{
+				"yaml": "component/changelog.yaml", "tag-prefix": "from-config", "bump-cap": "minor",
+				"excluded-dirs": "docs,.github", "excluded-dependencies": "golangci-lint",
+			},
+		},
+		{
+			name:   "Env_Overrides_Config",
+			config: testConfig,
+			env:    "from-env",
+			expected: map[string]string{
+				"yaml": "component/changelog.yaml", "tag-prefix": "from-env", "bump-cap": "minor",
+				"excluded-dirs": "docs,.github", "excluded-dependencies": "golangci-lint",
+			},
+		},
+		{
+			name:   "Flags_Override_Env_And_Config",
+			config: testConfig,
+			env:    "from-env",
+			args:   "-tag-prefix from-flag -bump-cap patch -excluded-dirs src",
+			expected: map[string]string{
+				"yaml": "component/changelog.yaml", "tag-prefix": "from-flag", "bump-cap": "patch",
+				"excluded-dirs": "src", "excluded-dependencies": "golangci-lint",
+			},
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			tDir := t.TempDir()
+			if tc.config != "" {
+				if err := os.WriteFile(path.Join(tDir, config.FileName), []byte(tc.config), 0o600); err != nil {
+					t.Fatalf("Error writing config: %v", err)
+				}
+			}
+
+			t.Setenv(common.EnvFor("tag-prefix", testEnvPrefix)[0], tc.env)
+			if tc.env == "" {
+				_ = os.Unsetenv(common.EnvFor("tag-prefix", testEnvPrefix)[0])
+			}
+
+			app, values := testApp()
+			err := app.Run(strings.Fields("rt test -git-root " + tDir + " " + tc.args))
+			if err != nil {
+				t.Fatalf("Error running app: %v", err)
+			}
+
+			if diff := cmp.Diff(tc.expected, values); diff != "" {
+				t.Fatalf("Flag values are not as expected:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestApply_Config_Flag(t *testing.T) {
+	t.Parallel()
+
+	cfgPath := path.Join(t.TempDir(), "custom.yaml")
+	if err := os.WriteFile(cfgPath, []byte("tag-prefix: custom\n"), 0o600); err != nil {
+		t.Fatalf("Error writing config: %v", err)
+	}
+
+	app, values := testApp()
+	err := app.Run(strings.Fields("rt -config " + cfgPath + " test"))
+	if err != nil {
+		t.Fatalf("Error running app: %v", err)
+	}
+
+	if values["tag-prefix"] != "custom" {
+		t.Fatalf("Expected tag-prefix to be taken from config, got %q", values["tag-prefix"])
+	}
+
+	app, _ = testApp()
+	err = app.Run(strings.Fields("rt -config " + path.Join(t.TempDir(), "missing.yaml") + " test"))
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("Expected error for missing config file, got %v", err)
+	}
+}
+
+func TestApply_Invalid_Value(t *testing.T) {
+	t.Parallel()
+
+	cfgPath := path.Join(t.TempDir(), "custom.yaml")
+	if err := os.WriteFile(cfgPath, []byte("tag-prefix:\n  foo: bar\n"), 0o600); err != nil {
+		t.Fatalf("Error writing config: %v", err)
+	}
+
+	app, _ := testApp()
+	err := app.Run(strings.Fields("rt -config " + cfgPath + " test"))
+	if !errors.Is(err, config.ErrInvalidValue) {
+		t.Fatalf("Expected %v, got %v", config.ErrInvalidValue, err)
+	}
+}
+
+func TestCommandContext(t *testing.T) {
+	t.Parallel()
+
+	cfgPath := path.Join(t.TempDir(), "custom.yaml")
+	if err := os.WriteFile(cfgPath, []byte(testConfig), 0o600); err != nil {
+		t.Fatalf("Error writing config: %v", err)
+	}
+
+	other := &cli.Command{
+		Name: "other",
+		Flags: []cli.Flag{
+			&cli.StringFlag{Name: "bump-cap", Value: "major"},
+			&cli.StringFlag{Name: "scheme", Value: "semver"},
+		},
+	}
+
+	values := map[string]string{}
+	app, _ := testApp()
+	app.Commands[0].Action = func(cCtx *cli.Context) error {
+		otherCtx, err := config.CommandContext(cCtx, other)
+		if err != nil {
+			return err
+		}
+
+		values["yaml"] = otherCtx.String(common.YAMLFlag)
+		values["bump-cap"] = otherCtx.String("bump-cap")
+		values["scheme"] = otherCtx.String("scheme")
+		return nil
+	}
+
+	err := app.Run(strings.Fields("rt -config " + cfgPath + " test -bump-cap major"))
+	if err != nil {
+		t.Fatalf("Error running app: %v", err)
+	}
+
+	// Flags of the other command are taken from its section of the config, not from the running command.
+	expected := map[string]string{"yaml": "component/changelog.yaml", "bump-cap": "patch", "scheme": "semver"}
+	if diff := cmp.Diff(expected, values); diff != "" {
+		t.Fatalf("Flag values are not as expected:\n%s", diff)
+	}
+}
@@ -4,14 +4,21 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/newrelic/release-toolkit/src/app/common"
+	"github.com/newrelic/release-toolkit/src/app/config"
 	"github.com/newrelic/release-toolkit/src/app/gha"
 	"github.com/newrelic/release-toolkit/src/changelog"
+	"github.com/newrelic/release-toolkit/src/changelog/sources/conventional"
 	"github.com/newrelic/release-toolkit/src/changelog/sources/dependabot"
 	"github.com/newrelic/release-toolkit/src/changelog/sources/markdown"
 	"github.com/newrelic/release-toolkit/src/changelog/sources/renovate"
 	"github.com/newrelic/release-toolkit/src/git"
+	"github.com/newrelic/release-toolkit/src/monorepo"
+	"github.com/newrelic/release-toolkit/src/version"
+	log "github.com/sirupsen/logrus"
 	"github.com/urfave/cli/v2"
 	"gopkg.in/yaml.v3"
 )
@@ -20,7 +27,9 @@ const (
 	markdownPathFlag                 = "markdown"
 	renovateFlag                     = "renovate"
 	dependabotFlag                   = "dependabot"
+	conventionalCommitsFlag          = "conventional-commits"
 	tagPrefixFlag                    = "tag-prefix"
+	versionConstraintFlag            = "version-constraint"
 	gitRootFlag                      = "git-root"
 	includedDirsFlag                 = "included-dirs"
 	excludedDirsFlag                 = "excluded-dirs"
@@ -28,13 +37,33 @@ const (
 	excludedFilesFlag                = "excluded-files"
 	excludedDependenciesManifestFlag = "excluded-dependencies-manifest"
 	exitCodeFlag                     = "exit-code"
+	fromFlag                         = "from"
+	toFlag                           = "to"
+	sinceFlag                        = "since"
+	firstParentFlag                  = "first-parent"
+	allowShallowFlag                 = "allow-shallow"
 )
 
-const emptyChangelogOutput = "empty-changelog"
+// sinceLayouts are the formats accepted by sinceFlag.
+//
+//nolint:gochecknoglobals
+var sinceLayouts = []string{"2006-01-02", time.RFC3339}
+
+const (
+	emptyChangelogOutput    = "empty-changelog"
+	releaseComponentsOutput = "release-components"
+)
 
 // ErrNoSources is returned if Generate is invoked without any source enabled.
 var ErrNoSources = errors.New("cannot generate changelog yaml without at least one source enabled")
 
+// ErrSinceNotValid is returned if the value of --since is not a date in any of the supported formats.
+var ErrSinceNotValid = errors.New("since must be a date in YYYY-MM-DD or RFC3339 format")
+
+// ErrMonorepoIncludedDirs is returned if --included-dirs is used in monorepo mode, where the path of each component is
+// the only included dir.
+var ErrMonorepoIncludedDirs = errors.New("included dirs cannot be used with a monorepo manifest, as component paths are included instead")
+
 // Cmd is the cli.Command object for the generate-yaml command.
 //
 //nolint:gochecknoglobals // We could overengineer this to avoid the global command but I don't think it's worth it.
@@ -90,6 +119,12 @@ var Cmd = &cli.Command{
 			Usage:   "Gather changelog entries from dependabot commits since last tag",
 			Value:   true,
 		},
+		&cli.BoolFlag{
+			Name:    conventionalCommitsFlag,
+			EnvVars: common.EnvFor(conventionalCommitsFlag),
+			Usage:   "Gather changelog entries from commits following the Conventional Commits specification since last tag",
+			Value:   false,
+		},
 		// Flags for tag sources.
 		&cli.StringFlag{
 			Name:    tagPrefixFlag,
@@ -97,12 +132,51 @@ var Cmd = &cli.Command{
 			Usage:   "Find commits since latest tag matching this prefix.",
 			Value:   "",
 		},
+		&cli.StringFlag{
+			Name:    versionConstraintFlag,
+			EnvVars: common.EnvFor(versionConstraintFlag),
+			Usage: "Find commits since latest tag whose version satisfies this constraint (e.g. \"~1.4\" or \"<2.0.0\"). " +
+				"Useful on maintenance branches where tags from newer release lines are reachable.",
+			Value: "",
+		},
 		&cli.StringFlag{
 			Name:    gitRootFlag,
 			EnvVars: common.EnvFor(gitRootFlag),
 			Usage:   "Path to the git repo to get commits and tags for.",
 			Value:   "./",
 		},
+		// Flags for commit ranges.
+		&cli.StringFlag{
+			Name:    fromFlag,
+			EnvVars: common.EnvFor(fromFlag),
+			Usage: "Scan commits after this ref (commit hash, tag or branch) instead of the latest tag. " +
+				"The commit it points to is not included",
+		},
+		&cli.StringFlag{
+			Name:    toFlag,
+			EnvVars: common.EnvFor(toFlag),
+			Usage: "Scan commits up to this ref (commit hash, tag or branch) instead of HEAD. " +
+				"If --from is not set, commits are scanned since the latest tag reachable from this ref",
+		},
+		&cli.StringFlag{
+			Name:    sinceFlag,
+			EnvVars: common.EnvFor(sinceFlag),
+			Usage:   "Skip commits committed before this date, in YYYY-MM-DD or RFC3339 format",
+		},
+		&cli.BoolFlag{
+			Name:    firstParentFlag,
+			EnvVars: common.EnvFor(firstParentFlag),
+			Usage: "Follow only the first parent of merge commits, taking merge commits as the unit of change " +
+				"and the PR number from their message",
+			Value: false,
+		},
+		&cli.BoolFlag{
+			Name:    allowShallowFlag,
+			EnvVars: common.EnvFor(allowShallowFlag),
+			Usage: "If the repository is a shallow clone that does not reach the latest version, generate the changelog " +
+				"from the commits available and add a warning to its notes instead of failing",
+			Value: false,
+		},
 		&cli.IntFlag{
 			Name:    exitCodeFlag,
 			EnvVars: common.EnvFor(exitCodeFlag),
@@ -110,148 +184,463 @@ var Cmd = &cli.Command{
 			Value:   1,
 		},
 	},
+	Before: config.Apply,
 	Action: Generate,
 }
 
-type appendDepSrc func([]changelog.Source, git.TagsVersionGetter, git.CommitsGetter) []changelog.Source
+// Options holds the settings of generate-yaml, which are taken from its flags by OptionsFromCli.
+type Options struct {
+	// YAML is the path where the changelog.yaml is written.
+	YAML string
+	// Markdown is the path to the CHANGELOG.md entries are gathered from, if not empty.
+	Markdown     string
+	Renovate     bool
+	Dependabot   bool
+	Conventional bool
+
+	GitRoot    string
+	GitBackend git.Backend
+	TagPrefix  string
+	// VersionConstraint, if not empty, restricts the tags considered as the latest version.
+	VersionConstraint string
+
+	IncludedDirs                 []string
+	ExcludedDirs                 []string
+	IncludedFiles                []string
+	ExcludedFiles                []string
+	ExcludedDependenciesManifest string
+	// ExcludedDependencies are excluded in addition to the ones in ExcludedDependenciesManifest.
+	ExcludedDependencies []string
+	// Handles maps author emails to the GitHub handles entries from their commits credit them with.
+	Handles map[string]string
+
+	From         string
+	To           string
+	Since        string
+	FirstParent  bool
+	AllowShallow bool
+}
+
+// OptionsFromCli returns the Options set by the flags of generate-yaml, and by the config file, in cCtx.
+func OptionsFromCli(cCtx *cli.Context) Options {
+	cfg := config.FromContext(cCtx)
+
+	return Options{
+		YAML:                         cCtx.String(common.YAMLFlag),
+		Markdown:                     cCtx.String(markdownPathFlag),
+		Renovate:                     cCtx.Bool(renovateFlag),
+		Dependabot:                   cCtx.Bool(dependabotFlag),
+		Conventional:                 cCtx.Bool(conventionalCommitsFlag),
+		GitRoot:                      cCtx.String(gitRootFlag),
+		GitBackend:                   git.Backend(cCtx.String(common.GitBackendFlag)),
+		TagPrefix:                    cCtx.String(tagPrefixFlag),
+		VersionConstraint:            cCtx.String(versionConstraintFlag),
+		IncludedDirs:                 sanitizeValue(cCtx.StringSlice(includedDirsFlag)),
+		ExcludedDirs:                 sanitizeValue(cCtx.StringSlice(excludedDirsFlag)),
+		IncludedFiles:                sanitizeValue(cCtx.StringSlice(includedFilesFlag)),
+		ExcludedFiles:                sanitizeValue(cCtx.StringSlice(excludedFilesFlag)),
+		ExcludedDependenciesManifest: cCtx.String(excludedDependenciesManifestFlag),
+		ExcludedDependencies:         cfg.ExcludedDependencies,
+		Handles:                      cfg.Handles,
+		From:                         cCtx.String(fromFlag),
+		To:                           cCtx.String(toFlag),
+		Since:                        cCtx.String(sinceFlag),
+		FirstParent:                  cCtx.Bool(firstParentFlag),
+		AllowShallow:                 cCtx.Bool(allowShallowFlag),
+	}
+}
+
+// Run generates the changelog.yaml of a single repository as configured by opts, and returns it.
+func Run(opts Options) (*changelog.Changelog, error) {
+	gen, err := newGenerator(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return gen.generate(opts.YAML)
+}
 
 // Generate is a command that creates a changelog.yaml file.
-//
-//nolint:gocyclo,cyclop
+// If a monorepo manifest is supplied, a changelog.yaml file is created for each of its components instead.
 func Generate(cCtx *cli.Context) error {
+	opts := OptionsFromCli(cCtx)
+
+	if manifestPath := cCtx.String(common.MonorepoFlag); manifestPath != "" {
+		gen, err := newGenerator(opts)
+		if err != nil {
+			return err
+		}
+
+		return generateMonorepo(cCtx, gen, manifestPath)
+	}
+
 	gh := gha.NewFromCli(cCtx)
 
-	yamlPath := cCtx.String(common.YAMLFlag)
-	chFile, err := os.Create(yamlPath)
+	combinedChangelog, err := Run(opts)
 	if err != nil {
-		return fmt.Errorf("opening changelog file %q: %w", yamlPath, err)
+		return err
 	}
 
-	combinedChangelog := &changelog.Changelog{}
-	sources := make([]changelog.Source, 0)
+	gh.SetOutput(emptyChangelogOutput, combinedChangelog.Empty())
+
+	exitCode := cCtx.Int(exitCodeFlag)
+	if combinedChangelog.Empty() && exitCode != 0 {
+		return cli.Exit("changelog is empty", exitCode)
+	}
+
+	return nil
+}
+
+// generateMonorepo generates a changelog.yaml for each component in the manifest, restricting commits to the ones
+// changing files in the component path and versions to tags with the component prefix. Git history is read only once
+// and shared across components.
+// A summary file listing which components are empty, held, or should be released is written as well.
+func generateMonorepo(cCtx *cli.Context, gen generator, manifestPath string) error {
+	gh := gha.NewFromCli(cCtx)
+
+	if len(gen.includedDirs) > 0 {
+		return ErrMonorepoIncludedDirs
+	}
+
+	manifest, err := monorepo.Load(manifestPath)
+	if err != nil {
+		return fmt.Errorf("loading monorepo manifest: %w", err)
+	}
+
+	summary := &monorepo.Summary{}
+	allEmpty := true
+
+	for _, component := range manifest.Components {
+		componentGen := gen
+		componentGen.tagPrefix = component.TagPrefix
+		componentGen.includedDirs = []string{component.Path}
+		if componentGen.markdownPath != "" {
+			componentGen.markdownPath = component.Markdown
+		}
+
+		gh.Group(component.Name)
+		ch, cErr := componentGen.generate(component.YAML)
+		gh.EndGroup()
+		if cErr != nil {
+			return fmt.Errorf("generating changelog for component %q: %w", component.Name, cErr)
+		}
+
+		cs := summary.Component(component.Name)
+		cs.Empty = ch.Empty()
+		cs.Held = ch.Held
+		cs.Release = !cs.Empty && !cs.Held
+
+		log.Infof("Component %q: empty=%v held=%v", component.Name, cs.Empty, cs.Held)
+
+		allEmpty = allEmpty && ch.Empty()
+	}
+
+	if err = summary.Write(manifest.Summary); err != nil {
+		return fmt.Errorf("writing monorepo summary: %w", err)
+	}
 
-	var excludedDependencies []string
-	if excludedDependenciesPath := cCtx.String(excludedDependenciesManifestFlag); excludedDependenciesPath != "" {
-		excludedDependencies, err = loadExcludedDependencies(excludedDependenciesPath)
+	gh.SetOutput(emptyChangelogOutput, allEmpty)
+	gh.SetOutput(releaseComponentsOutput, strings.Join(summary.Releasing(), ","))
+
+	exitCode := cCtx.Int(exitCodeFlag)
+	if allEmpty && exitCode != 0 {
+		return cli.Exit("changelogs for all components are empty", exitCode)
+	}
+
+	return nil
+}
+
+// generator gathers changelog entries from the sources enabled by command line flags.
+type generator struct {
+	tagPrefix    string
+	markdownPath string
+	// constraint, if set, restricts the tags considered as the latest version.
+	constraint *version.Constraint
+
+	renovate     bool
+	dependabot   bool
+	conventional bool
+
+	includedDirs         []string
+	excludedDirs         []string
+	includedFiles        []string
+	excludedFiles        []string
+	excludedDependencies []string
+	// handles maps author emails to the GitHub handles entries from their commits credit them with.
+	handles map[string]string
+
+	// commitRange overrides the commits scanned by commit-based sources, which by default are the ones from HEAD
+	// until the latest tag.
+	commitRange git.Range
+	repo        *git.Repo
+	// allowShallow makes commit-based sources use all the commits available in a shallow clone, rather than failing,
+	// if the history does not reach the latest version.
+	allowShallow bool
+}
+
+func newGenerator(opts Options) (generator, error) {
+	var commitsOpts []git.CommitsOptionFunc
+	if opts.FirstParent {
+		commitsOpts = append(commitsOpts, git.FirstParent())
+	}
+
+	gen := generator{
+		tagPrefix:     opts.TagPrefix,
+		markdownPath:  opts.Markdown,
+		renovate:      opts.Renovate,
+		dependabot:    opts.Dependabot,
+		conventional:  opts.Conventional,
+		includedDirs:  opts.IncludedDirs,
+		excludedDirs:  opts.ExcludedDirs,
+		includedFiles: opts.IncludedFiles,
+		excludedFiles: opts.ExcludedFiles,
+		handles:       opts.Handles,
+		commitRange: git.Range{
+			From: opts.From,
+			To:   opts.To,
+		},
+		allowShallow: opts.AllowShallow,
+	}
+
+	// The repository is shared so the history and tags are read only once, no matter how many sources or components
+	// need them.
+	repo, err := git.NewRepoWithBackend(opts.GitRoot, opts.GitBackend, commitsOpts...)
+	if err != nil {
+		return generator{}, fmt.Errorf("opening repository: %w", err)
+	}
+	gen.repo = repo
+
+	if c := opts.VersionConstraint; c != "" {
+		gen.constraint, err = version.NewConstraint(c)
 		if err != nil {
-			return fmt.Errorf("excluding dependencies %q: %w", excludedDependenciesPath, err)
+			return generator{}, fmt.Errorf("parsing version constraint: %w", err)
 		}
 	}
 
-	if cCtx.Bool(renovateFlag) {
-		appendDep := func(sources []changelog.Source, tgv git.TagsVersionGetter, getter git.CommitsGetter) []changelog.Source {
-			return append(sources, renovate.NewSource(tgv, getter))
+	if since := opts.Since; since != "" {
+		sinceTime, err := parseSince(since)
+		if err != nil {
+			return generator{}, err
 		}
-		sources, err = addDepSource(cCtx, sources, appendDep, excludedDependencies)
+		gen.commitRange.Since = sinceTime
+	}
+
+	// Dependencies excluded in the config file are combined with the ones in the manifest.
+	gen.excludedDependencies = append(gen.excludedDependencies, opts.ExcludedDependencies...)
+
+	if excludedDependenciesPath := opts.ExcludedDependenciesManifest; excludedDependenciesPath != "" {
+		excludedDependencies, err := loadExcludedDependencies(excludedDependenciesPath)
 		if err != nil {
-			return fmt.Errorf("adding renovate source: %w", err)
+			return generator{}, fmt.Errorf("excluding dependencies %q: %w", excludedDependenciesPath, err)
 		}
+		gen.excludedDependencies = append(gen.excludedDependencies, excludedDependencies...)
+	}
+
+	return gen, nil
+}
+
+// generate gathers the changelog from all enabled sources and writes it to yamlPath.
+func (g generator) generate(yamlPath string) (*changelog.Changelog, error) {
+	chFile, err := os.Create(yamlPath)
+	if err != nil {
+		return nil, fmt.Errorf("opening changelog file %q: %w", yamlPath, err)
 	}
+	defer chFile.Close()
+
+	combinedChangelog := &changelog.Changelog{}
+	sources := make([]changelog.Source, 0)
+
+	// All commit-based sources share the same version getter and commits.
+	var tvg git.TagsVersionGetter
+	var commitsGetter git.CommitsGetter
+	var tolerant *git.ShallowTolerantCommitsGetter
+	var shallowErr *git.ShallowError
+	if g.renovate || g.dependabot || g.conventional {
+		var tagsSource *git.TagsSource
+		tagsSource, err = g.tagVersionGetter()
+		if err != nil {
+			return nil, err
+		}
+		tvg = tagsSource
 
-	if cCtx.Bool(dependabotFlag) {
-		appendDep := func(sources []changelog.Source, tgv git.TagsVersionGetter, getter git.CommitsGetter) []changelog.Source {
-			return append(sources, dependabot.NewSource(tgv, getter))
+		shallowErr, err = g.unversionedShallowErr(tagsSource)
+		if err != nil {
+			return nil, err
+		}
+		if shallowErr != nil && !g.allowShallow {
+			return nil, shallowErr
 		}
-		sources, err = addDepSource(cCtx, sources, appendDep, excludedDependencies)
+
+		commitsGetter, tolerant, err = g.commitsGetter()
 		if err != nil {
-			return fmt.Errorf("adding dependabot source: %w", err)
+			return nil, err
 		}
 	}
 
-	if mdPath := cCtx.String(markdownPathFlag); mdPath != "" {
+	if g.renovate {
+		sources = append(sources, renovate.NewSource(tvg, commitsGetter))
+	}
+
+	if g.dependabot {
+		sources = append(sources, dependabot.NewSource(tvg, commitsGetter))
+	}
+
+	if g.conventional {
+		sources = append(sources, conventional.NewSource(tvg, commitsGetter, conventional.AuthorHandles(g.handles)))
+	}
+
+	if g.markdownPath != "" {
 		var mdFile *os.File
-		mdFile, err = os.Open(mdPath)
+		mdFile, err = os.Open(g.markdownPath)
 		if err != nil {
-			return fmt.Errorf("opening %q: %w", mdPath, err)
+			return nil, fmt.Errorf("opening %q: %w", g.markdownPath, err)
 		}
+		defer mdFile.Close()
 
 		sources = append(sources, markdown.New(mdFile))
 	}
 
 	if len(sources) == 0 {
-		return ErrNoSources
+		return nil, ErrNoSources
 	}
 
 	for _, source := range sources {
 		var ch *changelog.Changelog
 		ch, err = source.Changelog()
 		if err != nil {
-			return fmt.Errorf("gathering changelog from source: %w", err)
+			return nil, fmt.Errorf("gathering changelog from source: %w", err)
 		}
 
 		combinedChangelog.Merge(ch)
 	}
 
-	err = yaml.NewEncoder(chFile).Encode(combinedChangelog)
-	if err != nil {
-		return fmt.Errorf("writing changelog to %q: %w", yamlPath, err)
+	if tolerant != nil && tolerant.ShallowErr() != nil {
+		shallowErr = tolerant.ShallowErr()
+	}
+	// The warning is not added to empty changelogs, so they are still reported as empty.
+	if shallowErr != nil && !combinedChangelog.Empty() {
+		combinedChangelog.Notes = strings.TrimSpace(shallowWarning(shallowErr) + "\n\n" + combinedChangelog.Notes)
 	}
 
-	gh.SetOutput(emptyChangelogOutput, combinedChangelog.Empty())
-
-	exitCode := cCtx.Int(exitCodeFlag)
-	if combinedChangelog.Empty() && exitCode != 0 {
-		return cli.Exit("changelog is empty", exitCode)
+	err = yaml.NewEncoder(chFile).Encode(combinedChangelog)
+	if err != nil {
+		return nil, fmt.Errorf("writing changelog to %q: %w", yamlPath, err)
 	}
 
-	return nil
+	return combinedChangelog, nil
 }
 
-func addDepSource(cCtx *cli.Context, sources []changelog.Source, appendDep appendDepSrc, excludedDeps []string) ([]changelog.Source, error) {
-	tvg, err := tagVersionGetter(cCtx)
-	if err != nil {
-		return nil, err
+// commitsGetter returns the CommitsGetter for commit-based sources, which returns commits since the last version or in
+// the commit range, filtered by the included and excluded dirs, files and dependencies.
+// If shallow clones are allowed, the ShallowTolerantCommitsGetter wrapped by it is returned as well.
+//
+//nolint:ireturn // Sources take the interface.
+func (g generator) commitsGetter() (git.CommitsGetter, *git.ShallowTolerantCommitsGetter, error) {
+	// Sources ask for commits since the last version, which the range overrides when set.
+	var commitsGetter git.CommitsGetter = g.repo
+	if g.commitRange != (git.Range{}) {
+		log.Debugf("Scanning commits in range %+v", g.commitRange)
+		commitsGetter = git.NewRangedCommitsGetter(g.repo, g.commitRange)
 	}
 
-	includedDirs := sanitizeValue(cCtx.StringSlice(includedDirsFlag))
-	excludedDirs := sanitizeValue(cCtx.StringSlice(excludedDirsFlag))
-	includedFiles := sanitizeValue(cCtx.StringSlice(includedFilesFlag))
-	excludedFiles := sanitizeValue(cCtx.StringSlice(excludedFilesFlag))
-
-	gitCommitGetter := git.NewRepoCommitsGetter(cCtx.String(gitRootFlag))
+	var tolerant *git.ShallowTolerantCommitsGetter
+	if g.allowShallow {
+		tolerant = git.NewShallowTolerantCommitsGetter(commitsGetter)
+		commitsGetter = tolerant
+	}
 
-	if len(includedDirs) > 0 || len(excludedDirs) > 0 || len(includedFiles) > 0 || len(excludedFiles) > 0 || len(excludedDeps) > 0 {
-		commitFilter, err := git.NewCommitFilter(gitCommitGetter,
-			git.IncludedDirs(includedDirs...),
-			git.ExcludedDirs(excludedDirs...),
-			git.IncludedFiles(includedFiles...),
-			git.ExcludedFiles(excludedFiles...),
-			git.ExcludedDependencies(excludedDeps...),
+	if len(g.includedDirs) > 0 || len(g.excludedDirs) > 0 || len(g.includedFiles) > 0 || len(g.excludedFiles) > 0 ||
+		len(g.excludedDependencies) > 0 {
+		commitFilter, err := git.NewCommitFilter(commitsGetter,
+			git.IncludedDirs(g.includedDirs...),
+			git.ExcludedDirs(g.excludedDirs...),
+			git.IncludedFiles(g.includedFiles...),
+			git.ExcludedFiles(g.excludedFiles...),
+			git.ExcludedDependencies(g.excludedDependencies...),
 		)
 		if err != nil {
-			return nil, fmt.Errorf("creating git commit filter: %w", err)
+			return nil, nil, fmt.Errorf("creating git commit filter: %w", err)
 		}
-		return appendDep(sources, tvg, commitFilter), nil
+		return commitFilter, tolerant, nil
+	}
+
+	return commitsGetter, tolerant, nil
+}
+
+// unversionedShallowErr returns a ShallowError if no previous version was found and the repository is a shallow clone,
+// as the version is likely beyond the fetched history or its tag was not fetched at all. It returns nil if the start
+// of the range is set, as the latest version is not needed then.
+func (g generator) unversionedShallowErr(tagsSource *git.TagsSource) (*git.ShallowError, error) {
+	if g.commitRange.From != "" {
+		return nil, nil
+	}
+
+	lastHash, err := tagsSource.LastVersionHash()
+	if err != nil {
+		return nil, fmt.Errorf("getting last version: %w", err)
+	}
+	if lastHash != "" {
+		return nil, nil
+	}
+
+	shallow, depth, err := g.repo.Shallow()
+	if err != nil {
+		return nil, fmt.Errorf("checking if repository is shallow: %w", err)
+	}
+	if !shallow {
+		return nil, nil
 	}
 
-	return appendDep(sources, tvg, gitCommitGetter), nil
+	return &git.ShallowError{Depth: depth}, nil
 }
 
-func tagVersionGetter(cCtx *cli.Context) (*git.TagsSource, error) {
-	workDir := cCtx.String(gitRootFlag)
-	commitsGetter := git.NewRepoCommitsGetter(workDir)
+// shallowWarning returns the note added to changelogs generated from an incomplete history.
+func shallowWarning(err *git.ShallowError) string {
+	return fmt.Sprintf(
+		"> **Warning**\n> This changelog was generated from a shallow clone with %d commits of history, "+
+			"which does not reach the previous version. It might be incomplete.",
+		err.Depth,
+	)
+}
 
-	tagOpts := []git.TagOptionFunc{git.TagsMatchingCommits(commitsGetter)}
-	if matching := cCtx.String(tagPrefixFlag); matching != "" {
-		tagOpts = append(tagOpts, git.TagsMatchingRegex("^"+matching))
+func (g generator) tagVersionGetter() (*git.TagsSource, error) {
+	// Only tags reachable from the end of the range are considered, so the default start of the range is the latest
+	// version released from the same branch.
+	var branchCommits git.CommitsGetter = g.repo
+	if g.commitRange.To != "" {
+		branchCommits = git.NewRangedCommitsGetter(g.repo, git.Range{To: g.commitRange.To})
 	}
 
-	src, err := git.NewRepoTagsGetter(workDir, tagOpts...)
+	tagOpts := []git.TagOptionFunc{git.TagsMatchingCommits(branchCommits)}
+	if g.tagPrefix != "" {
+		tagOpts = append(tagOpts, git.TagsMatchingRegex("^"+g.tagPrefix))
+	}
+
+	src, err := g.repo.TagsGetter(tagOpts...)
 	if err != nil {
 		return nil, fmt.Errorf("creating source for git tags: %w", err)
 	}
 
 	var versionOpts []git.TagSourceOptionFunc
-	if matching := cCtx.String(tagPrefixFlag); matching != "" {
-		versionOpts = append(versionOpts, git.TagSourceReplacing(matching, ""))
+	if g.tagPrefix != "" {
+		versionOpts = append(versionOpts, git.TagSourceReplacing(g.tagPrefix, ""))
+	}
+	if g.constraint != nil {
+		versionOpts = append(versionOpts, git.TagSourceConstraint(g.constraint))
 	}
 
-	tvsrc := git.NewTagsSource(src, versionOpts...)
-	if err != nil {
-		return nil, fmt.Errorf("creating version source from git tag source: %w", err)
+	return git.NewTagsSource(src, versionOpts...), nil
+}
+
+// parseSince parses the value of sinceFlag in any of sinceLayouts.
+func parseSince(since string) (time.Time, error) {
+	for _, layout := range sinceLayouts {
+		if t, err := time.Parse(layout, since); err == nil {
+			return t, nil
+		}
 	}
 
-	return tvsrc, nil
+	return time.Time{}, fmt.Errorf("parsing %q: %w", since, ErrSinceNotValid)
 }
 
 func sanitizeValue(in []string) []string {
@@ -1,6 +1,7 @@
 package generate_test
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
@@ -11,6 +12,8 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/newrelic/release-toolkit/src/app"
+	"github.com/newrelic/release-toolkit/src/app/common"
+	"github.com/newrelic/release-toolkit/src/app/generate"
 	"github.com/newrelic/release-toolkit/src/git"
 )
 
@@ -36,6 +39,10 @@ This is a release note
 
 //nolint:funlen,paralleltest,maintidx
 func TestGenerate(t *testing.T) {
+	// Workflow commands are asserted on stdout, so environment files set when running on Github Actions are ignored.
+	t.Setenv(common.GHAOutputEnv, "")
+	t.Setenv(common.GHAStepSummaryEnv, "")
+
 	for _, tc := range []struct {
 		name           string
 		commits        []string
@@ -185,6 +192,42 @@ dependencies:
         commit: chore(deps): update helm release common-library to v1.0.4 (#401)
 			`) + "\n",
 		},
+		{
+			name:   "Markdown_Conventional_Commits",
+			md:     mdChangelog,
+			args:   "--renovate=false --dependabot=false --conventional-commits",
+			author: "Jane Doe <jane@example.com>",
+			commits: []string{
+				"feat(api): support pagination (#12)",
+				"docs: update readme",
+				"fix: do not panic on empty input",
+			},
+			expected: strings.TrimSpace(`
+notes: |-
+    ### Important announcement (note)
+    This is a release note
+changes:
+    - type: enhancement
+      message: support pagination
+      meta:
+        authors:
+            - Jane Doe
+        pr: "12"
+        commit: feat(api): support pagination (#12)
+        scope: api
+    - type: bugfix
+      message: do not panic on empty input
+      meta:
+        authors:
+            - Jane Doe
+        commit: fix: do not panic on empty input
+    - type: breaking
+      message: Support has been removed
+    - type: security
+      message: Fixed a security issue that leaked all data
+dependencies: []
+			`) + "\n",
+		},
 		{
 			name:   "Markdown_Renovate_Filter_IncludedDirs_notIncluded",
 			md:     mdChangelog,
@@ -532,23 +575,590 @@ func calculateHashes(t *testing.T, repoPath string, yaml string) string {
 	return output.String()
 }
 
-// hashFor is a helper that returns the hash of a commit given its message.
+// hashFor is a helper that returns the hash of a commit given its message, looking for it in all branches.
 func hashFor(t *testing.T, repoPath string, message string) string {
 	t.Helper()
 
 	commitsGetter := git.NewRepoCommitsGetter(repoPath)
 
-	commits, err := commitsGetter.Commits("")
+	branches, err := exec.Command("git", "-C", repoPath, "branch", "--format=%(refname:short)").Output()
 	if err != nil {
-		t.Fatalf("Internal error resolving hashes: fetching commits: %v", err)
+		t.Fatalf("Internal error resolving hashes: listing branches: %v", err)
 	}
 
-	for _, c := range commits {
-		if c.Message == message {
-			return c.Hash
+	for _, branch := range strings.Fields(string(branches)) {
+		commits, err := commitsGetter.CommitsInRange(git.Range{To: branch})
+		if err != nil {
+			t.Fatalf("Internal error resolving hashes: fetching commits: %v", err)
+		}
+
+		for _, c := range commits {
+			if c.Message == message {
+				return c.Hash
+			}
 		}
 	}
 
 	t.Fatalf("Internal error resolving hashes: Could not find hash for commit %q", message)
 	return ""
 }
+
+//nolint:paralleltest
+func TestGenerate_Monorepo(t *testing.T) {
+	tDir := t.TempDir()
+
+	for _, cmdline := range []string{
+		"git init",
+		"git config user.email test@user.tld",
+		"git config user.name Test",
+		"git config commit.gpgsign false",
+		"mkdir agent operator",
+		"touch agent/main.go operator/main.go",
+		"git add .",
+		"git commit -m initial",
+		"git tag agent-v1.0.0",
+		"git tag operator-v0.4.0",
+		"touch agent/go.mod",
+		"git add agent/go.mod",
+		"git commit --author 'renovate[bot] <renovate@whitesourcesoftware.com>' -m 'chore(deps): update module github.com/foo/bar to v1.2.3'",
+		"touch operator/go.mod",
+		"git add operator/go.mod",
+		"git commit --author 'renovate[bot] <renovate@whitesourcesoftware.com>' -m 'chore(deps): update module github.com/foo/baz to v0.2.0'",
+		"git tag operator-v0.4.1",
+	} {
+		cmd := exec.Command("/bin/bash", "-c", cmdline)
+		cmd.Dir = tDir
+
+		out := strings.Builder{}
+		cmd.Stdout = &out
+		cmd.Stderr = &out
+		if err := cmd.Run(); err != nil {
+			t.Fatalf("Error bootstrapping test git repo: %s: %v\n%s", cmdline, err, out.String())
+		}
+	}
+
+	emptyMd := "# Changelog\n\n## Unreleased\n\n## v0.0.1 - 2022-01-01\n"
+	for _, file := range []string{"agent/CHANGELOG.md", "operator/CHANGELOG.md"} {
+		if err := os.WriteFile(path.Join(tDir, file), []byte(emptyMd), 0o600); err != nil {
+			t.Fatalf("Error creating test markdown: %v", err)
+		}
+	}
+
+	manifestPath := path.Join(tDir, "monorepo.yaml")
+	manifest := strings.TrimSpace(`
+components:
+- name: agent
+  path: agent
+  tag-prefix: agent-
+- name: operator
+  path: operator
+  tag-prefix: operator-
+	`)
+	if err := os.WriteFile(manifestPath, []byte(manifest), 0o600); err != nil {
+		t.Fatalf("Error creating manifest: %v", err)
+	}
+
+	outputPath := path.Join(t.TempDir(), "github-output")
+	if err := os.WriteFile(outputPath, nil, 0o600); err != nil {
+		t.Fatalf("Error creating output file: %v", err)
+	}
+	t.Setenv(common.GHAOutputEnv, outputPath)
+
+	app := app.App()
+	buf := &strings.Builder{}
+	app.Writer = buf
+
+	err := app.Run(strings.Fields(fmt.Sprintf("rt --gha --monorepo %s generate-yaml -git-root %s --dependabot=false", manifestPath, tDir)))
+	if err != nil {
+		t.Fatalf("Error running app: %v", err)
+	}
+
+	agentYaml, err := os.ReadFile(path.Join(tDir, "agent", "changelog.yaml"))
+	if err != nil {
+		t.Fatalf("Error reading agent changelog: %v", err)
+	}
+	expectedAgent := calculateHashes(t, tDir, strings.TrimSpace(`
+notes: ""
+changes: []
+dependencies:
+    - name: github.com/foo/bar
+      to: v1.2.3
+      meta:
+        commit: chore(deps): update module github.com/foo/bar to v1.2.3
+	`))
+	if diff := cmp.Diff(expectedAgent, string(agentYaml)); diff != "" {
+		t.Fatalf("Agent YAML is not as expected:\n%s", diff)
+	}
+
+	operatorYaml, err := os.ReadFile(path.Join(tDir, "operator", "changelog.yaml"))
+	if err != nil {
+		t.Fatalf("Error reading operator changelog: %v", err)
+	}
+	expectedOperator := "notes: \"\"\nchanges: []\ndependencies: []\n"
+	if diff := cmp.Diff(expectedOperator, string(operatorYaml)); diff != "" {
+		t.Fatalf("Operator YAML is not as expected:\n%s", diff)
+	}
+
+	summary, err := os.ReadFile(path.Join(tDir, "release-summary.yaml"))
+	if err != nil {
+		t.Fatalf("Error reading summary: %v", err)
+	}
+	expectedSummary := strings.TrimSpace(`
+components:
+    - name: agent
+      empty: false
+      held: false
+      release: true
+    - name: operator
+      empty: true
+      held: false
+      release: false
+	`) + "\n"
+	if diff := cmp.Diff(expectedSummary, string(summary)); diff != "" {
+		t.Fatalf("Summary is not as expected:\n%s", diff)
+	}
+
+	expectedStdout := "::group::agent\n::endgroup::\n::group::operator\n::endgroup::\n"
+	if actual := buf.String(); actual != expectedStdout {
+		t.Fatalf("Expected %q, app printed: %q", expectedStdout, actual)
+	}
+
+	output, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("Error reading outputs: %v", err)
+	}
+	if expected, actual := "empty-changelog=false\nrelease-components=agent\n", string(output); actual != expected {
+		t.Fatalf("Expected outputs %q, got %q", expected, actual)
+	}
+
+	err = app.Run(strings.Fields(fmt.Sprintf("rt --monorepo %s generate-yaml -git-root %s -included-dirs agent", manifestPath, tDir)))
+	if !errors.Is(err, generate.ErrMonorepoIncludedDirs) {
+		t.Fatalf("Expected %v, got %v", generate.ErrMonorepoIncludedDirs, err)
+	}
+}
+
+//nolint:paralleltest,funlen
+func TestGenerate_Range(t *testing.T) {
+	tDir := t.TempDir()
+
+	const renovate = "--author 'renovate[bot] <renovate@whitesourcesoftware.com>'"
+	for _, cmdline := range []string{
+		"git init --initial-branch master",
+		"git config user.email test@user.tld",
+		"git config user.name Test",
+		"git config commit.gpgsign false",
+		"touch a",
+		"git add a",
+		"git commit -m initial",
+		"git tag v1.4.0",
+		"git branch release/1.4",
+		"touch b",
+		"git add b",
+		"git commit " + renovate + " -m 'chore(deps): update module github.com/foo/foo to v2.0.0'",
+		"git tag v1.5.0",
+		"git checkout release/1.4",
+		"touch c",
+		"git add c",
+		"git commit " + renovate + " -m 'chore(deps): update module github.com/foo/foo to v1.1.0'",
+		"git tag -a v1.4.1 -m v1.4.1",
+		"touch d",
+		"git add d",
+		"git commit " + renovate + " -m 'chore(deps): update module github.com/foo/bar to v1.0.1'",
+		"git checkout master",
+	} {
+		cmd := exec.Command("/bin/bash", "-c", cmdline)
+		cmd.Dir = tDir
+
+		out := strings.Builder{}
+		cmd.Stdout = &out
+		cmd.Stderr = &out
+		if err := cmd.Run(); err != nil {
+			t.Fatalf("Error bootstrapping test git repo: %s: %v\n%s", cmdline, err, out.String())
+		}
+	}
+
+	mdPath := path.Join(tDir, "CHANGELOG.md")
+	if err := os.WriteFile(mdPath, []byte("# Changelog\n\n## Unreleased\n\n## v1.4.0 - 2022-01-01\n"), 0o600); err != nil {
+		t.Fatalf("Error creating test markdown: %v", err)
+	}
+
+	for _, tc := range []struct {
+		name        string
+		args        string
+		expected    string
+		expectedErr error
+	}{
+		{
+			name: "Latest_Tag_In_Branch",
+			args: "-to release/1.4",
+			expected: `
+notes: ""
+changes: []
+dependencies:
+    - name: github.com/foo/bar
+      to: v1.0.1
+      meta:
+        commit: chore(deps): update module github.com/foo/bar to v1.0.1
+`,
+		},
+		{
+			name: "From_And_To",
+			args: "-from v1.4.0 -to release/1.4",
+			expected: `
+notes: ""
+changes: []
+dependencies:
+    - name: github.com/foo/foo
+      to: v1.1.0
+      meta:
+        commit: chore(deps): update module github.com/foo/foo to v1.1.0
+    - name: github.com/foo/bar
+      to: v1.0.1
+      meta:
+        commit: chore(deps): update module github.com/foo/bar to v1.0.1
+`,
+		},
+		{
+			name: "From_Overrides_Latest_Tag",
+			args: "-from v1.4.0",
+			expected: `
+notes: ""
+changes: []
+dependencies:
+    - name: github.com/foo/foo
+      to: v2.0.0
+      meta:
+        commit: chore(deps): update module github.com/foo/foo to v2.0.0
+`,
+		},
+		{
+			name:     "Since_Future_Date",
+			args:     "-from v1.4.0 -since 2999-01-01",
+			expected: "notes: \"\"\nchanges: []\ndependencies: []",
+		},
+		{
+			name:        "Invalid_Since",
+			args:        "-since yesterday",
+			expectedErr: generate.ErrSinceNotValid,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			app := app.App()
+			app.Writer = &strings.Builder{}
+
+			yamlPath := path.Join(t.TempDir(), "changelog.yaml")
+			err := app.Run(strings.Fields(fmt.Sprintf(
+				"rt --yaml %s generate-yaml -git-root %s -markdown %s -dependabot=false -exit-code 0 %s",
+				yamlPath, tDir, mdPath, tc.args,
+			)))
+			if tc.expectedErr != nil {
+				if !errors.Is(err, tc.expectedErr) {
+					t.Fatalf("Expected error %v, got %v", tc.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Error running app: %v", err)
+			}
+
+			yaml, err := os.ReadFile(yamlPath)
+			if err != nil {
+				t.Fatalf("Error reading file created by command: %v", err)
+			}
+
+			expected := calculateHashes(t, tDir, tc.expected)
+			if diff := cmp.Diff(expected, string(yaml)); diff != "" {
+				t.Fatalf("Output YAML is not as expected:\n%s", diff)
+			}
+		})
+	}
+}
+
+//nolint:paralleltest
+func TestGenerate_First_Parent(t *testing.T) {
+	tDir := t.TempDir()
+
+	for _, cmdline := range []string{
+		"git init --initial-branch master",
+		"git config user.email test@user.tld",
+		"git config user.name Test",
+		"git config commit.gpgsign false",
+		"git commit --allow-empty -m initial",
+		"git tag v1.0.0",
+		"git checkout -b renovate/bar",
+		"touch go.mod",
+		"git add go.mod",
+		"git commit --author 'renovate[bot] <bot@renovateapp.com>' -m 'chore(deps): update module github.com/foo/bar to v1.2.3'",
+		"git checkout master",
+		"git merge --no-ff renovate/bar -m 'Merge pull request #42 from renovate/bar' -m 'chore(deps): update module github.com/foo/bar to v1.2.3'",
+	} {
+		cmd := exec.Command("/bin/bash", "-c", cmdline)
+		cmd.Dir = tDir
+
+		out := strings.Builder{}
+		cmd.Stdout = &out
+		cmd.Stderr = &out
+		if err := cmd.Run(); err != nil {
+			t.Fatalf("Error bootstrapping test git repo: %s: %v\n%s", cmdline, err, out.String())
+		}
+	}
+
+	mdPath := path.Join(tDir, "CHANGELOG.md")
+	if err := os.WriteFile(mdPath, []byte("# Changelog\n\n## Unreleased\n\n## v1.0.0 - 2022-01-01\n"), 0o600); err != nil {
+		t.Fatalf("Error creating test markdown: %v", err)
+	}
+
+	mergeHash, err := exec.Command("git", "-C", tDir, "rev-parse", "HEAD").Output()
+	if err != nil {
+		t.Fatalf("Error getting merge commit hash: %v", err)
+	}
+
+	app := app.App()
+	app.Writer = &strings.Builder{}
+
+	yamlPath := path.Join(tDir, "changelog.yaml")
+	err = app.Run(strings.Fields(fmt.Sprintf(
+		"rt --yaml %s generate-yaml -git-root %s -markdown %s -dependabot=false -first-parent", yamlPath, tDir, mdPath,
+	)))
+	if err != nil {
+		t.Fatalf("Error running app: %v", err)
+	}
+
+	yaml, err := os.ReadFile(yamlPath)
+	if err != nil {
+		t.Fatalf("Error reading file created by command: %v", err)
+	}
+
+	expected := strings.TrimSpace(`
+notes: ""
+changes: []
+dependencies:
+    - name: github.com/foo/bar
+      to: v1.2.3
+      meta:
+        pr: "42"
+        commit: `+strings.TrimSpace(string(mergeHash))) + "\n"
+	if diff := cmp.Diff(expected, string(yaml)); diff != "" {
+		t.Fatalf("Output YAML is not as expected:\n%s", diff)
+	}
+}
+
+//nolint:paralleltest // urfave/cli cannot be tested concurrently.
+func TestGenerate_Shallow(t *testing.T) {
+	origin := repoWithCommits(t, "renovate[bot] <bot@renovateapp.com>",
+		"chore(deps): update module github.com/foo/bar to v1.2.3 (#1)",
+		"chore(deps): update module github.com/foo/baz to v2.0.0 (#2)",
+		"chore(deps): update module github.com/foo/qux to v3.1.0 (#3)",
+	)
+
+	// The tagged commit is beyond the boundary of the clone, so the tag is not fetched either.
+	clone := path.Join(t.TempDir(), "clone")
+	if out, err := exec.Command("git", "clone", "--quiet", "--depth", "2", "file://"+origin, clone).CombinedOutput(); err != nil {
+		t.Fatalf("Error cloning test repo: %v\n%s", err, out)
+	}
+
+	yamlPath := path.Join(clone, "changelog.yaml")
+	cmdline := fmt.Sprintf("rt --yaml %s generate-yaml -git-root %s -markdown= -dependabot=false", yamlPath, clone)
+
+	err := app.App().Run(strings.Fields(cmdline))
+	if !errors.Is(err, git.ErrShallow) {
+		t.Fatalf("Expected a shallow clone error, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "greater than 2") {
+		t.Fatalf("Expected the error to tell the depth to fetch, got %v", err)
+	}
+
+	err = app.App().Run(strings.Fields(cmdline + " -allow-shallow"))
+	if err != nil {
+		t.Fatalf("Error running app: %v", err)
+	}
+
+	yaml, err := os.ReadFile(yamlPath)
+	if err != nil {
+		t.Fatalf("Error reading file created by command: %v", err)
+	}
+
+	expected := strings.TrimSpace(`
+notes: |-
+    > **Warning**
+    > This changelog was generated from a shallow clone with 2 commits of history, which does not reach the previous version. It might be incomplete.
+changes: []
+dependencies:
+    - name: github.com/foo/baz
+      to: v2.0.0
+      meta:
+        pr: "2"
+        commit: chore(deps): update module github.com/foo/baz to v2.0.0 (#2)
+    - name: github.com/foo/qux
+      to: v3.1.0
+      meta:
+        pr: "3"
+        commit: chore(deps): update module github.com/foo/qux to v3.1.0 (#3)
+`) + "\n"
+	if diff := cmp.Diff(calculateHashes(t, clone, expected), string(yaml)); diff != "" {
+		t.Fatalf("Output YAML is not as expected:\n%s", diff)
+	}
+}
+
+//nolint:paralleltest // urfave/cli cannot be tested concurrently.
+func TestGenerate_Git_Backend(t *testing.T) {
+	repodir := repoWithCommits(t, "renovate[bot] <bot@renovateapp.com>",
+		"chore(deps): update module github.com/foo/bar to v1.2.3 (#1)",
+		"chore(deps): update module github.com/foo/baz to v2.0.0 (#2)",
+	)
+
+	outputs := map[string]string{}
+	for _, backend := range []string{"native", "exec"} {
+		yamlPath := path.Join(t.TempDir(), "changelog.yaml")
+		err := app.App().Run(strings.Fields(fmt.Sprintf(
+			"rt --yaml %s --git-backend %s generate-yaml -git-root %s -markdown= -dependabot=false", yamlPath, backend, repodir,
+		)))
+		if err != nil {
+			t.Fatalf("Error running app with %s backend: %v", backend, err)
+		}
+
+		yaml, err := os.ReadFile(yamlPath)
+		if err != nil {
+			t.Fatalf("Error reading file created by command: %v", err)
+		}
+		outputs[backend] = string(yaml)
+	}
+
+	if !strings.Contains(outputs["native"], "github.com/foo/baz") {
+		t.Fatalf("Unexpected changelog:\n%s", outputs["native"])
+	}
+	if diff := cmp.Diff(outputs["native"], outputs["exec"]); diff != "" {
+		t.Fatalf("Backends generated different changelogs:\n%s", diff)
+	}
+
+	err := app.App().Run(strings.Fields(fmt.Sprintf(
+		"rt --yaml %s --git-backend libgit2 generate-yaml -git-root %s", path.Join(t.TempDir(), "changelog.yaml"), repodir,
+	)))
+	if !errors.Is(err, git.ErrUnknownBackend) {
+		t.Fatalf("Expected an unknown backend error, got %v", err)
+	}
+}
+
+//nolint:paralleltest // urfave/cli cannot be tested concurrently.
+func TestGenerate_Version_Constraint(t *testing.T) {
+	tDir := t.TempDir()
+
+	// release/1.x merges main after v2.3.0 is tagged there, so v2.3.0 is reachable from the maintenance branch.
+	for _, cmdline := range []string{
+		"git init --initial-branch main",
+		"git config user.email test@user.tld",
+		"git config user.name Test",
+		"git config commit.gpgsign false",
+		"git commit --allow-empty -m initial",
+		"git tag v1.4.0",
+		"git checkout -b release/1.x",
+		"git checkout main",
+		"git commit --allow-empty -m 'feat: main feature'",
+		"git tag v2.3.0",
+		"git checkout release/1.x",
+		"git merge --no-ff main -m 'Merge main'",
+		"git commit --allow-empty -m 'fix: maintenance fix'",
+	} {
+		cmd := exec.Command("/bin/bash", "-c", cmdline)
+		cmd.Dir = tDir
+
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("Error bootstrapping test git repo: %s: %v\n%s", cmdline, err, out)
+		}
+	}
+
+	for _, tc := range []struct {
+		name       string
+		args       string
+		expected   []string
+		unexpected []string
+	}{
+		{
+			name:       "Without_Constraint",
+			expected:   []string{"maintenance fix"},
+			unexpected: []string{"main feature"},
+		},
+		{
+			name:     "Since_Version_Matching_Constraint",
+			args:     "-version-constraint ~1.4",
+			expected: []string{"maintenance fix", "main feature"},
+		},
+	} {
+		tc := tc
+		//nolint:paralleltest // urfave/cli cannot be tested concurrently.
+		t.Run(tc.name, func(t *testing.T) {
+			yamlPath := path.Join(t.TempDir(), "changelog.yaml")
+			err := app.App().Run(strings.Fields(fmt.Sprintf(
+				"rt --yaml %s generate-yaml -git-root %s -markdown= -dependabot=false -renovate=false -conventional-commits %s",
+				yamlPath, tDir, tc.args,
+			)))
+			if err != nil {
+				t.Fatalf("Error running app: %v", err)
+			}
+
+			yaml, err := os.ReadFile(yamlPath)
+			if err != nil {
+				t.Fatalf("Error reading file created by command: %v", err)
+			}
+
+			for _, e := range tc.expected {
+				if !strings.Contains(string(yaml), e) {
+					t.Fatalf("Expected changelog to contain %q:\n%s", e, yaml)
+				}
+			}
+			for _, u := range tc.unexpected {
+				if strings.Contains(string(yaml), u) {
+					t.Fatalf("Expected changelog not to contain %q:\n%s", u, yaml)
+				}
+			}
+		})
+	}
+}
+
+//nolint:paralleltest // urfave/cli cannot be tested concurrently.
+func TestGenerate_Authors(t *testing.T) {
+	tDir := t.TempDir()
+
+	for _, cmdline := range []string{
+		"git init --initial-branch master",
+		"git config user.email test@user.tld",
+		"git config user.name Test",
+		"git config commit.gpgsign false",
+		"git commit --allow-empty -m initial",
+		"git tag v1.0.0",
+		"echo 'Jane Doe <jane@example.com> <jane@old.example.com>' > .mailmap",
+		"git commit --allow-empty --author 'jdoe <jane@old.example.com>' " +
+			"-m 'feat: pair programmed' -m 'Co-authored-by: John <john@example.com>\nCo-authored-by: Bob <bob@example.com>'",
+	} {
+		cmd := exec.Command("/bin/bash", "-c", cmdline)
+		cmd.Dir = tDir
+
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("Error bootstrapping test git repo: %s: %v\n%s", cmdline, err, out)
+		}
+	}
+
+	cfg := "handles:\n  jane@example.com: janedoe\n  Bob@Example.com: '@bob'\n"
+	if err := os.WriteFile(path.Join(tDir, ".release-toolkit.yaml"), []byte(cfg), 0o600); err != nil {
+		t.Fatalf("Error writing config file: %v", err)
+	}
+
+	yamlPath := path.Join(t.TempDir(), "changelog.yaml")
+	err := app.App().Run(strings.Fields(fmt.Sprintf(
+		"rt --yaml %s generate-yaml -git-root %s -markdown= -dependabot=false -renovate=false -conventional-commits",
+		yamlPath, tDir,
+	)))
+	if err != nil {
+		t.Fatalf("Error running app: %v", err)
+	}
+
+	yaml, err := os.ReadFile(yamlPath)
+	if err != nil {
+		t.Fatalf("Error reading file created by command: %v", err)
+	}
+
+	expected := "      meta:\n        authors:\n            - '@janedoe'\n            - John\n            - '@bob'\n"
+	if !strings.Contains(string(yaml), expected) {
+		t.Fatalf("Expected authors to be mapped to handles and names:\n%s", yaml)
+	}
+}
@@ -3,23 +3,61 @@
 package gha
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
 	"io"
+	"os"
+	"strings"
 
 	"github.com/newrelic/release-toolkit/src/app/common"
+	log "github.com/sirupsen/logrus"
 	"github.com/urfave/cli/v2"
 )
 
+// envFilePermissions are the permissions used to create environment files if they do not exist, which should not
+// happen when running on Github Actions.
+const envFilePermissions = 0o644
+
+// Option modifies the behavior of a Github object.
+type Option func(g *Github)
+
+// OutputFile makes Github write outputs to the specified file, as Github Actions expects for the file pointed to by
+// $GITHUB_OUTPUT. If path is empty, outputs are printed to the writer using the deprecated `set-output` command.
+func OutputFile(path string) Option {
+	return func(g *Github) {
+		g.outputFile = path
+	}
+}
+
+// StepSummaryFile makes Github append step summaries to the specified file, as Github Actions expects for the file
+// pointed to by $GITHUB_STEP_SUMMARY. If path is empty, summaries are printed to the writer.
+func StepSummaryFile(path string) Option {
+	return func(g *Github) {
+		g.summaryFile = path
+	}
+}
+
 // New creates a Github object that will print commands to the specified writer.
-func New(writer io.Writer) Github {
-	return Github{w: writer}
+func New(writer io.Writer, opts ...Option) Github {
+	g := Github{w: writer}
+	for _, opt := range opts {
+		opt(&g)
+	}
+
+	return g
 }
 
 // NewFromCli takes a cli.Context and looks for common.GHAFlag on it. If it is set, it returns a Github object that
-// writes to the app's Writer. If it is not, it returns an empty Github object that does not write anything.
+// writes to the app's Writer and to the environment files Github Actions points to, if any. If it is not, it returns
+// an empty Github object that does not write anything.
 func NewFromCli(cCtx *cli.Context) Github {
 	if cCtx.Bool(common.GHAFlag) {
-		return New(cCtx.App.Writer)
+		return New(
+			cCtx.App.Writer,
+			OutputFile(os.Getenv(common.GHAOutputEnv)),
+			StepSummaryFile(os.Getenv(common.GHAStepSummaryEnv)),
+		)
 	}
 
 	return New(io.Discard)
@@ -27,11 +65,146 @@ func NewFromCli(cCtx *cli.Context) Github {
 
 // Github is an object that output workflow commands.
 type Github struct {
-	w io.Writer
+	w           io.Writer
+	outputFile  string
+	summaryFile string
 }
 
-// SetOutput outputs the `set-output` command.
+// SetOutput sets an output parameter for the step. Values spanning multiple lines are supported.
+// If no output file is configured or it cannot be written, the `set-output` command is printed instead.
 // https://docs.github.com/en/actions/using-workflows/workflow-commands-for-github-actions#setting-an-output-parameter
 func (g Github) SetOutput(name string, value interface{}) {
-	_, _ = fmt.Fprintf(g.w, "::set-output name=%s::%v\n", name, value)
+	strValue := fmt.Sprint(value)
+
+	if g.outputFile != "" {
+		err := appendTo(g.outputFile, keyValue(name, strValue))
+		if err == nil {
+			return
+		}
+
+		log.Warnf("Could not write output %q to %q, printing it instead: %v", name, g.outputFile, err)
+	}
+
+	_, _ = fmt.Fprintf(g.w, "::set-output name=%s::%s\n", name, escapeData(strValue))
+}
+
+// AddSummary appends markdown to the summary of the job.
+// If no summary file is configured or it cannot be written, the markdown is printed instead.
+// https://docs.github.com/en/actions/using-workflows/workflow-commands-for-github-actions#adding-a-job-summary
+func (g Github) AddSummary(markdown string) {
+	markdown = strings.TrimSpace(markdown) + "\n"
+
+	if g.summaryFile != "" {
+		err := appendTo(g.summaryFile, markdown)
+		if err == nil {
+			return
+		}
+
+		log.Warnf("Could not write step summary to %q, printing it instead: %v", g.summaryFile, err)
+	}
+
+	_, _ = fmt.Fprint(g.w, markdown)
+}
+
+// Annotation holds the location an annotation refers to. All fields are optional.
+type Annotation struct {
+	Title string
+	File  string
+	// Line and EndLine are 1-based, and ignored if zero.
+	Line    int
+	EndLine int
+}
+
+// Warning prints the `warning` command, which creates a warning annotation.
+// https://docs.github.com/en/actions/using-workflows/workflow-commands-for-github-actions#setting-a-warning-message
+func (g Github) Warning(a Annotation, message string) {
+	g.annotate("warning", a, message)
+}
+
+// Error prints the `error` command, which creates an error annotation.
+// https://docs.github.com/en/actions/using-workflows/workflow-commands-for-github-actions#setting-an-error-message
+func (g Github) Error(a Annotation, message string) {
+	g.annotate("error", a, message)
+}
+
+func (g Github) annotate(command string, a Annotation, message string) {
+	var props []string
+	if a.Title != "" {
+		props = append(props, "title="+escapeProperty(a.Title))
+	}
+	if a.File != "" {
+		props = append(props, "file="+escapeProperty(a.File))
+	}
+	if a.Line > 0 {
+		props = append(props, fmt.Sprintf("line=%d", a.Line))
+	}
+	if a.EndLine > 0 {
+		props = append(props, fmt.Sprintf("endLine=%d", a.EndLine))
+	}
+
+	if len(props) > 0 {
+		command += " " + strings.Join(props, ",")
+	}
+
+	_, _ = fmt.Fprintf(g.w, "::%s::%s\n", command, escapeData(message))
+}
+
+// Group prints the `group` command, which starts an expandable group in the log. Groups cannot be nested.
+// https://docs.github.com/en/actions/using-workflows/workflow-commands-for-github-actions#grouping-log-lines
+func (g Github) Group(title string) {
+	_, _ = fmt.Fprintf(g.w, "::group::%s\n", escapeData(title))
+}
+
+// EndGroup prints the `endgroup` command, which ends the group started by Group.
+func (g Github) EndGroup() {
+	_, _ = fmt.Fprintln(g.w, "::endgroup::")
+}
+
+// keyValue formats a name and value as Github Actions expects them in environment files. Values spanning multiple
+// lines are written using a random delimiter which is guaranteed not to appear in the value.
+func keyValue(name, value string) string {
+	if !strings.ContainsAny(value, "\r\n") {
+		return fmt.Sprintf("%s=%s\n", name, value)
+	}
+
+	delimiter := randomDelimiter()
+	for strings.Contains(value, delimiter) {
+		delimiter = randomDelimiter()
+	}
+
+	return fmt.Sprintf("%s<<%s\n%s\n%s\n", name, delimiter, value, delimiter)
+}
+
+func randomDelimiter() string {
+	const delimiterBytes = 16
+	buf := make([]byte, delimiterBytes)
+	// crypto/rand.Read does not fail on supported platforms.
+	_, _ = rand.Read(buf)
+
+	return "ghadelimiter_" + hex.EncodeToString(buf)
+}
+
+func appendTo(path, content string) error {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, envFilePermissions)
+	if err != nil {
+		return fmt.Errorf("opening file: %w", err)
+	}
+	defer file.Close()
+
+	_, err = file.WriteString(content)
+	if err != nil {
+		return fmt.Errorf("writing file: %w", err)
+	}
+
+	return nil
+}
+
+// escapeData escapes the message of a workflow command so newlines do not break it.
+func escapeData(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+// escapeProperty escapes the value of a workflow command property.
+func escapeProperty(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(s)
 }
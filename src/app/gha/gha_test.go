@@ -0,0 +1,91 @@
+package gha_test
+
+import (
+	"os"
+	"path"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/newrelic/release-toolkit/src/app/gha"
+)
+
+func TestGithub_SetOutput_File(t *testing.T) {
+	t.Parallel()
+
+	outputPath := path.Join(t.TempDir(), "output")
+	if err := os.WriteFile(outputPath, nil, 0o600); err != nil {
+		t.Fatalf("Error creating output file: %v", err)
+	}
+	buf := &strings.Builder{}
+	gh := gha.New(buf, gha.OutputFile(outputPath))
+
+	gh.SetOutput("version", "v1.2.3")
+	gh.SetOutput("notes", "### Notes\n\nMultiline value")
+
+	if buf.Len() != 0 {
+		t.Fatalf("Expected nothing to be printed, got %q", buf.String())
+	}
+
+	output, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("Error reading output file: %v", err)
+	}
+
+	expected := regexp.MustCompile(
+		`^version=v1\.2\.3\nnotes<<(ghadelimiter_[0-9a-f]+)\n### Notes\n\nMultiline value\n(ghadelimiter_[0-9a-f]+)\n$`,
+	)
+	matches := expected.FindStringSubmatch(string(output))
+	if matches == nil {
+		t.Fatalf("Output file does not have the expected format:\n%s", output)
+	}
+	if matches[1] != matches[2] {
+		t.Fatalf("Opening delimiter %q does not match closing delimiter %q", matches[1], matches[2])
+	}
+}
+
+func TestGithub_MissingFiles_Stdout(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	buf := &strings.Builder{}
+	gh := gha.New(buf, gha.OutputFile(path.Join(dir, "output")), gha.StepSummaryFile(path.Join(dir, "summary")))
+
+	gh.SetOutput("version", "v1.2.3")
+	gh.AddSummary("## Summary\n")
+
+	expected := "::set-output name=version::v1.2.3\n## Summary\n"
+	if actual := buf.String(); actual != expected {
+		t.Fatalf("Expected:\n%s\ngot:\n%s", expected, actual)
+	}
+
+	if entries, err := os.ReadDir(dir); err != nil || len(entries) != 0 {
+		t.Fatalf("Expected missing files not to be created, got %v (%v)", entries, err)
+	}
+}
+
+func TestGithub_Commands_Stdout(t *testing.T) {
+	t.Parallel()
+
+	buf := &strings.Builder{}
+	gh := gha.New(buf)
+
+	gh.SetOutput("notes", "100% done\nfor real")
+	gh.Group("Component")
+	gh.Warning(gha.Annotation{File: "CHANGELOG.md", Line: 3, Title: "Check: this"}, "Something looks off")
+	gh.Error(gha.Annotation{}, "Something is wrong")
+	gh.EndGroup()
+	gh.AddSummary("## Summary\n")
+
+	expected := strings.TrimLeft(`
+::set-output name=notes::100%25 done%0Afor real
+::group::Component
+::warning title=Check%3A this,file=CHANGELOG.md,line=3::Something looks off
+::error::Something is wrong
+::endgroup::
+## Summary
+`, "\n")
+	if actual := buf.String(); actual != expected {
+		t.Fatalf("Expected:\n%s\ngot:\n%s", expected, actual)
+	}
+}
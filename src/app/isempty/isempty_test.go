@@ -10,10 +10,15 @@ import (
 	"github.com/urfave/cli/v2"
 
 	"github.com/newrelic/release-toolkit/src/app"
+	"github.com/newrelic/release-toolkit/src/app/common"
 )
 
 //nolint:paralleltest,gocyclo,cyclop
 func TestIsEmpty(t *testing.T) {
+	// Workflow commands are asserted on stdout, so environment files set when running on Github Actions are ignored.
+	t.Setenv(common.GHAOutputEnv, "")
+	t.Setenv(common.GHAStepSummaryEnv, "")
+
 	app := app.App()
 
 	buf := &strings.Builder{}
@@ -82,6 +87,10 @@ changes: []
 
 //nolint:paralleltest
 func TestIsNotEmpty(t *testing.T) {
+	// Workflow commands are asserted on stdout, so environment files set when running on Github Actions are ignored.
+	t.Setenv(common.GHAOutputEnv, "")
+	t.Setenv(common.GHAStepSummaryEnv, "")
+
 	app := app.App()
 
 	buf := &strings.Builder{}
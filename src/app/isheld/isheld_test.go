@@ -8,10 +8,15 @@ import (
 	"testing"
 
 	"github.com/newrelic/release-toolkit/src/app"
+	"github.com/newrelic/release-toolkit/src/app/common"
 )
 
 //nolint:paralleltest
 func TestIsHeld(t *testing.T) {
+	// Workflow commands are asserted on stdout, so environment files set when running on Github Actions are ignored.
+	t.Setenv(common.GHAOutputEnv, "")
+	t.Setenv(common.GHAStepSummaryEnv, "")
+
 	app := app.App()
 
 	buf := &strings.Builder{}
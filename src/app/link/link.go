@@ -1,10 +1,16 @@
 package link
 
 import (
+	"errors"
 	"fmt"
+	"io"
+	"net/url"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/newrelic/release-toolkit/src/app/common"
+	"github.com/newrelic/release-toolkit/src/app/config"
 	"github.com/newrelic/release-toolkit/src/changelog"
 	"github.com/newrelic/release-toolkit/src/changelog/linker"
 	"github.com/newrelic/release-toolkit/src/changelog/linker/mapper"
@@ -15,10 +21,59 @@ import (
 const (
 	dictionaryPathFlag          = "dictionary"
 	sampleFlag                  = "sample"
+	explainFlag                 = "explain"
 	disableGithubValidationFlag = "disable-github-validation"
+	registriesFlag              = "registries"
+	goProxyURLFlag              = "go-proxy-url"
+	goVanityImportsFlag         = "go-vanity-imports"
+	npmURLFlag                  = "npm-url"
+	pypiURLFlag                 = "pypi-url"
+	dockerRegistryURLFlag       = "docker-registry-url"
+	artifactHubURLFlag          = "artifacthub-url"
+	compareFlag                 = "compare"
+	compareHostsFlag            = "compare-hosts"
+	concurrencyFlag             = "concurrency"
+	timeoutFlag                 = "timeout"
+	retriesFlag                 = "retries"
+	githubTokenFlag             = "github-token"
+	linkCacheFlag               = "link-cache"
+	linkCacheTTLFlag            = "link-cache-ttl"
+	releaseNotesFlag            = "release-notes"
+	releasesAPIURLFlag          = "releases-api-url"
+	releasesAPITokenFlag        = "releases-api-token"
 	chFilePermissions           = os.FileMode(0o666)
+	linkCheckBackoff            = 500 * time.Millisecond
 )
 
+var (
+	ErrUnknownRegistry    = errors.New("unknown registry")
+	ErrInvalidCompareHost = errors.New("compare host must be in the form host=forge")
+)
+
+// registryMappers builds the mapper for each registry supported by --registries from the base URL in its option.
+//
+//nolint:gochecknoglobals
+var registryMappers = map[string]func(opts Options) linker.Mapper{
+	"go": func(opts Options) linker.Mapper {
+		goProxy := mapper.NewGoProxy(opts.GoProxyURL)
+		goProxy.VanityImports = opts.GoVanityImports
+
+		return goProxy
+	},
+	"npm": func(opts Options) linker.Mapper {
+		return mapper.NewNpm(opts.NpmURL)
+	},
+	"pypi": func(opts Options) linker.Mapper {
+		return mapper.NewPyPI(opts.PyPIURL)
+	},
+	"dockerhub": func(opts Options) linker.Mapper {
+		return mapper.NewDockerHub(opts.DockerRegistryURL)
+	},
+	"artifacthub": func(opts Options) linker.Mapper {
+		return mapper.NewArtifactHub(opts.ArtifactHubURL)
+	},
+}
+
 // Cmd is the cli.Command object for the link-dependencies command.
 //
 //nolint:gochecknoglobals // We could overengineer this to avoid the global command but I don't think it's worth it.
@@ -32,7 +87,8 @@ var Cmd = &cli.Command{
 			EnvVars: common.EnvFor(dictionaryPathFlag),
 			Usage: "Path to a dictionary file mapping dependencies to their changelogs. " +
 				"A dictionary is a YAML file with a root dictionary object, which contains a map from " +
-				"dependency names to a template that will be rendered into a URL pointing to its changelog. " +
+				"dependency names to a template that will be rendered into a URL pointing to its changelog, " +
+				"or an ordered list of entries matching dependencies by name, regex or glob, whose captures are available in the template as {{.Match.<group>}}. " +
 				"The template link must be in Go tpl format and typically will include the {{.To.Original}} variable " +
 				"that will be replaced by the last bumped version (execute link-dependencies with --sample flag to see a dictionary.yml sample)",
 			Value: "",
@@ -42,6 +98,11 @@ var Cmd = &cli.Command{
 			EnvVars: common.EnvFor(sampleFlag),
 			Usage:   "Prints a sample dictionary to stdout.",
 		},
+		&cli.BoolFlag{
+			Name:    explainFlag,
+			EnvVars: common.EnvFor(explainFlag),
+			Usage:   "Prints which dictionary entry matched each dependency, and the link it rendered, to stdout.",
+		},
 		&cli.BoolFlag{
 			Name:    disableGithubValidationFlag,
 			EnvVars: common.EnvFor(disableGithubValidationFlag),
@@ -53,17 +114,202 @@ var Cmd = &cli.Command{
 				"https://github.com/<org>/<repo>/releases/tag/<new-version> with no validation, so no external request are performed.",
 			Value: false,
 		},
+		&cli.StringSliceFlag{
+			Name:    registriesFlag,
+			EnvVars: common.EnvFor(registriesFlag),
+			Usage: "Package registries to look up the source repository of dependencies in, in order, when they are not " +
+				"in the dictionary nor hosted on Github: go, npm, pypi, dockerhub and artifacthub. " +
+				"Dependencies are linked to the Github release of their new version in that repository, which is " +
+				"validated as other Github links.",
+		},
+		&cli.StringFlag{
+			Name:    goProxyURLFlag,
+			EnvVars: common.EnvFor(goProxyURLFlag),
+			Usage:   "Base URL of the Go module proxy, used when go is listed in --registries.",
+			Value:   mapper.DefaultGoProxyURL,
+		},
+		&cli.BoolFlag{
+			Name:    goVanityImportsFlag,
+			EnvVars: common.EnvFor(goVanityImportsFlag),
+			Usage: "Looks up the go-import meta tag of Go modules whose origin the proxy does not report, requesting " +
+				"the host of their import path. Ignored if --go-proxy-url is not the public Go module proxy.",
+			Value: false,
+		},
+		&cli.StringFlag{
+			Name:    npmURLFlag,
+			EnvVars: common.EnvFor(npmURLFlag),
+			Usage:   "Base URL of the npm registry, used when npm is listed in --registries.",
+			Value:   mapper.DefaultNpmURL,
+		},
+		&cli.StringFlag{
+			Name:    pypiURLFlag,
+			EnvVars: common.EnvFor(pypiURLFlag),
+			Usage:   "Base URL of the Python package index, used when pypi is listed in --registries.",
+			Value:   mapper.DefaultPyPIURL,
+		},
+		&cli.StringFlag{
+			Name:    dockerRegistryURLFlag,
+			EnvVars: common.EnvFor(dockerRegistryURLFlag),
+			Usage:   "Base URL of the Docker registry, used when dockerhub is listed in --registries.",
+			Value:   mapper.DefaultDockerRegistryURL,
+		},
+		&cli.StringFlag{
+			Name:    artifactHubURLFlag,
+			EnvVars: common.EnvFor(artifactHubURLFlag),
+			Usage:   "Base URL of the Artifact Hub instance, used when artifacthub is listed in --registries.",
+			Value:   mapper.DefaultArtifactHubURL,
+		},
+		&cli.BoolFlag{
+			Name:    compareFlag,
+			EnvVars: common.EnvFor(compareFlag),
+			Usage: "Adds links to the differences between the previous and the new version of dependencies hosted on " +
+				"GitHub, GitLab or Gitea, like https://github.com/<org>/<repo>/compare/<old-version>...<new-version>. " +
+				"The repository is taken from the changelog link, or from the dependency name if it is not linked. " +
+				"Links are validated as Github changelog links are.",
+			Value: false,
+		},
+		&cli.StringSliceFlag{
+			Name:    compareHostsFlag,
+			EnvVars: common.EnvFor(compareHostsFlag),
+			Usage: "Self-hosted forges compare links are added for, besides github.com, gitlab.com, gitea.com and " +
+				"codeberg.org, in the form host=forge, where forge is github, gitlab or gitea.",
+		},
+		&cli.IntFlag{
+			Name:    concurrencyFlag,
+			EnvVars: common.EnvFor(concurrencyFlag),
+			Usage:   "Maximum number of dependencies linked at the same time.",
+			Value:   8, //nolint:gomnd
+		},
+		&cli.DurationFlag{
+			Name:    timeoutFlag,
+			EnvVars: common.EnvFor(timeoutFlag),
+			Usage:   "Timeout of each request performed to validate a link.",
+			Value:   5 * time.Second, //nolint:gomnd
+		},
+		&cli.IntFlag{
+			Name:    retriesFlag,
+			EnvVars: common.EnvFor(retriesFlag),
+			Usage: "Number of times the validation of a link is retried, with exponential backoff, when it fails due to " +
+				"network errors, rate limits, authorization errors or server errors, waiting as long as the server asks " +
+				"for up to a minute. Links that cannot be validated are kept.",
+			Value: 2, //nolint:gomnd
+		},
+		&cli.StringFlag{
+			Name:    githubTokenFlag,
+			EnvVars: common.EnvFor(githubTokenFlag),
+			Usage: "Token sent to api.github.com, which GitHub release and compare links are validated through when it " +
+				"is set, and when fetching release notes, so requests are subject to higher rate limits.",
+		},
+		&cli.StringFlag{
+			Name:    linkCacheFlag,
+			EnvVars: common.EnvFor(linkCacheFlag),
+			Usage: "Path to a file where the results of link validations are cached across runs, keyed by URL. " +
+				"Only links that exist, or that respond with 404 or 410, are cached. Caching is disabled if empty.",
+		},
+		&cli.DurationFlag{
+			Name:    linkCacheTTLFlag,
+			EnvVars: common.EnvFor(linkCacheTTLFlag),
+			Usage:   "Time the results of link validations are kept in the link cache.",
+			Value:   24 * time.Hour, //nolint:gomnd
+		},
+		&cli.BoolFlag{
+			Name:    releaseNotesFlag,
+			EnvVars: common.EnvFor(releaseNotesFlag),
+			Usage: "Embeds the upstream release notes of each version of dependencies after the previous one and up to " +
+				"the new one, fetched from a GitHub-compatible releases API. The repository is taken from the changelog " +
+				"link, or from the dependency name if it is not linked.",
+			Value: false,
+		},
+		&cli.StringFlag{
+			Name:    releasesAPIURLFlag,
+			EnvVars: common.EnvFor(releasesAPIURLFlag),
+			Usage: "Base URL of the GitHub-compatible API release notes are fetched from, used when --release-notes is set, " +
+				"e.g. https://github.example.com/api/v3 for GitHub Enterprise Server.",
+			Value: mapper.DefaultGithubAPIURL,
+		},
+		&cli.StringFlag{
+			Name: releasesAPITokenFlag,
+			Usage: "Token sent to the API release notes are fetched from. Defaults to the value of --github-token only " +
+				"if the API is api.github.com, so the token is not sent to other hosts. Not read from the environment.",
+		},
 	},
+	Before: config.Apply,
 	Action: Link,
 }
 
+// Options holds the settings of link-dependencies, which are taken from its flags by OptionsFromCli.
+type Options struct {
+	// YAML is the path to the changelog.yaml whose dependencies are linked.
+	YAML string
+	// Dictionary is the path to a dictionary file, whose entries take precedence over the ones in ConfigDictionary.
+	Dictionary       string
+	ConfigDictionary mapper.Dictionary
+
+	DisableGithubValidation bool
+	Registries              []string
+	GoProxyURL              string
+	GoVanityImports         bool
+	NpmURL                  string
+	PyPIURL                 string
+	DockerRegistryURL       string
+	ArtifactHubURL          string
+	Compare                 bool
+	CompareHosts            []string
+
+	Concurrency  int
+	Timeout      time.Duration
+	Retries      int
+	LinkCache    string
+	LinkCacheTTL time.Duration
+
+	ReleaseNotes     bool
+	ReleasesAPIURL   string
+	ReleasesAPIToken string
+	// GithubToken is sent to api.github.com when validating GitHub links, and to the releases API only if it is the
+	// one of github.com and ReleasesAPIToken is empty.
+	GithubToken string
+
+	// Explain, if not nil, is where the dictionary entry matching each dependency is written to.
+	Explain io.Writer
+}
+
+// OptionsFromCli returns the Options set by the flags of link-dependencies, and by the config file, in cCtx.
+func OptionsFromCli(cCtx *cli.Context) Options {
+	opts := Options{
+		YAML:                    cCtx.String(common.YAMLFlag),
+		Dictionary:              cCtx.String(dictionaryPathFlag),
+		ConfigDictionary:        config.FromContext(cCtx).Dictionary,
+		DisableGithubValidation: cCtx.Bool(disableGithubValidationFlag),
+		Registries:              cCtx.StringSlice(registriesFlag),
+		GoProxyURL:              cCtx.String(goProxyURLFlag),
+		GoVanityImports:         cCtx.Bool(goVanityImportsFlag),
+		NpmURL:                  cCtx.String(npmURLFlag),
+		PyPIURL:                 cCtx.String(pypiURLFlag),
+		DockerRegistryURL:       cCtx.String(dockerRegistryURLFlag),
+		ArtifactHubURL:          cCtx.String(artifactHubURLFlag),
+		Compare:                 cCtx.Bool(compareFlag),
+		CompareHosts:            cCtx.StringSlice(compareHostsFlag),
+		Concurrency:             cCtx.Int(concurrencyFlag),
+		Timeout:                 cCtx.Duration(timeoutFlag),
+		Retries:                 cCtx.Int(retriesFlag),
+		LinkCache:               cCtx.String(linkCacheFlag),
+		LinkCacheTTL:            cCtx.Duration(linkCacheTTLFlag),
+		ReleaseNotes:            cCtx.Bool(releaseNotesFlag),
+		ReleasesAPIURL:          cCtx.String(releasesAPIURLFlag),
+		ReleasesAPIToken:        cCtx.String(releasesAPITokenFlag),
+		GithubToken:             cCtx.String(githubTokenFlag),
+	}
+
+	if cCtx.Bool(explainFlag) {
+		opts.Explain = cCtx.App.Writer
+	}
+
+	return opts
+}
+
 // Link is a command function which tries to add a link to the changelog of each dependency in a changelog
 // computing them from each of the defined mappers.
-//
-//nolint:gocyclo,cyclop
 func Link(cCtx *cli.Context) error {
-	chPath := cCtx.String(common.YAMLFlag)
-
 	if cCtx.Bool(sampleFlag) {
 		sampleDic, err := sampleDictionary()
 		if err != nil {
@@ -73,6 +319,15 @@ func Link(cCtx *cli.Context) error {
 		return nil
 	}
 
+	return Run(OptionsFromCli(cCtx))
+}
+
+// Run links the dependencies in the changelog.yaml of a single repository as configured by opts.
+//
+//nolint:gocyclo,cyclop
+func Run(opts Options) error {
+	chPath := opts.YAML
+
 	chFile, err := os.Open(chPath)
 	if err != nil {
 		return fmt.Errorf("opening changelog file %q: %w", chPath, err)
@@ -87,32 +342,103 @@ func Link(cCtx *cli.Context) error {
 
 	mappers := make([]linker.Mapper, 0)
 
-	if dicPath := cCtx.String(dictionaryPathFlag); dicPath != "" {
+	// Dictionary entries in the config file are combined with the ones in the dictionary file, which take precedence.
+	dic := opts.ConfigDictionary
+
+	if dicPath := opts.Dictionary; dicPath != "" {
 		dicFile, errPath := os.Open(dicPath)
 		if errPath != nil {
 			return fmt.Errorf("opening linker dictionary  %q: %w", dicPath, errPath)
 		}
-		dic, errPath := mapper.NewDictionary(dicFile)
+		fileDic, errPath := mapper.NewDictionary(dicFile)
 		if errPath != nil {
 			return fmt.Errorf("creating validator: %w", errPath)
 		}
+		dic = fileDic.Merge(dic)
+	}
+
+	if !dic.Empty() {
 		mappers = append(mappers, dic)
 	}
 
+	var cache *mapper.LinkCache
+	if cachePath := opts.LinkCache; cachePath != "" {
+		cache, err = mapper.LoadLinkCache(cachePath, opts.LinkCacheTTL)
+		if err != nil {
+			return fmt.Errorf("loading link cache: %w", err)
+		}
+	}
+
+	// All the links are validated by the same checker, so they share the cache.
+	checker := mapper.LeadingVCheckLinkChecker(mapper.NewLinkChecker(
+		mapper.LinkCheckerTimeout(opts.Timeout),
+		mapper.LinkCheckerRetries(opts.Retries, linkCheckBackoff),
+		mapper.LinkCheckerGithubAPI(mapper.DefaultGithubAPIURL, opts.GithubToken),
+		mapper.LinkCheckerCache(cache),
+	))
+
 	var githubMapper linker.Mapper = mapper.Github{}
 
-	if !cCtx.Bool(disableGithubValidationFlag) {
-		githubMapper = mapper.NewWithLeadingVCheck(githubMapper)
+	if !opts.DisableGithubValidation {
+		githubMapper = mapper.NewWithLeadingVCheck(githubMapper, checker)
 	}
 
 	mappers = append(mappers, githubMapper)
 
+	for _, name := range opts.Registries {
+		if name == "" {
+			continue
+		}
+
+		newMapper, found := registryMappers[name]
+		if !found {
+			return fmt.Errorf("%w %q, supported registries are go, npm, pypi, dockerhub and artifacthub", ErrUnknownRegistry, name)
+		}
+
+		var registryMapper linker.Mapper = newMapper(opts)
+		if !opts.DisableGithubValidation {
+			registryMapper = mapper.NewWithLeadingVCheck(registryMapper, checker)
+		}
+
+		mappers = append(mappers, registryMapper)
+	}
+
 	link := linker.New(mappers...)
+	link.Concurrency = opts.Concurrency
+
+	if opts.Compare {
+		comparer, errCompare := forgeCompare(opts.CompareHosts)
+		if errCompare != nil {
+			return errCompare
+		}
+
+		if !opts.DisableGithubValidation {
+			comparer = mapper.NewCompareWithLeadingVCheck(comparer, checker)
+		}
+
+		link.Comparers = append(link.Comparers, comparer)
+	}
+
+	if opts.ReleaseNotes {
+		link.NotesFetchers = append(link.NotesFetchers,
+			mapper.NewGithubReleases(opts.ReleasesAPIURL, releasesAPIToken(opts)),
+		)
+	}
+
 	err = link.Link(ch)
 	if err != nil {
 		return fmt.Errorf("linking dependency changelogs: %w", err)
 	}
 
+	err = cache.Save()
+	if err != nil {
+		return fmt.Errorf("saving link cache: %w", err)
+	}
+
+	if opts.Explain != nil {
+		explain(opts.Explain, dic, ch.Dependencies)
+	}
+
 	chFile, err = os.OpenFile(chPath, os.O_RDWR|os.O_TRUNC, chFilePermissions)
 	if err != nil {
 		return fmt.Errorf("truncating changelog file: %w", err)
@@ -127,6 +453,64 @@ func Link(cCtx *cli.Context) error {
 	return nil
 }
 
+// forgeCompare returns a comparer for the default forges and the ones in hosts, in the form host=forge.
+func forgeCompare(hosts []string) (linker.Comparer, error) {
+	opts := make([]mapper.ForgeCompareOptionFunc, 0, len(hosts))
+	for _, host := range hosts {
+		if host == "" {
+			continue
+		}
+
+		name, forgeName, found := strings.Cut(host, "=")
+		if !found || name == "" {
+			return nil, fmt.Errorf("%w, got %q", ErrInvalidCompareHost, host)
+		}
+
+		forge, err := mapper.ParseForge(forgeName)
+		if err != nil {
+			return nil, fmt.Errorf("parsing forge of %q: %w", name, err)
+		}
+
+		opts = append(opts, mapper.ForgeHost(name, forge))
+	}
+
+	return mapper.NewForgeCompare(opts...), nil
+}
+
+// releasesAPIToken returns the token sent to the releases API, which is the one of github.com only if the API is
+// served from api.github.com, as it must not be leaked to any other host.
+func releasesAPIToken(opts Options) string {
+	if opts.ReleasesAPIToken != "" {
+		return opts.ReleasesAPIToken
+	}
+
+	u, err := url.Parse(opts.ReleasesAPIURL)
+	if err != nil || u.Scheme != "https" || !strings.EqualFold(u.Host, "api.github.com") {
+		return ""
+	}
+
+	return opts.GithubToken
+}
+
+// explain prints which dictionary entry matches each dependency, and the link it renders.
+func explain(w io.Writer, dic mapper.Dictionary, deps []changelog.Dependency) {
+	for _, dep := range deps {
+		match, found := dic.Match(dep)
+		if !found {
+			_, _ = fmt.Fprintf(w, "%s: no dictionary entry matched\n", dep.Name)
+			continue
+		}
+
+		link, err := dic.Render(dep, match)
+		if err != nil {
+			_, _ = fmt.Fprintf(w, "%s: matched %s, which could not be rendered: %v\n", dep.Name, match.Entry, err)
+			continue
+		}
+
+		_, _ = fmt.Fprintf(w, "%s: matched %s -> %s\n", dep.Name, match.Entry, link)
+	}
+}
+
 //nolint:wrapcheck
 func sampleDictionary() ([]byte, error) {
 	sampleDictionary := mapper.Dictionary{
@@ -135,5 +519,7 @@ func sampleDictionary() ([]byte, error) {
 			"golangci-lint":           "https://github.com/golangci/golangci-lint/releases/tag/{{.To.Original}}",
 		},
 	}
-	return yaml.Marshal(sampleDictionary)
+	return yaml.Marshal(struct {
+		Dictionary mapper.Dictionary `yaml:"dictionary"`
+	}{Dictionary: sampleDictionary})
 }
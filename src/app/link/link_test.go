@@ -1,8 +1,10 @@
 package link_test
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
+	"net/http/httptest"
 	"os"
 	"path"
 	"strings"
@@ -12,6 +14,7 @@ import (
 	"github.com/h2non/gock"
 
 	"github.com/newrelic/release-toolkit/src/app"
+	"github.com/newrelic/release-toolkit/src/app/link"
 )
 
 //nolint:paralleltest,funlen // urfave/cli cannot be tested concurrently.
@@ -146,10 +149,10 @@ dependencies:
 			// setup http mock to avoid external requests to check github links.
 			defer gock.Off()
 			gock.New("https://github.com").
-				Get("/spf13/viper/releases/tag/4.1.2").
+				Head("/spf13/viper/releases/tag/4.1.2").
 				Reply(http.StatusNotFound)
 			gock.New("https://github.com").
-				Get("/spf13/viper/releases/tag/v4.1.2").
+				Head("/spf13/viper/releases/tag/v4.1.2").
 				Reply(http.StatusOK)
 
 			tDir := t.TempDir()
@@ -216,3 +219,422 @@ dictionary:
 		t.Fatalf("Changelog.yml is not as expected\n%s", diff)
 	}
 }
+
+//nolint:paralleltest // urfave/cli cannot be tested concurrently.
+func TestLink_Config_Dictionary(t *testing.T) {
+	tDir := t.TempDir()
+
+	chlogPath := path.Join(tDir, "changelog.yaml")
+	chlog := strings.TrimSpace(`
+notes: ""
+changes: []
+dependencies:
+- name: golangci-lint
+  to: 1.50.0
+- name: newrelic-infrastructure
+  to: 3.1.0
+	`)
+	if err := os.WriteFile(chlogPath, []byte(chlog), 0o600); err != nil {
+		t.Fatalf("Error creating yaml for test: %v", err)
+	}
+
+	configPath := path.Join(tDir, "release-toolkit.yaml")
+	config := strings.TrimSpace(`
+dictionary:
+  golangci-lint: "https://github.com/golangci/golangci-lint/releases/tag/v{{.To.Original}}"
+  newrelic-infrastructure: "https://example.com/config/{{.To.Original}}"
+	`)
+	if err := os.WriteFile(configPath, []byte(config), 0o600); err != nil {
+		t.Fatalf("Error creating config for test: %v", err)
+	}
+
+	// Entries in the dictionary file take precedence over the ones in the config.
+	dicPath := path.Join(tDir, "dictionary.yaml")
+	dictionary := strings.TrimSpace(`
+dictionary:
+  newrelic-infrastructure: "https://example.com/dictionary/{{.To.Original}}"
+	`)
+	if err := os.WriteFile(dicPath, []byte(dictionary), 0o600); err != nil {
+		t.Fatalf("Error creating dictionary for test: %v", err)
+	}
+
+	app := app.App()
+	err := app.Run(strings.Fields(fmt.Sprintf(
+		"rt -config %s -yaml %s link-dependencies -dictionary %s -disable-github-validation", configPath, chlogPath, dicPath,
+	)))
+	if err != nil {
+		t.Fatalf("Error running app: %v", err)
+	}
+
+	actual, err := os.ReadFile(chlogPath)
+	if err != nil {
+		t.Fatalf("Error reading changelog file: %v", err)
+	}
+
+	expected := strings.TrimLeft(`
+notes: ""
+changes: []
+dependencies:
+    - name: golangci-lint
+      to: 1.50.0
+      changelog: https://github.com/golangci/golangci-lint/releases/tag/v1.50.0
+    - name: newrelic-infrastructure
+      to: 3.1.0
+      changelog: https://example.com/dictionary/3.1.0
+`, "\n")
+	if diff := cmp.Diff(expected, string(actual)); diff != "" {
+		t.Fatalf("Changelog.yml is not as expected\n%s", diff)
+	}
+}
+
+//nolint:paralleltest // urfave/cli cannot be tested concurrently.
+func TestLink_Registries(t *testing.T) {
+	npm := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/left-pad/1.3.0" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`{"repository": {"type": "git", "url": "git+https://github.com/left-pad/left-pad.git"}}`))
+	}))
+	defer npm.Close()
+
+	pypi := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/pypi/requests/json":
+			_, _ = w.Write([]byte(`{"info": {"project_urls": {"Source": "https://github.com/psf/requests"}}}`))
+		case "/pypi/left-pad/json":
+			_, _ = w.Write([]byte(`{"info": {"project_urls": {"Source": "https://github.com/someone/left-pad"}}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer pypi.Close()
+
+	tDir := t.TempDir()
+	chlogPath := path.Join(tDir, "changelog.yaml")
+	chlog := strings.TrimSpace(`
+notes: ""
+changes: []
+dependencies:
+- name: left-pad
+  to: 1.3.0
+- name: requests
+  to: 2.28.1
+- name: unknown
+  to: 1.0.0
+	`)
+	if err := os.WriteFile(chlogPath, []byte(chlog), 0o600); err != nil {
+		t.Fatalf("Error creating yaml for test: %v", err)
+	}
+
+	// Registries are looked up in order, so left-pad is linked to the repository of the npm package.
+	err := app.App().Run(strings.Fields(fmt.Sprintf(
+		"rt -yaml %s link-dependencies -disable-github-validation -registries npm,pypi -npm-url %s -pypi-url %s",
+		chlogPath, npm.URL, pypi.URL,
+	)))
+	if err != nil {
+		t.Fatalf("Error running app: %v", err)
+	}
+
+	actual, err := os.ReadFile(chlogPath)
+	if err != nil {
+		t.Fatalf("Error reading changelog file: %v", err)
+	}
+
+	expected := strings.TrimLeft(`
+notes: ""
+changes: []
+dependencies:
+    - name: left-pad
+      to: 1.3.0
+      changelog: https://github.com/left-pad/left-pad/releases/tag/1.3.0
+    - name: requests
+      to: 2.28.1
+      changelog: https://github.com/psf/requests/releases/tag/2.28.1
+    - name: unknown
+      to: 1.0.0
+`, "\n")
+	if diff := cmp.Diff(expected, string(actual)); diff != "" {
+		t.Fatalf("Changelog.yml is not as expected\n%s", diff)
+	}
+
+	err = app.App().Run(strings.Fields(fmt.Sprintf("rt -yaml %s link-dependencies -registries maven", chlogPath)))
+	if !errors.Is(err, link.ErrUnknownRegistry) {
+		t.Fatalf("Expected %v, got %v", link.ErrUnknownRegistry, err)
+	}
+}
+
+//nolint:paralleltest // urfave/cli cannot be tested concurrently.
+func TestLink_Compare(t *testing.T) {
+	tDir := t.TempDir()
+	chlogPath := path.Join(tDir, "changelog.yaml")
+	chlog := strings.TrimSpace(`
+notes: ""
+changes: []
+dependencies:
+- name: github.com/spf13/viper
+  from: v1.11.0
+  to: v1.12.0
+- name: git.example.com/org/repo
+  from: 1.0.0
+  to: 1.1.0
+- name: golangci-lint
+  to: 1.50.0
+	`)
+	if err := os.WriteFile(chlogPath, []byte(chlog), 0o600); err != nil {
+		t.Fatalf("Error creating yaml for test: %v", err)
+	}
+
+	err := app.App().Run(strings.Fields(fmt.Sprintf(
+		"rt -yaml %s link-dependencies -disable-github-validation -compare -compare-hosts git.example.com=gitea", chlogPath,
+	)))
+	if err != nil {
+		t.Fatalf("Error running app: %v", err)
+	}
+
+	actual, err := os.ReadFile(chlogPath)
+	if err != nil {
+		t.Fatalf("Error reading changelog file: %v", err)
+	}
+
+	expected := strings.TrimLeft(`
+notes: ""
+changes: []
+dependencies:
+    - name: github.com/spf13/viper
+      from: v1.11.0
+      to: v1.12.0
+      changelog: https://github.com/spf13/viper/releases/tag/v1.12.0
+      compare: https://github.com/spf13/viper/compare/v1.11.0...v1.12.0
+    - name: git.example.com/org/repo
+      from: 1.0.0
+      to: 1.1.0
+      compare: https://git.example.com/org/repo/compare/1.0.0...1.1.0
+    - name: golangci-lint
+      to: 1.50.0
+`, "\n")
+	if diff := cmp.Diff(expected, string(actual)); diff != "" {
+		t.Fatalf("Changelog.yml is not as expected\n%s", diff)
+	}
+
+	for _, host := range []string{"git.example.com", "git.example.com=bitbucket"} {
+		err = app.App().Run(strings.Fields(fmt.Sprintf("rt -yaml %s link-dependencies -compare -compare-hosts %s", chlogPath, host)))
+		if err == nil {
+			t.Fatalf("Expected an error for compare host %q", host)
+		}
+	}
+}
+
+//nolint:paralleltest // urfave/cli cannot be tested concurrently.
+func TestLink_Cache(t *testing.T) {
+	defer gock.Off()
+
+	tDir := t.TempDir()
+	chlogPath := path.Join(tDir, "changelog.yaml")
+	cachePath := path.Join(tDir, "cache", "links.json")
+	chlog := strings.TrimSpace(`
+notes: ""
+changes: []
+dependencies:
+- name: github.com/spf13/viper
+  to: 4.1.2
+	`)
+	expected := strings.TrimLeft(`
+notes: ""
+changes: []
+dependencies:
+    - name: github.com/spf13/viper
+      to: 4.1.2
+      changelog: https://github.com/spf13/viper/releases/tag/v4.1.2
+`, "\n")
+
+	run := func() {
+		t.Helper()
+
+		if err := os.WriteFile(chlogPath, []byte(chlog), 0o600); err != nil {
+			t.Fatalf("Error creating yaml for test: %v", err)
+		}
+
+		err := app.App().Run(strings.Fields(fmt.Sprintf(
+			"rt -yaml %s link-dependencies -link-cache %s -retries 0 -concurrency 2", chlogPath, cachePath,
+		)))
+		if err != nil {
+			t.Fatalf("Error running app: %v", err)
+		}
+
+		actual, err := os.ReadFile(chlogPath)
+		if err != nil {
+			t.Fatalf("Error reading changelog file: %v", err)
+		}
+
+		if diff := cmp.Diff(expected, string(actual)); diff != "" {
+			t.Fatalf("Changelog.yml is not as expected\n%s", diff)
+		}
+	}
+
+	gock.New("https://github.com").
+		Head("/spf13/viper/releases/tag/4.1.2").
+		Reply(http.StatusNotFound)
+	gock.New("https://github.com").
+		Head("/spf13/viper/releases/tag/v4.1.2").
+		Reply(http.StatusOK)
+	run()
+
+	if !gock.IsDone() {
+		t.Fatalf("Expected both links to be checked")
+	}
+
+	// Links are not checked again, as requests that do not match any mock would fail and the link would be kept.
+	gock.Off()
+	gock.New("https://github.com").Head("/unused").Reply(http.StatusOK)
+	run()
+
+	if gock.HasUnmatchedRequest() {
+		t.Fatalf("Expected links to be read from the cache")
+	}
+}
+
+func TestLink_Explain(t *testing.T) {
+	t.Parallel()
+
+	tDir := t.TempDir()
+	chlogPath := path.Join(tDir, "changelog.yaml")
+	chlog := strings.TrimSpace(`
+notes: ""
+changes: []
+dependencies:
+- name: newrelic/nri-kafka
+  to: 3.1.0
+- name: newrelic/nri-redis
+  to: 1.2.0
+- name: org/unknown
+  to: 1.0.0
+	`)
+	if err := os.WriteFile(chlogPath, []byte(chlog), 0o600); err != nil {
+		t.Fatalf("Error creating yaml for test: %v", err)
+	}
+
+	dicPath := path.Join(tDir, "dictionary.yaml")
+	dictionary := strings.TrimSpace(`
+dictionary:
+  - name: newrelic/nri-kafka
+    changelog: "https://example.com/kafka/{{.To.Original}}"
+  - regex: '^newrelic/nri-(?P<integration>.+)$'
+    changelog: "https://example.com/{{.Match.integration}}/{{.To.Original}}"
+	`)
+	if err := os.WriteFile(dicPath, []byte(dictionary), 0o600); err != nil {
+		t.Fatalf("Error creating dictionary for test: %v", err)
+	}
+
+	expected := strings.TrimLeft(`
+newrelic/nri-kafka: matched name "newrelic/nri-kafka" -> https://example.com/kafka/3.1.0
+newrelic/nri-redis: matched regex "^newrelic/nri-(?P<integration>.+)$" -> https://example.com/redis/1.2.0
+org/unknown: no dictionary entry matched
+`, "\n")
+
+	app := app.App()
+	buf := &strings.Builder{}
+	app.Writer = buf
+
+	err := app.Run(strings.Fields(fmt.Sprintf(
+		"rt -yaml %s link-dependencies -dictionary %s -disable-github-validation -explain", chlogPath, dicPath,
+	)))
+	if err != nil {
+		t.Fatalf("Error running app: %v", err)
+	}
+
+	if diff := cmp.Diff(expected, buf.String()); diff != "" {
+		t.Fatalf("Explanation is not as expected\n%s", diff)
+	}
+}
+
+//nolint:paralleltest // urfave/cli cannot be tested concurrently.
+func TestLink_ReleaseNotes(t *testing.T) {
+	var authorization string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		if r.URL.Path != "/repos/spf13/viper/releases" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		_, _ = w.Write([]byte(`[
+			{"tag_name": "v1.13.0", "body": "Not yet"},
+			{"tag_name": "v1.12.0", "body": "New features"},
+			{"tag_name": "v1.11.1", "body": "Fixes\r\n"},
+			{"tag_name": "v1.11.0", "body": "Already released"}
+		]`))
+	}))
+	t.Cleanup(server.Close)
+
+	chlog := strings.TrimSpace(`
+notes: ""
+changes: []
+dependencies:
+- name: github.com/spf13/viper
+  from: v1.11.0
+  to: v1.12.0
+	`)
+
+	expected := strings.TrimLeft(`
+notes: ""
+changes: []
+dependencies:
+    - name: github.com/spf13/viper
+      from: v1.11.0
+      to: v1.12.0
+      changelog: https://github.com/spf13/viper/releases/tag/v1.12.0
+      release-notes:
+        - version: v1.12.0
+          notes: New features
+        - version: v1.11.1
+          notes: Fixes
+`, "\n")
+
+	for _, tc := range []struct {
+		name                  string
+		flags                 string
+		expectedAuthorization string
+	}{
+		{
+			name:                  "Releases_API_Token",
+			flags:                 "-github-token github -releases-api-token secret",
+			expectedAuthorization: "Bearer secret",
+		},
+		{
+			// The token of github.com is only sent to api.github.com.
+			name:  "Github_Token_Not_Sent",
+			flags: "-github-token github",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			authorization = ""
+
+			chlogPath := path.Join(t.TempDir(), "changelog.yaml")
+			if err := os.WriteFile(chlogPath, []byte(chlog), 0o600); err != nil {
+				t.Fatalf("Error creating yaml for test: %v", err)
+			}
+
+			err := app.App().Run(strings.Fields(fmt.Sprintf(
+				"rt -yaml %s link-dependencies -disable-github-validation -release-notes -releases-api-url %s %s",
+				chlogPath, server.URL, tc.flags,
+			)))
+			if err != nil {
+				t.Fatalf("Error running app: %v", err)
+			}
+
+			if authorization != tc.expectedAuthorization {
+				t.Fatalf("Expected authorization %q, got %q", tc.expectedAuthorization, authorization)
+			}
+
+			actual, err := os.ReadFile(chlogPath)
+			if err != nil {
+				t.Fatalf("Error reading changelog file: %v", err)
+			}
+
+			if diff := cmp.Diff(expected, string(actual)); diff != "" {
+				t.Fatalf("Changelog.yml is not as expected\n%s", diff)
+			}
+		})
+	}
+}
@@ -4,14 +4,18 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/Masterminds/semver"
 	"github.com/newrelic/release-toolkit/src/app/common"
+	"github.com/newrelic/release-toolkit/src/app/config"
 	"github.com/newrelic/release-toolkit/src/app/gha"
 	"github.com/newrelic/release-toolkit/src/bump"
 	"github.com/newrelic/release-toolkit/src/bumper"
 	"github.com/newrelic/release-toolkit/src/changelog"
 	"github.com/newrelic/release-toolkit/src/git"
+	"github.com/newrelic/release-toolkit/src/monorepo"
 	"github.com/newrelic/release-toolkit/src/version"
 	log "github.com/sirupsen/logrus"
 	"github.com/urfave/cli/v2"
@@ -27,12 +31,22 @@ const (
 	BumpCapFlag       = "bump-cap"
 	DependencyCapFlag = "dependency-cap"
 	failFlag          = "fail"
+	prereleaseFlag    = "prerelease"
+	promoteFlag       = "promote"
+	schemeFlag        = "scheme"
+	constraintFlag    = "version-constraint"
+	dateFlag          = "date"
+	padMonthFlag      = "calver-pad-month"
 )
 
+// ErrMonorepoOverride is returned when version overrides are used in monorepo mode, where they are ambiguous.
+var ErrMonorepoOverride = errors.New("current and next version overrides cannot be used with a monorepo manifest")
+
 const (
-	nextVersionOutput = "next-version"
-	majorOutput       = "next-version-major"
-	majorMinorOutput  = "next-version-major-minor"
+	releaseComponentsOutput = "release-components"
+	nextVersionOutput       = "next-version"
+	majorOutput             = "next-version-major"
+	majorMinorOutput        = "next-version-major-minor"
 )
 
 // Cmd is the cli.Command object for the next-version command.
@@ -89,6 +103,48 @@ next-version will exit with an error if no previous versions are found in the gi
 			Usage:   "In case of having to bump the version of base on a dependency, limit to this semVer type",
 			Value:   string(bump.MajorName),
 		},
+		&cli.StringFlag{
+			Name:    prereleaseFlag,
+			EnvVars: common.EnvFor(prereleaseFlag),
+			Usage: "If set, compute the next prerelease version using this identifier (e.g. rc, beta, alpha). " +
+				"The numeric suffix of the latest prerelease is incremented if the changelog does not require a larger bump, " +
+				"otherwise a new <identifier>.1 prerelease is started.",
+			Value: "",
+		},
+		&cli.BoolFlag{
+			Name:    promoteFlag,
+			EnvVars: common.EnvFor(promoteFlag),
+			Usage:   "If set, the next version is the latest prerelease without its prerelease suffix (e.g. v2.0.0-rc.2 becomes v2.0.0).",
+			Value:   false,
+		},
+		&cli.StringFlag{
+			Name:    schemeFlag,
+			EnvVars: common.EnvFor(schemeFlag),
+			Usage: "Versioning scheme used to parse tags and compute the next version. Either semver, or calver for " +
+				"YYYY.MM.MICRO versions, where MICRO is reset every month.",
+			Value: string(version.SemverName),
+		},
+		&cli.TimestampFlag{
+			Name:    dateFlag,
+			EnvVars: common.EnvFor(dateFlag),
+			Usage: "Date CalVer versions are computed for, in YYYY-MM-DD format. " +
+				"If empty it will default to the current time (time.Now()).",
+			Layout: "2006-01-02",
+		},
+		&cli.BoolFlag{
+			Name:    padMonthFlag,
+			EnvVars: common.EnvFor(padMonthFlag),
+			Usage: "If set, the month of new CalVer versions is always zero-padded (e.g. 2024.03.0). Otherwise, it is " +
+				"padded only if it is in the current version.",
+			Value: false,
+		},
+		&cli.StringFlag{
+			Name:    constraintFlag,
+			EnvVars: common.EnvFor(constraintFlag),
+			Usage: "If set, only versions satisfying this constraint (e.g. \"~1.4\" or \"<2.0.0\") are considered as the " +
+				"current one, and the command fails if the next version does not satisfy it. Useful on maintenance branches.",
+			Value: "",
+		},
 		&cli.BoolFlag{
 			Name:    failFlag,
 			EnvVars: common.EnvFor(failFlag),
@@ -97,57 +153,148 @@ next-version will exit with an error if no previous versions are found in the gi
 			Value: false,
 		},
 	},
+	Before: config.Apply,
 	Action: NextVersion,
 }
 
+// Options holds the settings of next-version, which are taken from its flags by OptionsFromCli.
+type Options struct {
+	// YAML is the path to the changelog.yaml the next version is computed from.
+	YAML       string
+	GitRoot    string
+	GitBackend git.Backend
+	TagPrefix  string
+	// OutputPrefix is prepended to the versions printed and set as outputs by next-version.
+	OutputPrefix string
+	// Current, if not empty, overrides the current version found in the tags of the repository.
+	Current string
+	// Next, if not empty, overrides the computed next version.
+	Next          string
+	BumpCap       string
+	DependencyCap string
+	Prerelease    string
+	Promote       bool
+	Scheme        string
+	// Now returns the date CalVer versions are computed for. It defaults to time.Now.
+	Now func() time.Time
+	// PadMonth makes CalVer zero-pad the month of new versions even if the current one is not. See version.CalVer.
+	PadMonth bool
+	// VersionConstraint, if not empty, restricts the tags considered as the current version and the next version.
+	VersionConstraint string
+	// Fail makes Run fail if the changelog does not produce a new version, instead of returning the current one.
+	Fail bool
+}
+
+// OptionsFromCli returns the Options set by the flags of next-version in cCtx.
+func OptionsFromCli(cCtx *cli.Context) Options {
+	opts := Options{
+		YAML:              cCtx.String(common.YAMLFlag),
+		GitRoot:           cCtx.String(gitRootFlag),
+		GitBackend:        git.Backend(cCtx.String(common.GitBackendFlag)),
+		TagPrefix:         cCtx.String(tagPrefix),
+		OutputPrefix:      cCtx.String(outputPrefix),
+		Current:           cCtx.String(currentFlag),
+		Next:              cCtx.String(nextFlag),
+		BumpCap:           cCtx.String(BumpCapFlag),
+		DependencyCap:     cCtx.String(DependencyCapFlag),
+		Prerelease:        cCtx.String(prereleaseFlag),
+		Promote:           cCtx.Bool(promoteFlag),
+		Scheme:            cCtx.String(schemeFlag),
+		PadMonth:          cCtx.Bool(padMonthFlag),
+		VersionConstraint: cCtx.String(constraintFlag),
+		Fail:              cCtx.Bool(failFlag),
+	}
+
+	if t := cCtx.Timestamp(dateFlag); t != nil {
+		date := *t
+		opts.Now = func() time.Time {
+			return date
+		}
+	}
+
+	return opts
+}
+
 // NextVersion is a command function which loads a changelog.yaml file from disk and computes what the next version
 // should be according to semver standards.
-//
-//nolint:gocyclo,cyclop
+// If a monorepo manifest is supplied, the next version is computed for each of its components instead.
 func NextVersion(cCtx *cli.Context) error {
+	opts := OptionsFromCli(cCtx)
+
+	if manifestPath := cCtx.String(common.MonorepoFlag); manifestPath != "" {
+		return nextVersionMonorepo(cCtx, opts, manifestPath)
+	}
+
 	gh := gha.NewFromCli(cCtx)
 
-	nextOverride, err := parseNextFlag(cCtx.String(nextFlag))
+	next, err := Run(opts)
 	if err != nil {
 		return err
 	}
 
-	chPath := cCtx.String(common.YAMLFlag)
-	chFile, err := os.Open(chPath)
+	prefix := opts.OutputPrefix
+	// Versions are formatted keeping the zero-padded month of CalVer versions.
+	formatted := version.Format(next)
+	parts := strings.SplitN(formatted, ".", 3)
+	_, _ = fmt.Fprintf(cCtx.App.Writer, "%s\n", prefix+formatted)
+	gh.SetOutput(nextVersionOutput, prefix+formatted)
+	gh.SetOutput(majorOutput, prefix+parts[0])
+	gh.SetOutput(majorMinorOutput, prefix+parts[0]+"."+parts[1])
+
+	return nil
+}
+
+// Run computes the next version of a single repository as configured by opts.
+//
+//nolint:gocyclo,cyclop
+func Run(opts Options) (*semver.Version, error) {
+	nextOverride, err := parseNextFlag(opts.Next)
 	if err != nil {
-		return fmt.Errorf("opening changelog file %q: %w", chPath, err)
+		return nil, err
 	}
 
-	ch := changelog.Changelog{}
-	err = yaml.NewDecoder(chFile).Decode(&ch)
+	ch, err := loadChangelog(opts.YAML)
 	if err != nil {
-		return fmt.Errorf("loading changelog from file: %w", err)
+		return nil, err
 	}
 
-	versionSrc, err := source(cCtx)
+	scheme, err := schemeFor(opts)
 	if err != nil {
-		return err
+		return nil, fmt.Errorf("parsing versioning scheme: %w", err)
 	}
 
-	entryCap, err := bump.NameToType(cCtx.String(BumpCapFlag))
+	constraint, err := versionConstraint(opts.VersionConstraint)
 	if err != nil {
-		return fmt.Errorf("parsing version bump cap: %w", err)
+		return nil, err
 	}
-	dependencyCap, err := bump.NameToType(cCtx.String(DependencyCapFlag))
+
+	versionSrc, err := source(opts, scheme, constraint)
 	if err != nil {
-		return fmt.Errorf("parsing dependency bump: %w", err)
+		return nil, err
 	}
 
-	bmpr := bumper.New(ch)
-	bmpr.EntryCap = entryCap
-	bmpr.DependencyCap = dependencyCap
+	bmpr, err := newBumper(opts, ch, scheme, constraint)
+	if err != nil {
+		return nil, err
+	}
 
 	next, err := bmpr.BumpSource(versionSrc)
 
 	// Other errors are computed after checking for overrides in the switch statement.
 	if errors.Is(err, bumper.ErrEmptySource) {
 		log.Errorf("Refusing to compute next version as no previous version was found. Please create an initial version first.")
-		return fmt.Errorf("computing next version: %w", err)
+		return nil, fmt.Errorf("computing next version: %w", err)
+	}
+
+	if errors.Is(err, bumper.ErrNoPrerelease) || errors.Is(err, bumper.ErrInvalidPrereleaseID) ||
+		errors.Is(err, bumper.ErrPrereleaseAndPromote) || errors.Is(err, bumper.ErrPrereleaseDowngrade) {
+		return nil, fmt.Errorf("computing next version: %w", err)
+	}
+
+	if errors.Is(err, bumper.ErrOutsideConstraint) && nextOverride == nil {
+		log.Errorf("Refusing to release a version outside of %q. Changes requiring a larger bump cannot be released "+
+			"from this maintenance line.", constraint)
+		return nil, fmt.Errorf("computing next version: %w", err)
 	}
 
 	switch {
@@ -167,23 +314,205 @@ func NextVersion(cCtx *cli.Context) error {
 		// If we don't have an override, the bumper did not bump anything, and the user has set `--fail`, then we should error out.
 		if errors.Is(err, bumper.ErrNoNewVersion) {
 			log.Warnf("None of the changelog entries produced a version bump, returning current version")
-			if cCtx.Bool("fail") {
-				return fmt.Errorf("failing by user request: %w", err)
+			if opts.Fail {
+				return nil, fmt.Errorf("failing by user request: %w", err)
 			}
 		}
 
 	case nextOverride == nil && next == nil:
-		return fmt.Errorf("bumping source: %w", err)
+		return nil, fmt.Errorf("bumping source: %w", err)
+	}
+
+	return next, nil
+}
+
+// nextVersionMonorepo computes the next version of each component listed in the manifest, using the component's
+// changelog.yaml and the tags matching its prefix. Versions are recorded in the monorepo summary, and components
+// without a new version are marked as not needing a release. Components without any version yet are marked as needing
+// an initial one. If --fail is set, it fails after writing the summary if no component got a new version.
+//
+//nolint:gocyclo,cyclop
+func nextVersionMonorepo(cCtx *cli.Context, opts Options, manifestPath string) error {
+	gh := gha.NewFromCli(cCtx)
+
+	if opts.Current != "" || opts.Next != "" {
+		return ErrMonorepoOverride
+	}
+
+	manifest, err := monorepo.Load(manifestPath)
+	if err != nil {
+		return fmt.Errorf("loading monorepo manifest: %w", err)
+	}
+
+	summary, err := monorepo.ReadSummary(manifest.Summary)
+	if err != nil {
+		return fmt.Errorf("loading monorepo summary: %w", err)
+	}
+
+	scheme, err := schemeFor(opts)
+	if err != nil {
+		return fmt.Errorf("parsing versioning scheme: %w", err)
+	}
+
+	constraint, err := versionConstraint(opts.VersionConstraint)
+	if err != nil {
+		return err
+	}
+
+	// All components share the same repository, so its history and tags are read only once.
+	repo, err := git.NewRepoWithBackend(opts.GitRoot, opts.GitBackend)
+	if err != nil {
+		return fmt.Errorf("opening repository: %w", err)
+	}
+
+	bumped := false
+	for _, component := range manifest.Components {
+		ch, cErr := loadChangelog(component.YAML)
+		if cErr != nil {
+			return fmt.Errorf("component %q: %w", component.Name, cErr)
+		}
+
+		versionSrc, cErr := tagsSource(repo, component.TagPrefix, scheme, constraint)
+		if cErr != nil {
+			return fmt.Errorf("component %q: %w", component.Name, cErr)
+		}
+
+		bmpr, cErr := newBumper(opts, ch, scheme, constraint)
+		if cErr != nil {
+			return cErr
+		}
+
+		cs := summary.Component(component.Name)
+		cs.Empty = ch.Empty()
+		cs.Held = ch.Held
+		cs.Current = ""
+		cs.Next = ""
+		cs.Release = false
+		cs.NeedsInitialVersion = false
+
+		next, cErr := bmpr.BumpSource(versionSrc)
+		switch {
+		case errors.Is(cErr, bumper.ErrEmptySource):
+			log.Warnf("Component %q has no version yet, please create an initial version first", component.Name)
+			cs.NeedsInitialVersion = true
+			continue
+		case errors.Is(cErr, bumper.ErrNoNewVersion):
+			log.Infof("Component %q does not need a new version", component.Name)
+		case cErr != nil:
+			return fmt.Errorf("computing next version for component %q: %w", component.Name, cErr)
+		default:
+			cs.Next = opts.OutputPrefix + version.Format(next)
+			cs.Release = !cs.Empty && !cs.Held
+			bumped = true
+		}
+
+		// Versions are sorted from largest to smallest, and BumpSource already checked there is at least one.
+		versions, cErr := versionSrc.Versions()
+		if cErr != nil {
+			return fmt.Errorf("getting versions for component %q: %w", component.Name, cErr)
+		}
+		cs.Current = opts.OutputPrefix + version.Format(versions[0])
+
+		if cs.Release {
+			_, _ = fmt.Fprintf(cCtx.App.Writer, "%s %s\n", component.Name, cs.Next)
+			gh.SetOutput(component.Name+"-"+nextVersionOutput, cs.Next)
+		}
 	}
 
-	_, _ = fmt.Fprintf(cCtx.App.Writer, "%s\n", fmt.Sprintf("%s%s", cCtx.String(outputPrefix), next.String()))
-	gh.SetOutput(nextVersionOutput, fmt.Sprintf("%s%s", cCtx.String(outputPrefix), next.String()))
-	gh.SetOutput(majorOutput, fmt.Sprintf("%s%d", cCtx.String(outputPrefix), next.Major()))
-	gh.SetOutput(majorMinorOutput, fmt.Sprintf("%s%d.%d", cCtx.String(outputPrefix), next.Major(), next.Minor()))
+	if err = summary.Write(manifest.Summary); err != nil {
+		return fmt.Errorf("writing monorepo summary: %w", err)
+	}
+
+	gh.SetOutput(releaseComponentsOutput, strings.Join(summary.Releasing(), ","))
+	gh.AddSummary(markdownSummary(summary))
+
+	if !bumped && opts.Fail {
+		return fmt.Errorf("failing by user request: %w", bumper.ErrNoNewVersion)
+	}
 
 	return nil
 }
 
+// markdownSummary renders a table with the versions of each component, to be shown as the step summary.
+func markdownSummary(summary *monorepo.Summary) string {
+	buf := &strings.Builder{}
+	buf.WriteString("| Component | Current | Next | Release |\n")
+	buf.WriteString("|-----------|---------|------|---------|\n")
+	for _, cs := range summary.Components {
+		next := cs.Next
+		if cs.NeedsInitialVersion {
+			next = "needs an initial version"
+		}
+		_, _ = fmt.Fprintf(buf, "| %s | %s | %s | %v |\n", cs.Name, cs.Current, next, cs.Release)
+	}
+
+	return buf.String()
+}
+
+// schemeFor returns the versioning scheme selected in opts, configured with its CalVer settings.
+//
+//nolint:ireturn // The scheme is chosen at runtime.
+func schemeFor(opts Options) (version.Scheme, error) {
+	//nolint:wrapcheck // Callers add context.
+	return version.SchemeFor(opts.Scheme, version.CalVerNow(opts.Now), version.CalVerPadMonth(opts.PadMonth))
+}
+
+func loadChangelog(chPath string) (changelog.Changelog, error) {
+	chFile, err := os.Open(chPath)
+	if err != nil {
+		return changelog.Changelog{}, fmt.Errorf("opening changelog file %q: %w", chPath, err)
+	}
+	defer chFile.Close()
+
+	ch := changelog.Changelog{}
+	err = yaml.NewDecoder(chFile).Decode(&ch)
+	if err != nil {
+		return changelog.Changelog{}, fmt.Errorf("loading changelog from file: %w", err)
+	}
+
+	return ch, nil
+}
+
+// newBumper returns a bumper for ch configured according to opts.
+func newBumper(
+	opts Options, ch changelog.Changelog, scheme version.Scheme, constraint *version.Constraint,
+) (bumper.Bumper, error) {
+	entryCap, err := bump.NameToType(opts.BumpCap)
+	if err != nil {
+		return bumper.Bumper{}, fmt.Errorf("parsing version bump cap: %w", err)
+	}
+	dependencyCap, err := bump.NameToType(opts.DependencyCap)
+	if err != nil {
+		return bumper.Bumper{}, fmt.Errorf("parsing dependency bump: %w", err)
+	}
+
+	bmpr := bumper.New(ch)
+	bmpr.EntryCap = entryCap
+	bmpr.DependencyCap = dependencyCap
+	bmpr.Prerelease = opts.Prerelease
+	bmpr.Promote = opts.Promote
+	bmpr.Scheme = scheme
+	bmpr.Constraint = constraint
+
+	return bmpr, nil
+}
+
+// versionConstraint parses c, returning nil if it is empty.
+//
+//nolint:nilnil // A nil constraint means versions are not constrained.
+func versionConstraint(c string) (*version.Constraint, error) {
+	if c == "" {
+		return nil, nil
+	}
+
+	constraint, err := version.NewConstraint(c)
+	if err != nil {
+		return nil, fmt.Errorf("parsing version constraint: %w", err)
+	}
+
+	return constraint, nil
+}
+
 //nolint:nilnil // A sentinel error would be better, but we don't bother as this fn is unexported and used only once.
 func parseNextFlag(override string) (*semver.Version, error) {
 	if override == "" {
@@ -199,28 +528,41 @@ func parseNextFlag(override string) (*semver.Version, error) {
 }
 
 //nolint:ireturn,nolintlint // I do want to return an interface here.
-func source(cCtx *cli.Context) (version.Source, error) {
-	if override := cCtx.String(currentFlag); override != "" {
+func source(opts Options, scheme version.Scheme, constraint *version.Constraint) (version.Source, error) {
+	if override := opts.Current; override != "" {
 		return version.Static(override), nil
 	}
 
-	workDir := cCtx.String(gitRootFlag)
-	getter := git.NewRepoCommitsGetter(workDir)
+	repo, err := git.NewRepoWithBackend(opts.GitRoot, opts.GitBackend)
+	if err != nil {
+		return nil, fmt.Errorf("opening repository: %w", err)
+	}
+
+	return tagsSource(repo, opts.TagPrefix, scheme, constraint)
+}
 
-	tagOpts := []git.TagOptionFunc{git.TagsMatchingCommits(getter)}
-	if prefix := cCtx.String(tagPrefix); prefix != "" {
+// tagsSource returns a version source for the tags in repo that start with prefix and point to commits reachable from
+// HEAD. If constraint is not nil, only versions satisfying it are returned.
+func tagsSource(
+	repo *git.Repo, prefix string, scheme version.Scheme, constraint *version.Constraint,
+) (*git.TagsSource, error) {
+	tagOpts := []git.TagOptionFunc{git.TagsMatchingCommits(repo)}
+	if prefix != "" {
 		tagOpts = append(tagOpts, git.TagsMatchingRegex("^"+prefix))
 	}
 
-	tg, err := git.NewRepoTagsGetter(workDir, tagOpts...)
+	tg, err := repo.TagsGetter(tagOpts...)
 	if err != nil {
 		return nil, fmt.Errorf("building repo tags lister: %w", err)
 	}
 
-	var srcOpts []git.TagSourceOptionFunc
-	if prefix := cCtx.String(tagPrefix); prefix != "" {
+	srcOpts := []git.TagSourceOptionFunc{git.TagSourceScheme(scheme)}
+	if prefix != "" {
 		srcOpts = append(srcOpts, git.TagSourceReplacing(prefix, ""))
 	}
+	if constraint != nil {
+		srcOpts = append(srcOpts, git.TagSourceConstraint(constraint))
+	}
 
 	return git.NewTagsSource(tg, srcOpts...), nil
 }
@@ -10,11 +10,19 @@ import (
 	"testing"
 
 	"github.com/newrelic/release-toolkit/src/app"
+	"github.com/newrelic/release-toolkit/src/app/common"
+	"github.com/newrelic/release-toolkit/src/app/nextversion"
 	"github.com/newrelic/release-toolkit/src/bump"
+	"github.com/newrelic/release-toolkit/src/bumper"
+	"github.com/newrelic/release-toolkit/src/version"
 )
 
 //nolint:paralleltest, funlen // urfave/cli cannot be tested concurrently.
 func TestNextVersion_Without_Repo(t *testing.T) {
+	// Workflow commands are asserted on stdout, so environment files set when running on Github Actions are ignored.
+	t.Setenv(common.GHAOutputEnv, "")
+	t.Setenv(common.GHAStepSummaryEnv, "")
+
 	for _, tc := range []struct {
 		name          string
 		yaml          string
@@ -45,6 +53,37 @@ dependencies:
   to: 0.1.0
 			`),
 		},
+		{
+			name:     "Bumps_CalVer",
+			args:     "-current v2024.02.5 -scheme calver -date 2024-03-15",
+			expected: "v2024.03.0",
+			yaml: strings.TrimSpace(`
+changes:
+- type: enhancement
+  message: New feature has been added
+			`),
+		},
+		{
+			name:       "Bumps_CalVer_GHA",
+			globalargs: "-gha=true",
+			args:       "-current v2024.03.5 -scheme calver -date 2024-03-15",
+			expected:   "v2024.03.6\n::set-output name=next-version::v2024.03.6\n::set-output name=next-version-major::v2024\n::set-output name=next-version-major-minor::v2024.03",
+			yaml: strings.TrimSpace(`
+changes:
+- type: bugfix
+  message: Just a quick fix
+			`),
+		},
+		{
+			name:     "Bumps_CalVer_Pad_Month",
+			args:     "-current v2023.12.1 -scheme calver -date 2024-01-10 -calver-pad-month",
+			expected: "v2024.01.0",
+			yaml: strings.TrimSpace(`
+changes:
+- type: bugfix
+  message: Just a quick fix
+			`),
+		},
 		{
 			name:     "Bumps_Patch",
 			args:     "-current v1.2.3",
@@ -328,6 +367,39 @@ dependencies:
   to: 1.0.0
 			`),
 		},
+		{
+			name:     "Starts_Prerelease",
+			expected: "v3.0.0-rc.1",
+			args:     "--prerelease rc",
+			tags:     allTags,
+			yaml: strings.TrimSpace(`
+changes:
+- type: breaking
+  message: Support has been removed
+			`),
+		},
+		{
+			name:     "Increments_Prerelease",
+			expected: "v3.0.0-rc.3",
+			args:     "--prerelease rc",
+			tags:     append([]string{"v3.0.0-rc.1", "v3.0.0-rc.2"}, allTags...),
+			yaml: strings.TrimSpace(`
+changes:
+- type: bugfix
+  message: Just a quick fix
+			`),
+		},
+		{
+			name:     "Promotes_Prerelease",
+			expected: "v3.0.0",
+			args:     "--promote",
+			tags:     append([]string{"v3.0.0-rc.1", "v3.0.0-rc.2"}, allTags...),
+			yaml: strings.TrimSpace(`
+changes:
+- type: bugfix
+  message: Just a quick fix
+			`),
+		},
 		{
 			name:     "When_Repo_Has_No_Canges_But_Fail_Is_False",
 			expected: "v0.1.0",
@@ -530,6 +602,120 @@ dependencies:
 	}
 }
 
+// maintenanceRepo creates a repository with a release/1.x branch checked out, where v1.4.0 was tagged, that merged
+// main after v2.3.0 was tagged there.
+func maintenanceRepo(t *testing.T) string {
+	t.Helper()
+
+	dir := t.TempDir()
+
+	for _, cmdline := range []string{
+		"git init --initial-branch main",
+		"git config user.email test@user.tld",
+		"git config user.name Test",
+		"git config commit.gpgsign false",
+		"touch a",
+		"git add a",
+		"git commit -m test",
+		"git tag v1.4.0",
+		"git checkout -b release/1.x",
+		"git checkout main",
+		"touch b",
+		"git add b",
+		"git commit -m test",
+		"git tag v2.3.0",
+		"git checkout release/1.x",
+		"git merge --no-ff -m merge main",
+	} {
+		cmdparts := strings.Fields(cmdline)
+		//nolint:gosec // This is a test, we trust hardcoded input.
+		cmd := exec.Command(cmdparts[0], cmdparts[1:]...)
+		cmd.Dir = dir
+
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Errorf("%s output:\n%s", cmdline, out)
+			t.Fatalf("Error bootstrapping test git repo: %v", err)
+		}
+	}
+
+	return dir
+}
+
+//nolint:paralleltest // urfave/cli cannot be tested concurrently.
+func TestNextVersion_Constraint(t *testing.T) {
+	t.Setenv(common.GHAOutputEnv, "")
+	t.Setenv(common.GHAStepSummaryEnv, "")
+
+	for _, tc := range []struct {
+		name          string
+		changeType    string
+		args          string
+		expected      string
+		errorExpected error
+	}{
+		{
+			name:       "Without_Constraint_Bumps_Merged_Version",
+			changeType: "bugfix",
+			expected:   "v2.3.1",
+		},
+		{
+			name:       "Bumps_Version_Matching_Constraint",
+			changeType: "bugfix",
+			args:       "-version-constraint ~1.4",
+			expected:   "v1.4.1",
+		},
+		{
+			name:       "Bumps_Minor_Within_Major_Line",
+			changeType: "enhancement",
+			args:       "-version-constraint <2.0.0",
+			expected:   "v1.5.0",
+		},
+		{
+			name:          "Refuses_Breaking_On_Major_Line",
+			changeType:    "breaking",
+			args:          "-version-constraint <2.0.0",
+			errorExpected: bumper.ErrOutsideConstraint,
+		},
+		{
+			name:       "Override_Takes_Precedence",
+			changeType: "breaking",
+			args:       "-version-constraint <2.0.0 -next v2.0.0",
+			expected:   "v2.0.0",
+		},
+		{
+			name:          "Invalid_Constraint",
+			changeType:    "bugfix",
+			args:          "-version-constraint ~one",
+			errorExpected: version.ErrConstraintNotValid,
+		},
+	} {
+		tc := tc
+		//nolint:paralleltest // urfave/cli cannot be tested concurrently.
+		t.Run(tc.name, func(t *testing.T) {
+			repoDir := maintenanceRepo(t)
+
+			yamlPath := path.Join(t.TempDir(), "changelog.yaml")
+			yaml := fmt.Sprintf("changes:\n- type: %s\n  message: A change\n", tc.changeType)
+			if err := os.WriteFile(yamlPath, []byte(yaml), 0o600); err != nil {
+				t.Fatalf("Error creating yaml for test: %v", err)
+			}
+
+			app := app.App()
+			buf := &strings.Builder{}
+			app.Writer = buf
+
+			err := app.Run(strings.Fields(fmt.Sprintf("rt -yaml %s next-version -git-root %s %s", yamlPath, repoDir, tc.args)))
+			if !errors.Is(err, tc.errorExpected) {
+				t.Fatalf("Expected error %v, got %v", tc.errorExpected, err)
+			}
+
+			if actual := strings.TrimSpace(buf.String()); actual != tc.expected {
+				t.Fatalf("Expected %q, got %q", tc.expected, actual)
+			}
+		})
+	}
+}
+
 func repoWithTags(t *testing.T, tags ...string) string {
 	t.Helper()
 
@@ -566,3 +752,161 @@ func repoWithTags(t *testing.T, tags ...string) string {
 
 	return dir
 }
+
+//nolint:paralleltest // urfave/cli cannot be tested concurrently.
+func TestNextVersion_Monorepo(t *testing.T) {
+	repoDir := repoWithTags(t, "agent-v1.2.3", "agent-v1.2.0", "operator-v0.4.1", "held-v3.0.0", "v9.9.9")
+
+	files := map[string]string{
+		"monorepo.yaml": strings.TrimSpace(`
+components:
+- name: agent
+  path: agent
+  tag-prefix: agent-
+- name: operator
+  path: operator
+  tag-prefix: operator-
+- name: held
+  path: held
+  tag-prefix: held-
+- name: new
+  path: new
+  tag-prefix: new-
+		`),
+		"release-summary.yaml": strings.TrimSpace(`
+components:
+- name: agent
+  release: true
+- name: operator
+  empty: true
+		`),
+		"agent/changelog.yaml": strings.TrimSpace(`
+changes:
+- type: enhancement
+  message: New feature has been added
+		`),
+		"operator/changelog.yaml": "notes: \"\"\nchanges: []\n",
+		"held/changelog.yaml": strings.TrimSpace(`
+held: true
+changes:
+- type: bugfix
+  message: Just a quick fix
+		`),
+		"new/changelog.yaml": strings.TrimSpace(`
+changes:
+- type: enhancement
+  message: First feature
+		`),
+	}
+	for name, content := range files {
+		_ = os.MkdirAll(path.Dir(path.Join(repoDir, name)), 0o755)
+		if err := os.WriteFile(path.Join(repoDir, name), []byte(content), 0o600); err != nil {
+			t.Fatalf("Error creating %s: %v", name, err)
+		}
+	}
+
+	outputPath := path.Join(t.TempDir(), "github-output")
+	summaryPath := path.Join(t.TempDir(), "github-step-summary")
+	if err := os.WriteFile(outputPath, nil, 0o600); err != nil {
+		t.Fatalf("Error creating output file: %v", err)
+	}
+	if err := os.WriteFile(summaryPath, nil, 0o600); err != nil {
+		t.Fatalf("Error creating summary file: %v", err)
+	}
+	t.Setenv(common.GHAOutputEnv, outputPath)
+	t.Setenv(common.GHAStepSummaryEnv, summaryPath)
+
+	app := app.App()
+	buf := &strings.Builder{}
+	app.Writer = buf
+
+	manifestPath := path.Join(repoDir, "monorepo.yaml")
+	err := app.Run(strings.Fields(fmt.Sprintf("rt --gha --monorepo %s next-version -git-root %s", manifestPath, repoDir)))
+	if err != nil {
+		t.Fatalf("Error running app: %v", err)
+	}
+
+	if expected, actual := "agent v1.3.0\n", buf.String(); actual != expected {
+		t.Fatalf("Expected %q, got %q", expected, actual)
+	}
+
+	summary, err := os.ReadFile(path.Join(repoDir, "release-summary.yaml"))
+	if err != nil {
+		t.Fatalf("Error reading summary: %v", err)
+	}
+
+	expectedSummary := strings.TrimSpace(`
+components:
+    - name: agent
+      empty: false
+      held: false
+      current: v1.2.3
+      next: v1.3.0
+      release: true
+    - name: operator
+      empty: true
+      held: false
+      current: v0.4.1
+      release: false
+    - name: held
+      empty: false
+      held: true
+      current: v3.0.0
+      next: v3.0.1
+      release: false
+    - name: new
+      empty: false
+      held: false
+      release: false
+      needs-initial-version: true
+	`) + "\n"
+	if actual := string(summary); actual != expectedSummary {
+		t.Fatalf("Expected summary:\n%s\ngot:\n%s", expectedSummary, actual)
+	}
+
+	output, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("Error reading outputs: %v", err)
+	}
+	if expected, actual := "agent-next-version=v1.3.0\nrelease-components=agent\n", string(output); actual != expected {
+		t.Fatalf("Expected outputs %q, got %q", expected, actual)
+	}
+
+	stepSummary, err := os.ReadFile(summaryPath)
+	if err != nil {
+		t.Fatalf("Error reading step summary: %v", err)
+	}
+	expectedStepSummary := strings.TrimSpace(`
+| Component | Current | Next | Release |
+|-----------|---------|------|---------|
+| agent | v1.2.3 | v1.3.0 | true |
+| operator | v0.4.1 |  | false |
+| held | v3.0.0 | v3.0.1 | false |
+| new |  | needs an initial version | false |
+	`) + "\n"
+	if actual := string(stepSummary); actual != expectedStepSummary {
+		t.Fatalf("Expected step summary:\n%s\ngot:\n%s", expectedStepSummary, actual)
+	}
+
+	err = app.Run(strings.Fields(fmt.Sprintf("rt --monorepo %s next-version -git-root %s -current v1.0.0", manifestPath, repoDir)))
+	if !errors.Is(err, nextversion.ErrMonorepoOverride) {
+		t.Fatalf("Expected %v, got %v", nextversion.ErrMonorepoOverride, err)
+	}
+
+	err = app.Run(strings.Fields(fmt.Sprintf("rt --monorepo %s next-version -git-root %s -fail", manifestPath, repoDir)))
+	if err != nil {
+		t.Fatalf("Expected no error as some components have a new version, got %v", err)
+	}
+
+	// Without changes, no component gets a new version.
+	for _, component := range []string{"agent", "held", "new"} {
+		if err = os.WriteFile(path.Join(repoDir, component, "changelog.yaml"), []byte("changes: []\n"), 0o600); err != nil {
+			t.Fatalf("Error emptying changelog: %v", err)
+		}
+	}
+
+	err = app.Run(strings.Fields(fmt.Sprintf("rt --monorepo %s next-version -git-root %s -fail", manifestPath, repoDir)))
+	if !errors.Is(err, bumper.ErrNoNewVersion) {
+		t.Fatalf("Expected %v, got %v", bumper.ErrNoNewVersion, err)
+	}
+}
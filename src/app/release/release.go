@@ -0,0 +1,408 @@
+// Package release implements the release command, which runs the whole release pipeline in a single invocation.
+package release
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"github.com/Masterminds/semver"
+	"github.com/newrelic/release-toolkit/src/app/common"
+	"github.com/newrelic/release-toolkit/src/app/config"
+	"github.com/newrelic/release-toolkit/src/app/generate"
+	"github.com/newrelic/release-toolkit/src/app/gha"
+	"github.com/newrelic/release-toolkit/src/app/link"
+	"github.com/newrelic/release-toolkit/src/app/nextversion"
+	"github.com/newrelic/release-toolkit/src/app/render"
+	"github.com/newrelic/release-toolkit/src/app/update"
+	"github.com/newrelic/release-toolkit/src/changelog/sources/markdown"
+	"github.com/newrelic/release-toolkit/src/version"
+	"github.com/pmezard/go-difflib/difflib"
+	log "github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	markdownPathFlag                 = "markdown"
+	partialMarkdownPathFlag          = "partial-markdown"
+	gitRootFlag                      = "git-root"
+	tagPrefixFlag                    = "tag-prefix"
+	versionConstraintFlag            = "version-constraint"
+	dictionaryPathFlag               = "dictionary"
+	excludedDependenciesManifestFlag = "excluded-dependencies-manifest"
+	dryRunFlag                       = "dry-run"
+)
+
+const (
+	nextVersionOutput     = "next-version"
+	majorOutput           = "next-version-major"
+	majorMinorOutput      = "next-version-major-minor"
+	partialMarkdownOutput = "partial-markdown"
+)
+
+// Exit codes returned when the release cannot proceed. Other errors exit with 1.
+const (
+	ExitCodeInvalid = 3
+	ExitCodeEmpty   = 4
+	ExitCodeHeld    = 5
+)
+
+// diffContextLines is the number of unchanged lines shown around changes in dry run diffs.
+const diffContextLines = 3
+
+// ErrMonorepoUnsupported is returned if the release command is run with a monorepo manifest.
+var ErrMonorepoUnsupported = errors.New("release does not support monorepo mode yet, run each command instead")
+
+// Cmd is the cli.Command object for the release command.
+//
+//nolint:gochecknoglobals // We could overengineer this to avoid the global command but I don't think it's worth it.
+var Cmd = &cli.Command{
+	Name:  "release",
+	Usage: "Runs the whole release pipeline: validates CHANGELOG.md, generates and links changelog.yaml, computes the next version and renders the changelogs.",
+	UsageText: `Release runs, in order, validate-markdown, generate-yaml, is-empty, is-held, link-dependencies, next-version,
+update-markdown and render-changelog. Flags of each command not exposed by release can be set in the config file.
+Release exits with code 3 if CHANGELOG.md is invalid, 4 if the changelog is empty and 5 if it is held.`,
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:    markdownPathFlag,
+			EnvVars: common.EnvFor(markdownPathFlag),
+			Usage:   "Path to the CHANGELOG.md file to validate, gather entries from, and update.",
+			Value:   "CHANGELOG.md",
+		},
+		&cli.StringFlag{
+			Name:    partialMarkdownPathFlag,
+			EnvVars: common.EnvFor(partialMarkdownPathFlag),
+			Usage:   "Path where the changelog of this release alone is rendered.",
+			Value:   "CHANGELOG.partial.md",
+		},
+		&cli.StringFlag{
+			Name:    gitRootFlag,
+			EnvVars: common.EnvFor(gitRootFlag),
+			Usage:   "Path to the git repo to get commits and tags for.",
+			Value:   "./",
+		},
+		&cli.StringFlag{
+			Name:    tagPrefixFlag,
+			EnvVars: common.EnvFor(tagPrefixFlag),
+			Usage:   "Consider only tags matching this prefix, both to find commits and the current version.",
+			Value:   "",
+		},
+		&cli.StringFlag{
+			Name:    versionConstraintFlag,
+			EnvVars: common.EnvFor(versionConstraintFlag),
+			Usage: "Consider only versions satisfying this constraint (e.g. \"~1.4\" or \"<2.0.0\"), and fail if the " +
+				"next version does not satisfy it. Useful on maintenance branches.",
+			Value: "",
+		},
+		&cli.StringFlag{
+			Name:    dictionaryPathFlag,
+			EnvVars: common.EnvFor(dictionaryPathFlag),
+			Usage:   "Path to a dictionary file mapping dependencies to their changelogs. See link-dependencies.",
+			Value:   "",
+		},
+		&cli.StringFlag{
+			Name:    excludedDependenciesManifestFlag,
+			EnvVars: common.EnvFor(excludedDependenciesManifestFlag),
+			Usage:   "Path to a YAML file with the list of dependencies excluded from the changelog. See generate-yaml.",
+			Value:   "",
+		},
+		&cli.BoolFlag{
+			Name:    dryRunFlag,
+			EnvVars: common.EnvFor(dryRunFlag),
+			Usage:   "If set, no file is modified. Instead, the changes that would have been made are printed as diffs.",
+			Value:   false,
+		},
+	},
+	Before: config.Apply,
+	Action: Release,
+}
+
+// files holds the paths of the files written during the release.
+type files struct {
+	yaml            string
+	markdown        string
+	partialMarkdown string
+}
+
+func (f files) list() []string {
+	return []string{f.yaml, f.markdown, f.partialMarkdown}
+}
+
+// Release is a command function that runs all the steps needed to release a new version, calling the other commands
+// in process. Outputs for the new version are emitted once all steps have succeeded.
+func Release(cCtx *cli.Context) error {
+	if cCtx.String(common.MonorepoFlag) != "" {
+		return ErrMonorepoUnsupported
+	}
+
+	target := files{
+		yaml:            cCtx.String(common.YAMLFlag),
+		markdown:        cCtx.String(markdownPathFlag),
+		partialMarkdown: cCtx.String(partialMarkdownPathFlag),
+	}
+
+	if !cCtx.Bool(dryRunFlag) {
+		next, err := run(cCtx, target, false)
+		if err != nil {
+			return err
+		}
+
+		_, _ = fmt.Fprintf(cCtx.App.Writer, "%s\n", next)
+		return setOutputs(gha.NewFromCli(cCtx), next, target.partialMarkdown)
+	}
+
+	tmpDir, err := os.MkdirTemp("", "release-toolkit-dry-run-")
+	if err != nil {
+		return fmt.Errorf("creating temporary directory for dry run: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	// Steps are run against copies of the files so the originals are left untouched.
+	scratch := files{}
+	scratchPaths := []*string{&scratch.yaml, &scratch.markdown, &scratch.partialMarkdown}
+	for i, path := range target.list() {
+		*scratchPaths[i] = filepath.Join(tmpDir, fmt.Sprintf("%d-%s", i, filepath.Base(path)))
+		if err = copyIfExists(path, *scratchPaths[i]); err != nil {
+			return err
+		}
+	}
+
+	next, err := run(cCtx, scratch, true)
+	if err != nil {
+		return err
+	}
+
+	for i, path := range target.list() {
+		if err = printDiff(cCtx.App.Writer, path, scratch.list()[i]); err != nil {
+			return err
+		}
+	}
+
+	_, _ = fmt.Fprintf(cCtx.App.Writer, "%s\n", next)
+
+	return nil
+}
+
+// run executes all the release steps, reading and writing the supplied files, and returns the next version. Options
+// of each step not set by release are taken from the flags of its command, so they can be set in the config file.
+// In dry runs, no file other than the supplied ones is written.
+func run(cCtx *cli.Context, f files, dryRun bool) (string, error) {
+	gitRoot := cCtx.String(gitRootFlag)
+
+	log.Infof("Validating %s", f.markdown)
+	if err := validateMarkdown(cCtx.App.ErrWriter, f.markdown); err != nil {
+		return "", err
+	}
+
+	log.Infof("Generating %s", f.yaml)
+	genCtx, err := config.CommandContext(cCtx, generate.Cmd)
+	if err != nil {
+		return "", err
+	}
+	genOpts := generate.OptionsFromCli(genCtx)
+	genOpts.YAML = f.yaml
+	genOpts.Markdown = f.markdown
+	genOpts.GitRoot = gitRoot
+	// generate-yaml and next-version use the same tags, so they agree on the latest version.
+	genOpts.TagPrefix = cCtx.String(tagPrefixFlag)
+	genOpts.VersionConstraint = cCtx.String(versionConstraintFlag)
+	if manifest := cCtx.String(excludedDependenciesManifestFlag); manifest != "" {
+		genOpts.ExcludedDependenciesManifest = manifest
+	}
+
+	ch, err := generate.Run(genOpts)
+	if err != nil {
+		return "", fmt.Errorf("running %s: %w", generate.Cmd.Name, err)
+	}
+
+	if ch.Empty() {
+		return "", cli.Exit("changelog is empty, nothing to release", ExitCodeEmpty)
+	}
+
+	if ch.Held {
+		return "", cli.Exit("changelog is held, release needs human intervention", ExitCodeHeld)
+	}
+
+	log.Infof("Linking dependencies in %s", f.yaml)
+	linkCtx, err := config.CommandContext(cCtx, link.Cmd)
+	if err != nil {
+		return "", err
+	}
+	linkOpts := link.OptionsFromCli(linkCtx)
+	linkOpts.YAML = f.yaml
+	if dictionary := cCtx.String(dictionaryPathFlag); dictionary != "" {
+		linkOpts.Dictionary = dictionary
+	}
+	if dryRun {
+		// The link cache is disabled, rather than read and rewritten, so dry runs leave it untouched.
+		linkOpts.LinkCache = ""
+	}
+
+	if err = link.Run(linkOpts); err != nil {
+		return "", fmt.Errorf("running %s: %w", link.Cmd.Name, err)
+	}
+
+	nextCtx, err := config.CommandContext(cCtx, nextversion.Cmd)
+	if err != nil {
+		return "", err
+	}
+	nextOpts := nextversion.OptionsFromCli(nextCtx)
+	nextOpts.YAML = f.yaml
+	nextOpts.GitRoot = gitRoot
+	nextOpts.TagPrefix = genOpts.TagPrefix
+	nextOpts.VersionConstraint = genOpts.VersionConstraint
+
+	nextVersion, err := nextversion.Run(nextOpts)
+	if err != nil {
+		return "", fmt.Errorf("running %s: %w", nextversion.Cmd.Name, err)
+	}
+	next := nextOpts.OutputPrefix + version.Format(nextVersion)
+	log.Infof("Next version is %s", next)
+
+	log.Infof("Updating %s", f.markdown)
+	updateCtx, err := config.CommandContext(cCtx, update.Cmd)
+	if err != nil {
+		return "", err
+	}
+	updateOpts := update.OptionsFromCli(updateCtx)
+	updateOpts.YAML = f.yaml
+	updateOpts.Markdown = f.markdown
+	updateOpts.Version = next
+
+	if err = update.Run(updateOpts); err != nil {
+		return "", fmt.Errorf("running %s: %w", update.Cmd.Name, err)
+	}
+
+	log.Infof("Rendering %s", f.partialMarkdown)
+	renderCtx, err := config.CommandContext(cCtx, render.Cmd)
+	if err != nil {
+		return "", err
+	}
+	renderOpts := render.OptionsFromCli(renderCtx)
+	renderOpts.YAML = f.yaml
+	renderOpts.Markdown = f.partialMarkdown
+	renderOpts.Version = next
+
+	if err = render.Run(renderOpts); err != nil {
+		return "", fmt.Errorf("running %s: %w", render.Cmd.Name, err)
+	}
+
+	return next, nil
+}
+
+// validateMarkdown writes the errors found in the changelog at mdPath to w, returning an exit error if there are any.
+func validateMarkdown(w io.Writer, mdPath string) error {
+	mdFile, err := os.Open(mdPath)
+	if err != nil {
+		return fmt.Errorf("opening changelog file %q: %w", mdPath, err)
+	}
+	defer mdFile.Close()
+
+	validator, err := markdown.NewValidator(mdFile)
+	if err != nil {
+		return fmt.Errorf("creating validator: %w", err)
+	}
+
+	errs := validator.Validate()
+	for _, vErr := range errs {
+		_, _ = fmt.Fprintln(w, vErr)
+	}
+
+	if len(errs) > 0 {
+		return cli.Exit(fmt.Sprintf("%s is not valid", mdPath), ExitCodeInvalid)
+	}
+
+	return nil
+}
+
+// setOutputs emits the outputs of the release. Major and major-minor outputs keep the prefix of the version.
+func setOutputs(gh gha.Github, next, partialMarkdown string) error {
+	prefixEnd := strings.IndexFunc(next, unicode.IsDigit)
+	if prefixEnd < 0 {
+		prefixEnd = 0
+	}
+	prefix := next[:prefixEnd]
+
+	nextVersion, err := semver.NewVersion(next[prefixEnd:])
+	if err != nil {
+		return fmt.Errorf("parsing next version %q: %w", next, err)
+	}
+
+	// Versions are formatted keeping the zero-padded month of CalVer versions.
+	parts := strings.SplitN(version.Format(nextVersion), ".", 3)
+	gh.SetOutput(nextVersionOutput, next)
+	gh.SetOutput(majorOutput, prefix+parts[0])
+	gh.SetOutput(majorMinorOutput, prefix+parts[0]+"."+parts[1])
+	gh.SetOutput(partialMarkdownOutput, partialMarkdown)
+
+	return nil
+}
+
+// splitLines splits content in lines keeping line endings. Unlike difflib.SplitLines, it does not add an empty line
+// after the trailing newline.
+func splitLines(content []byte) []string {
+	if len(content) == 0 {
+		return nil
+	}
+
+	lines := difflib.SplitLines(string(content))
+	if strings.HasSuffix(string(content), "\n") {
+		lines = lines[:len(lines)-1]
+	}
+
+	return lines
+}
+
+func copyIfExists(src, dst string) error {
+	content, err := os.ReadFile(src)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("reading %q: %w", src, err)
+	}
+
+	//nolint:gosec // Temporary copies are only read by this process.
+	err = os.WriteFile(dst, content, 0o644)
+	if err != nil {
+		return fmt.Errorf("copying %q: %w", src, err)
+	}
+
+	return nil
+}
+
+// printDiff writes a unified diff between the original file and the one modified by the dry run, labeled with the
+// path of the original file. Files that do not exist are considered empty.
+func printDiff(w io.Writer, original, modified string) error {
+	before, err := os.ReadFile(original)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("reading %q: %w", original, err)
+	}
+
+	after, err := os.ReadFile(modified)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("reading %q: %w", modified, err)
+	}
+
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        splitLines(before),
+		B:        splitLines(after),
+		FromFile: original,
+		ToFile:   original,
+		Context:  diffContextLines,
+	})
+	if err != nil {
+		return fmt.Errorf("computing diff for %q: %w", original, err)
+	}
+
+	_, err = fmt.Fprint(w, diff)
+	if err != nil {
+		return fmt.Errorf("writing diff: %w", err)
+	}
+
+	return nil
+}
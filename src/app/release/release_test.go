@@ -0,0 +1,262 @@
+package release_test
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/newrelic/release-toolkit/src/app"
+	"github.com/newrelic/release-toolkit/src/app/common"
+	"github.com/newrelic/release-toolkit/src/app/release"
+	"github.com/urfave/cli/v2"
+)
+
+const changelogWithEntries = `# Changelog
+This is based on blah blah blah
+
+## Unreleased
+
+### Enhancements
+- New feature has been added
+
+## v1.2.3 - 2022-01-01
+
+### Bug fixes
+- Fixed a bug
+`
+
+const emptyChangelog = `# Changelog
+This is based on blah blah blah
+
+## Unreleased
+
+## v1.2.3 - 2022-01-01
+
+### Bug fixes
+- Fixed a bug
+`
+
+const heldChangelog = `# Changelog
+This is based on blah blah blah
+
+## Unreleased
+
+### Held
+Waiting for a dependency to be released.
+
+### Enhancements
+- New feature has been added
+
+## v1.2.3 - 2022-01-01
+
+### Bug fixes
+- Fixed a bug
+`
+
+// repoWithChangelog creates a git repository with a v1.2.3 tag and the supplied CHANGELOG.md.
+func repoWithChangelog(t *testing.T, changelog string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	if err := os.WriteFile(path.Join(dir, "CHANGELOG.md"), []byte(changelog), 0o600); err != nil {
+		t.Fatalf("Error writing changelog: %v", err)
+	}
+
+	for _, cmdline := range []string{
+		"git init",
+		"git config user.email test@user.tld",
+		"git config user.name Test",
+		"git config commit.gpgsign false",
+		"git add CHANGELOG.md",
+		"git commit -m test",
+		"git tag v1.2.3",
+	} {
+		cmdparts := strings.Fields(cmdline)
+		//nolint:gosec // This is a test, we trust hardcoded input.
+		cmd := exec.Command(cmdparts[0], cmdparts[1:]...)
+		cmd.Dir = dir
+
+		out := strings.Builder{}
+		cmd.Stdout = &out
+		cmd.Stderr = &out
+		if err := cmd.Run(); err != nil {
+			t.Errorf("%s output:\n%s", cmdline, out.String())
+			t.Fatalf("Error bootstrapping test git repo: %v", err)
+		}
+	}
+
+	return dir
+}
+
+// runRelease runs the release command in dir, returning what it printed and the error.
+func runRelease(t *testing.T, dir string, args string) (string, error) {
+	t.Helper()
+
+	app := app.App()
+	buf := &strings.Builder{}
+	app.Writer = buf
+	// Prevent cli from exiting the test binary on cli.Exit errors.
+	app.ExitErrHandler = func(*cli.Context, error) {}
+
+	// Bot sources are disabled through the config file, as release does not expose their flags.
+	configPath := path.Join(dir, ".release-toolkit.yaml")
+	if err := os.WriteFile(configPath, []byte("renovate: false\ndependabot: false\n"), 0o600); err != nil {
+		t.Fatalf("Error writing config: %v", err)
+	}
+
+	cmdline := fmt.Sprintf(
+		"rt -yaml %s release -git-root %s -markdown %s -partial-markdown %s %s",
+		path.Join(dir, "changelog.yaml"), dir, path.Join(dir, "CHANGELOG.md"), path.Join(dir, "CHANGELOG.partial.md"), args,
+	)
+	err := app.Run(strings.Fields(cmdline))
+	return buf.String(), err
+}
+
+//nolint:paralleltest // urfave/cli cannot be tested concurrently.
+func TestRelease(t *testing.T) {
+	outputPath := path.Join(t.TempDir(), "github-output")
+	if err := os.WriteFile(outputPath, nil, 0o600); err != nil {
+		t.Fatalf("Error creating output file: %v", err)
+	}
+	t.Setenv(common.GHAEnv, "true")
+	t.Setenv(common.GHAOutputEnv, outputPath)
+
+	dir := repoWithChangelog(t, changelogWithEntries)
+
+	stdout, err := runRelease(t, dir, "")
+	if err != nil {
+		t.Fatalf("Error running release: %v", err)
+	}
+
+	if expected := "v1.3.0\n"; stdout != expected {
+		t.Fatalf("Expected %q, got %q", expected, stdout)
+	}
+
+	partial, err := os.ReadFile(path.Join(dir, "CHANGELOG.partial.md"))
+	if err != nil {
+		t.Fatalf("Error reading partial changelog: %v", err)
+	}
+	if !strings.HasPrefix(string(partial), "## v1.3.0 - ") || !strings.Contains(string(partial), "- New feature has been added") {
+		t.Fatalf("Unexpected partial changelog:\n%s", partial)
+	}
+
+	md, err := os.ReadFile(path.Join(dir, "CHANGELOG.md"))
+	if err != nil {
+		t.Fatalf("Error reading changelog: %v", err)
+	}
+	if !strings.Contains(string(md), "## Unreleased\n\n## v1.3.0 - ") {
+		t.Fatalf("Changelog was not updated:\n%s", md)
+	}
+
+	output, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("Error reading outputs: %v", err)
+	}
+	expectedOutput := fmt.Sprintf(
+		"next-version=v1.3.0\nnext-version-major=v1\nnext-version-major-minor=v1.3\npartial-markdown=%s\n",
+		path.Join(dir, "CHANGELOG.partial.md"),
+	)
+	if actual := string(output); actual != expectedOutput {
+		t.Fatalf("Expected outputs %q, got %q", expectedOutput, actual)
+	}
+}
+
+//nolint:paralleltest // urfave/cli cannot be tested concurrently.
+func TestRelease_Dry_Run(t *testing.T) {
+	dir := repoWithChangelog(t, changelogWithEntries)
+
+	// An expired entry, which link-dependencies would drop when saving the cache.
+	const linkCache = `{"https://example.com": {"ok": true, "checked": "2000-01-01T00:00:00Z"}}`
+	linkCachePath := path.Join(dir, "links.json")
+	if err := os.WriteFile(linkCachePath, []byte(linkCache), 0o600); err != nil {
+		t.Fatalf("Error writing link cache: %v", err)
+	}
+	t.Setenv(common.EnvFor("link-cache")[0], linkCachePath)
+
+	stdout, err := runRelease(t, dir, "-dry-run")
+	if err != nil {
+		t.Fatalf("Error running release: %v", err)
+	}
+
+	for _, expected := range []string{
+		fmt.Sprintf("--- %s\n+++ %s\n", path.Join(dir, "changelog.yaml"), path.Join(dir, "changelog.yaml")),
+		"+    - type: enhancement\n+      message: New feature has been added\n",
+		fmt.Sprintf("--- %s\n+++ %s\n", path.Join(dir, "CHANGELOG.md"), path.Join(dir, "CHANGELOG.md")),
+		"-### Enhancements\n+## v1.3.0 - ",
+		"+++ " + path.Join(dir, "CHANGELOG.partial.md") + "\n@@ -0,0 +1,4 @@\n+## v1.3.0 - ",
+	} {
+		if !strings.Contains(stdout, expected) {
+			t.Fatalf("Expected output to contain:\n%s\ngot:\n%s", expected, stdout)
+		}
+	}
+
+	if !strings.HasSuffix(stdout, "\nv1.3.0\n") {
+		t.Fatalf("Expected output to end with the next version, got:\n%s", stdout)
+	}
+
+	md, err := os.ReadFile(path.Join(dir, "CHANGELOG.md"))
+	if err != nil {
+		t.Fatalf("Error reading changelog: %v", err)
+	}
+	if string(md) != changelogWithEntries {
+		t.Fatalf("Changelog was modified in dry run:\n%s", md)
+	}
+
+	for _, file := range []string{"changelog.yaml", "CHANGELOG.partial.md"} {
+		if _, err = os.Stat(path.Join(dir, file)); !errors.Is(err, os.ErrNotExist) {
+			t.Fatalf("Expected %s not to be created in dry run, got %v", file, err)
+		}
+	}
+
+	cache, err := os.ReadFile(linkCachePath)
+	if err != nil {
+		t.Fatalf("Error reading link cache: %v", err)
+	}
+	if string(cache) != linkCache {
+		t.Fatalf("Link cache was modified in dry run:\n%s", cache)
+	}
+}
+
+//nolint:paralleltest // urfave/cli cannot be tested concurrently.
+func TestRelease_Exit_Codes(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		changelog string
+		exitCode  int
+	}{
+		{
+			name:      "Invalid",
+			changelog: "## Unreleased\n\n### Enhancements\nNot a list\n",
+			exitCode:  release.ExitCodeInvalid,
+		},
+		{
+			name:      "Empty",
+			changelog: emptyChangelog,
+			exitCode:  release.ExitCodeEmpty,
+		},
+		{
+			name:      "Held",
+			changelog: heldChangelog,
+			exitCode:  release.ExitCodeHeld,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			dir := repoWithChangelog(t, tc.changelog)
+
+			_, err := runRelease(t, dir, "")
+
+			var exitErr cli.ExitCoder
+			if !errors.As(err, &exitErr) {
+				t.Fatalf("Expected an exit error, got %v", err)
+			}
+			if exitErr.ExitCode() != tc.exitCode {
+				t.Fatalf("Expected exit code %d, got %d (%v)", tc.exitCode, exitErr.ExitCode(), err)
+			}
+		})
+	}
+}
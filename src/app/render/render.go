@@ -1,24 +1,33 @@
 package render
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"time"
 
 	"github.com/Masterminds/semver"
 	"github.com/newrelic/release-toolkit/src/app/common"
+	"github.com/newrelic/release-toolkit/src/app/config"
 	"github.com/newrelic/release-toolkit/src/changelog"
 	"github.com/newrelic/release-toolkit/src/changelog/renderer"
+	"github.com/newrelic/release-toolkit/src/monorepo"
 	"github.com/urfave/cli/v2"
 	"gopkg.in/yaml.v3"
 )
 
 const (
-	markdownPathFlag = "markdown"
-	versionFlag      = "version"
-	dateFlag         = "date"
+	markdownPathFlag  = "markdown"
+	versionFlag       = "version"
+	versionPrefixFlag = "version-prefix"
+	dateFlag          = "date"
+	templateFlag      = "template"
+	formatFlag        = "format"
+	releaseNotesFlag  = "release-notes-for"
 )
 
+var ErrTemplateFormat = errors.New("templates can only be used with the markdown format")
+
 // Cmd is the cli.Command object for the render command.
 //
 //nolint:gochecknoglobals // We could overengineer this to avoid the global command but I don't think it's worth it.
@@ -39,6 +48,12 @@ var Cmd = &cli.Command{
 				"If omitted, no version header will be generated",
 			Value: "",
 		},
+		&cli.StringFlag{
+			Name:    versionPrefixFlag,
+			EnvVars: common.EnvFor(versionPrefixFlag),
+			Usage:   "Prefix prepended to the version in the changelog section header.",
+			Value:   "v",
+		},
 		&cli.TimestampFlag{
 			Name:    dateFlag,
 			EnvVars: common.EnvFor(dateFlag),
@@ -47,18 +62,120 @@ var Cmd = &cli.Command{
 			Value:  cli.NewTimestamp(time.Now()),
 			Layout: "2006-01-02",
 		},
+		&cli.StringFlag{
+			Name:    templateFlag,
+			EnvVars: common.EnvFor(templateFlag),
+			Usage: "Path to a Go text/template file used to render the changelog section instead of the default layout. " +
+				"See README_CLI.md for the data model and available functions.",
+			Value: "",
+		},
+		&cli.StringFlag{
+			Name:    formatFlag,
+			EnvVars: common.EnvFor(formatFlag),
+			Usage:   "Output format of the rendered changelog: markdown, html, text, json or slack (Block Kit JSON).",
+			Value:   renderer.MarkdownFormat,
+		},
+		&cli.StringSliceFlag{
+			Name:    releaseNotesFlag,
+			EnvVars: common.EnvFor(releaseNotesFlag),
+			Usage: "Names of the dependencies whose upstream release notes, embedded by link-dependencies --release-notes, " +
+				"are rendered in a collapsible block. The release notes of all dependencies are rendered if empty.",
+		},
 	},
+	Before: config.Apply,
 	Action: Render,
 }
 
-// Render is a command function which loads a changelog.yaml file from this, and prints to stdout whether it has the
-// Held flag set to true.
+// Options holds the settings of render-changelog, which are taken from its flags by OptionsFromCli.
+type Options struct {
+	// YAML is the path to the changelog.yaml that is rendered.
+	YAML string
+	// Markdown is the path to the file the changelog is rendered to.
+	Markdown string
+	// Version, if not empty, is stamped in the header of the section.
+	Version string
+	// VersionPrefix is prepended to Version in the header of the section.
+	VersionPrefix string
+	// Date is stamped in the header of the section. The current time is used if it is zero.
+	Date time.Time
+	// Template, if not empty, is the path to the template the section is rendered with.
+	Template        string
+	Format          string
+	ReleaseNotesFor []string
+}
+
+// OptionsFromCli returns the Options set by the flags of render-changelog in cCtx.
+func OptionsFromCli(cCtx *cli.Context) Options {
+	opts := Options{
+		YAML:          cCtx.String(common.YAMLFlag),
+		Markdown:      cCtx.String(markdownPathFlag),
+		Version:       cCtx.String(versionFlag),
+		VersionPrefix: cCtx.String(versionPrefixFlag),
+		Template:      cCtx.String(templateFlag),
+		Format:        cCtx.String(formatFlag),
+	}
+
+	if t := cCtx.Timestamp(dateFlag); t != nil {
+		opts.Date = *t
+	}
+
+	for _, name := range cCtx.StringSlice(releaseNotesFlag) {
+		if name != "" {
+			opts.ReleaseNotesFor = append(opts.ReleaseNotesFor, name)
+		}
+	}
+
+	return opts
+}
+
+// Render is a command function which loads a changelog.yaml file and renders it as a markdown changelog section.
+// If a monorepo manifest is supplied, the changelog of each component marked for release in the summary is rendered
+// instead, using the version recorded for it.
 func Render(cCtx *cli.Context) error {
-	chPath := cCtx.String(common.YAMLFlag)
+	opts := OptionsFromCli(cCtx)
+
+	if manifestPath := cCtx.String(common.MonorepoFlag); manifestPath != "" {
+		return renderMonorepo(opts, manifestPath)
+	}
+
+	return Run(opts)
+}
+
+func renderMonorepo(opts Options, manifestPath string) error {
+	manifest, err := monorepo.Load(manifestPath)
+	if err != nil {
+		return fmt.Errorf("loading monorepo manifest: %w", err)
+	}
+
+	summary, err := monorepo.ReadSummary(manifest.Summary)
+	if err != nil {
+		return fmt.Errorf("loading monorepo summary: %w", err)
+	}
+
+	for _, release := range manifest.Releases(summary) {
+		componentOpts := opts
+		componentOpts.YAML = release.YAML
+		componentOpts.Markdown = release.PartialMarkdown
+		componentOpts.Version = release.Version
+
+		err = Run(componentOpts)
+		if err != nil {
+			return fmt.Errorf("rendering component %q: %w", release.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// Run renders the changelog.yaml of a single repository as configured by opts.
+func Run(opts Options) error {
+	chPath, mdPath := opts.YAML, opts.Markdown
+
 	chFile, err := os.Open(chPath)
 	if err != nil {
 		return fmt.Errorf("opening changelog yaml file %q: %w", chPath, err)
 	}
+	defer chFile.Close()
 
 	ch := &changelog.Changelog{}
 	err = yaml.NewDecoder(chFile).Decode(ch)
@@ -66,25 +183,44 @@ func Render(cCtx *cli.Context) error {
 		return fmt.Errorf("loading changelog from file: %w", err)
 	}
 
-	mdPath := cCtx.String(markdownPathFlag)
 	mdFile, err := os.Create(mdPath)
 	if err != nil {
 		return fmt.Errorf("creating destination file at %q: %w", mdPath, err)
 	}
+	defer mdFile.Close()
 
 	rnd := renderer.New(ch)
+	rnd.ReleaseNotesFor = opts.ReleaseNotesFor
+	rnd.VersionPrefix = opts.VersionPrefix
 
-	if t := cCtx.Timestamp(dateFlag); t != nil {
-		tv := *t
+	format := opts.Format
+	rnd.Formatter, err = renderer.FormatterFor(format)
+	if err != nil {
+		return fmt.Errorf("selecting output format: %w", err)
+	}
+
+	if tplPath := opts.Template; tplPath != "" {
+		if format != renderer.MarkdownFormat {
+			return fmt.Errorf("%w, got %q", ErrTemplateFormat, format)
+		}
+
+		tpl, tErr := os.ReadFile(tplPath)
+		if tErr != nil {
+			return fmt.Errorf("reading template %q: %w", tplPath, tErr)
+		}
+		rnd.Formatter = renderer.Markdown{Template: string(tpl)}
+	}
+
+	if !opts.Date.IsZero() {
 		rnd.ReleasedOn = func() time.Time {
-			return tv
+			return opts.Date
 		}
 	}
 
-	if versionStr := cCtx.String(versionFlag); versionStr != "" {
-		version, vErr := semver.NewVersion(versionStr)
+	if opts.Version != "" {
+		version, vErr := semver.NewVersion(opts.Version)
 		if vErr != nil {
-			return fmt.Errorf("parsing version %q: %w", versionStr, vErr)
+			return fmt.Errorf("parsing version %q: %w", opts.Version, vErr)
 		}
 		rnd.Next = version
 	}
@@ -18,6 +18,7 @@ func TestRender(t *testing.T) {
 		name     string
 		yaml     string
 		args     string
+		template string
 		expected string
 	}{
 		{
@@ -170,6 +171,113 @@ This is a release note
 - Support has been removed
 			`) + "\n",
 		},
+		{
+			name: "Changelog_With_Version_Prefix",
+			args: "-version 1.2.3 -version-prefix release- -date 1993-09-21",
+			yaml: strings.TrimSpace(`
+changes:
+- type: breaking
+  message: Support has been removed
+			`),
+			expected: strings.TrimSpace(`
+## release-1.2.3 - 1993-09-21
+
+### ⚠️️ Breaking changes ⚠️
+- Support has been removed
+			`) + "\n",
+		},
+		{
+			name: "Changelog_With_Template",
+			args: "-version v1.2.3 -date 1993-09-21",
+			yaml: strings.TrimSpace(`
+changes:
+- type: enhancement
+  message: New feature has been added
+- type: bugfix
+  message: Fixed a bug
+			`),
+			template: strings.TrimSpace(`
+## {{ .Version }} ({{ date "Jan 2, 2006" .ReleasedOn }})
+{{ with .Sections.enhancement }}
+### Added
+{{- range . }}
+- {{ .Message }}
+{{- end }}
+{{ end }}
+{{- with .Sections.bugfix }}
+### Fixed
+{{- range . }}
+- {{ .Message }}
+{{- end }}
+{{ end }}
+			`),
+			expected: strings.TrimSpace(`
+## v1.2.3 (Sep 21, 1993)
+
+### Added
+- New feature has been added
+
+### Fixed
+- Fixed a bug
+			`) + "\n",
+		},
+		{
+			name: "Changelog_With_Text_Format",
+			args: "-version v1.2.3 -date 1993-09-21 -format text",
+			yaml: strings.TrimSpace(`
+changes:
+- type: enhancement
+  message: New feature has been added
+dependencies:
+- name: foobar
+  from: 0.0.1
+  to: 0.1.0
+  changelog: https://github.com/foo/bar/releases/tag/0.1.0
+			`),
+			expected: strings.TrimSpace(`
+v1.2.3 - 1993-09-21
+
+Enhancements:
+- New feature has been added
+
+Dependencies:
+- Upgraded foobar from 0.0.1 to 0.1.0 - Changelog 🔗: https://github.com/foo/bar/releases/tag/0.1.0
+			`) + "\n",
+		},
+		{
+			name: "Changelog_With_Release_Notes_For",
+			args: "-release-notes-for foobar",
+			yaml: strings.TrimSpace(`
+dependencies:
+- name: foobar
+  from: 0.0.1
+  to: 0.1.0
+  release-notes:
+  - version: 0.1.0
+    notes: Foobar notes
+- name: bazbar
+  from: 0.0.1
+  to: 0.1.0
+  release-notes:
+  - version: 0.1.0
+    notes: Bazbar notes
+			`),
+			expected: strings.TrimSpace(`
+### ⛓️ Dependencies
+- Upgraded foobar from 0.0.1 to 0.1.0
+
+    <details>
+    <summary>Release notes</summary>
+
+    #### 0.1.0
+
+    Foobar notes
+
+    </details>
+
+- Upgraded bazbar from 0.0.1 to 0.1.0
+			`) + "\n",
+		},
 	} {
 		tc := tc
 		//nolint:paralleltest // urfave/cli cannot be tested concurrently.
@@ -188,7 +296,16 @@ This is a release note
 
 			mdPath := path.Join(tDir, "changelog.md")
 
-			err = app.Run(strings.Fields(fmt.Sprintf("rt -yaml %s render-changelog -markdown %s %s", yamlPath, mdPath, tc.args)))
+			args := tc.args
+			if tc.template != "" {
+				tplPath := path.Join(tDir, "changelog.tpl")
+				if err = os.WriteFile(tplPath, []byte(tc.template), 0o600); err != nil {
+					t.Fatalf("Error creating template for test: %v", err)
+				}
+				args += " -template " + tplPath
+			}
+
+			err = app.Run(strings.Fields(fmt.Sprintf("rt -yaml %s render-changelog -markdown %s %s", yamlPath, mdPath, args)))
 			if err != nil {
 				t.Fatalf("Error running app: %v", err)
 			}
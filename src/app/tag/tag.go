@@ -0,0 +1,289 @@
+// Package tag implements the tag command, which commits the files updated by a release and tags the result.
+package tag
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/Masterminds/semver"
+	"github.com/newrelic/release-toolkit/src/app/common"
+	"github.com/newrelic/release-toolkit/src/app/config"
+	"github.com/newrelic/release-toolkit/src/app/gha"
+	"github.com/newrelic/release-toolkit/src/changelog"
+	"github.com/newrelic/release-toolkit/src/changelog/renderer"
+	"github.com/newrelic/release-toolkit/src/git"
+	log "github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v2"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	markdownPathFlag  = "markdown"
+	filesFlag         = "files"
+	versionFlag       = "version"
+	tagPrefixFlag     = "tag-prefix"
+	gitRootFlag       = "git-root"
+	messageFlag       = "message"
+	signFlag          = "sign"
+	signingKeyFlag    = "signing-key"
+	signingFormatFlag = "signing-format"
+)
+
+const (
+	tagOutput    = "tag"
+	commitOutput = "commit"
+)
+
+// defaultMessage is the default template of the release commit message.
+const defaultMessage = "Release {{.Tag}}"
+
+var (
+	// ErrNoVersion is returned if tag is run without a version.
+	ErrNoVersion = errors.New("version to tag is required")
+	// ErrDirtyWorktree is returned if files other than the ones to commit have changed.
+	ErrDirtyWorktree = errors.New("worktree has uncommitted changes to files other than the ones to commit")
+	// ErrMonorepoUnsupported is returned if the tag command is run with a monorepo manifest.
+	ErrMonorepoUnsupported = errors.New("tag does not support monorepo mode yet")
+)
+
+// Cmd is the cli.Command object for the tag command.
+//
+//nolint:gochecknoglobals // We could overengineer this to avoid the global command but I don't think it's worth it.
+var Cmd = &cli.Command{
+	Name:  "tag",
+	Usage: "Commits the updated CHANGELOG.md and version files, and creates an annotated tag with the release notes.",
+	UsageText: `Tag commits CHANGELOG.md and the files passed with --files, then creates an annotated tag named after the
+tag prefix and the version, whose message is changelog.yaml rendered as markdown. The tag is created on HEAD if none of
+the files changed. Tag refuses to run if other tracked files have uncommitted changes. Untracked files are ignored.
+Nothing is pushed.`,
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:    markdownPathFlag,
+			EnvVars: common.EnvFor(markdownPathFlag),
+			Usage:   "Path to the CHANGELOG.md file to commit.",
+			Value:   "CHANGELOG.md",
+		},
+		&cli.StringSliceFlag{
+			Name:    filesFlag,
+			EnvVars: common.EnvFor(filesFlag),
+			Usage:   "Comma-separated list of other files updated by the release, such as version files, to commit.",
+		},
+		&cli.StringFlag{
+			Name:    versionFlag,
+			EnvVars: common.EnvFor(versionFlag),
+			Usage:   "Version being released, as printed by next-version.",
+			Value:   "",
+		},
+		&cli.StringFlag{
+			Name:    tagPrefixFlag,
+			EnvVars: common.EnvFor(tagPrefixFlag),
+			Usage:   "Prefix prepended to the version to name the tag.",
+			Value:   "",
+		},
+		&cli.StringFlag{
+			Name:    gitRootFlag,
+			EnvVars: common.EnvFor(gitRootFlag),
+			Usage:   "Path to the git repo to commit and tag in.",
+			Value:   "./",
+		},
+		&cli.StringFlag{
+			Name:    messageFlag,
+			EnvVars: common.EnvFor(messageFlag),
+			Usage: "Go text/template for the commit message, executed with .Tag, .Version and .Prefix " +
+				"(e.g. \"chore(release): {{.Version}}\").",
+			Value: defaultMessage,
+		},
+		&cli.BoolFlag{
+			Name:    signFlag,
+			EnvVars: common.EnvFor(signFlag),
+			Usage:   "Sign the commit and the tag with the key configured in git (user.signingKey), or --signing-key.",
+			Value:   false,
+		},
+		&cli.StringFlag{
+			Name:    signingKeyFlag,
+			EnvVars: common.EnvFor(signingKeyFlag),
+			Usage:   "Key to sign with: a GPG key ID, or the path to an SSH key if the signing format is ssh. Implies --sign.",
+			Value:   "",
+		},
+		&cli.StringFlag{
+			Name:    signingFormatFlag,
+			EnvVars: common.EnvFor(signingFormatFlag),
+			Usage:   "Signature format, gpg or ssh, overriding the gpg.format setting of git. Implies --sign.",
+			Value:   "",
+		},
+	},
+	Before: config.Apply,
+	Action: Tag,
+}
+
+// messageData is the object the commit message template is executed with.
+type messageData struct {
+	// Tag is the name of the tag, i.e. Prefix followed by Version.
+	Tag     string
+	Version string
+	Prefix  string
+}
+
+// Tag is a command function that commits the files updated by a release and tags the resulting commit.
+func Tag(cCtx *cli.Context) error {
+	if cCtx.String(common.MonorepoFlag) != "" {
+		return ErrMonorepoUnsupported
+	}
+
+	version := cCtx.String(versionFlag)
+	if version == "" {
+		return ErrNoVersion
+	}
+
+	data := messageData{
+		Tag:     cCtx.String(tagPrefixFlag) + version,
+		Version: version,
+		Prefix:  cCtx.String(tagPrefixFlag),
+	}
+
+	message, err := commitMessage(cCtx.String(messageFlag), data)
+	if err != nil {
+		return err
+	}
+
+	notes, err := releaseNotes(cCtx.String(common.YAMLFlag), version)
+	if err != nil {
+		return err
+	}
+
+	var writeOpts []git.WriteOptionFunc
+	if cCtx.Bool(signFlag) || cCtx.String(signingKeyFlag) != "" || cCtx.String(signingFormatFlag) != "" {
+		writeOpts = append(writeOpts, git.Signed(cCtx.String(signingFormatFlag), cCtx.String(signingKeyFlag)))
+	}
+
+	worktree := git.NewWorktree(cCtx.String(gitRootFlag))
+
+	files, err := filesToCommit(worktree, append([]string{cCtx.String(markdownPathFlag)}, cCtx.StringSlice(filesFlag)...))
+	if err != nil {
+		return err
+	}
+
+	// The tag is checked before committing, so no commit is left behind if it cannot be created.
+	if err = worktree.CheckTag(data.Tag); err != nil {
+		return fmt.Errorf("tagging release: %w", err)
+	}
+
+	hash, err := worktree.Commit(message, files, writeOpts...)
+	if errors.Is(err, git.ErrNothingToCommit) {
+		log.Infof("None of %v changed, tagging HEAD", files)
+		if hash, err = worktree.Head(); err != nil {
+			return fmt.Errorf("tagging release: %w", err)
+		}
+	} else if err != nil {
+		return fmt.Errorf("committing release: %w", err)
+	} else {
+		log.Infof("Committed %v as %s", files, hash)
+	}
+
+	if err = worktree.Tag(data.Tag, notes, writeOpts...); err != nil {
+		return fmt.Errorf("tagging release: %w", err)
+	}
+
+	_, _ = fmt.Fprintf(cCtx.App.Writer, "%s\n", data.Tag)
+
+	gh := gha.NewFromCli(cCtx)
+	gh.SetOutput(tagOutput, data.Tag)
+	gh.SetOutput(commitOutput, hash)
+
+	return nil
+}
+
+// filesToCommit returns the absolute paths of files, which are relative to the current directory, after checking that
+// no other tracked files in the worktree have changed.
+func filesToCommit(worktree *git.Worktree, files []string) ([]string, error) {
+	expected := map[string]bool{}
+	absFiles := make([]string, 0, len(files))
+	for _, file := range files {
+		if file == "" {
+			continue
+		}
+
+		abs, err := filepath.Abs(file)
+		if err != nil {
+			return nil, fmt.Errorf("getting absolute path of %q: %w", file, err)
+		}
+		absFiles = append(absFiles, abs)
+
+		rel, err := worktree.Rel(abs)
+		if err != nil {
+			return nil, err
+		}
+		expected[rel] = true
+	}
+
+	changes, err := worktree.Changes()
+	if err != nil {
+		return nil, err
+	}
+
+	var unexpected []string
+	for _, change := range changes {
+		if !expected[change] {
+			unexpected = append(unexpected, change)
+		}
+	}
+
+	if len(unexpected) > 0 {
+		sort.Strings(unexpected)
+		return nil, fmt.Errorf("%w: %s", ErrDirtyWorktree, strings.Join(unexpected, ", "))
+	}
+
+	return absFiles, nil
+}
+
+// commitMessage executes the commit message template with data.
+func commitMessage(tpl string, data messageData) (string, error) {
+	t, err := template.New("message").Parse(tpl)
+	if err != nil {
+		return "", fmt.Errorf("parsing commit message template: %w", err)
+	}
+
+	message := &strings.Builder{}
+	if err = t.Execute(message, data); err != nil {
+		return "", fmt.Errorf("rendering commit message: %w", err)
+	}
+
+	return message.String(), nil
+}
+
+// releaseNotes renders the changelog in chPath as markdown, stamped with version, to be used as the tag message.
+func releaseNotes(chPath, version string) (string, error) {
+	chFile, err := os.Open(chPath)
+	if err != nil {
+		return "", fmt.Errorf("opening changelog yaml file %q: %w", chPath, err)
+	}
+	defer chFile.Close()
+
+	ch := &changelog.Changelog{}
+	if err = yaml.NewDecoder(chFile).Decode(ch); err != nil {
+		return "", fmt.Errorf("loading changelog from file: %w", err)
+	}
+
+	rnd := renderer.New(ch)
+	rnd.ReleasedOn = time.Now
+
+	// Versions that are not semver, such as the ones with a tag prefix, are rendered without a version header.
+	if next, vErr := semver.NewVersion(version); vErr == nil {
+		rnd.Next = next
+	} else {
+		log.Debugf("Rendering release notes without version header, %q is not semver: %v", version, vErr)
+	}
+
+	notes := &strings.Builder{}
+	if err = rnd.Render(notes); err != nil {
+		return "", fmt.Errorf("rendering changelog: %w", err)
+	}
+
+	return strings.TrimSpace(notes.String()) + "\n", nil
+}
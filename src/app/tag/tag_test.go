@@ -0,0 +1,216 @@
+package tag_test
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/newrelic/release-toolkit/src/app"
+	"github.com/newrelic/release-toolkit/src/app/common"
+	"github.com/newrelic/release-toolkit/src/app/tag"
+	"github.com/newrelic/release-toolkit/src/git"
+)
+
+const changelogYaml = `
+notes: |-
+  This release adds the tag command.
+changes:
+  - type: enhancement
+    message: Add tag command
+`
+
+// repoWithReleaseFiles creates a git repository with CHANGELOG.md, VERSION and other committed, and a changelog.yaml
+// outside of it. The paths to both are returned.
+func repoWithReleaseFiles(t *testing.T) (string, string) {
+	t.Helper()
+
+	dir := t.TempDir()
+	gitCmd(t, dir, "init")
+	gitCmd(t, dir, "config", "user.email", "test@user.tld")
+	gitCmd(t, dir, "config", "user.name", "Test")
+	gitCmd(t, dir, "config", "commit.gpgsign", "false")
+	gitCmd(t, dir, "config", "tag.gpgsign", "false")
+
+	for _, file := range []string{"CHANGELOG.md", "VERSION", "other"} {
+		writeFile(t, path.Join(dir, file), "initial\n")
+	}
+	gitCmd(t, dir, "add", ".")
+	gitCmd(t, dir, "commit", "-m", "initial")
+
+	yamlPath := path.Join(t.TempDir(), "changelog.yaml")
+	writeFile(t, yamlPath, changelogYaml)
+
+	return dir, yamlPath
+}
+
+func writeFile(t *testing.T, name, contents string) {
+	t.Helper()
+
+	if err := os.WriteFile(name, []byte(contents), 0o600); err != nil {
+		t.Fatalf("Error writing %s: %v", name, err)
+	}
+}
+
+func gitCmd(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("Error running git %v: %v\n%s", args, err, out)
+	}
+
+	return strings.TrimSpace(string(out))
+}
+
+//nolint:paralleltest
+func TestTag(t *testing.T) {
+	t.Setenv(common.GHAOutputEnv, "")
+
+	dir, yamlPath := repoWithReleaseFiles(t)
+	writeFile(t, path.Join(dir, "CHANGELOG.md"), "updated\n")
+	writeFile(t, path.Join(dir, "VERSION"), "1.3.0\n")
+	writeFile(t, path.Join(dir, "untracked"), "untracked\n")
+	head := gitCmd(t, dir, "rev-parse", "HEAD")
+
+	rt := app.App()
+	buf := &strings.Builder{}
+	rt.Writer = buf
+
+	err := rt.Run(strings.Fields(fmt.Sprintf(
+		"rt -yaml %s tag -git-root %s -markdown %s -files %s -tag-prefix v -version 1.3.0",
+		yamlPath, dir, path.Join(dir, "CHANGELOG.md"), path.Join(dir, "VERSION"),
+	)))
+	if err != nil {
+		t.Fatalf("Error running app: %v", err)
+	}
+
+	if actual := buf.String(); actual != "v1.3.0\n" {
+		t.Fatalf("Expected tag name to be printed, got %q", actual)
+	}
+
+	if parent := gitCmd(t, dir, "rev-parse", "HEAD^"); parent != head {
+		t.Fatalf("Expected a new commit on top of %s, parent is %s", head, parent)
+	}
+	if message := gitCmd(t, dir, "log", "-1", "--format=%B"); message != "Release v1.3.0" {
+		t.Fatalf("Unexpected commit message %q", message)
+	}
+	if files := gitCmd(t, dir, "show", "--format=", "--name-only", "HEAD"); files != "CHANGELOG.md\nVERSION" {
+		t.Fatalf("Unexpected committed files %q", files)
+	}
+
+	if tagged := gitCmd(t, dir, "rev-parse", "v1.3.0^{commit}"); tagged != gitCmd(t, dir, "rev-parse", "HEAD") {
+		t.Fatalf("Expected tag to point to the release commit, points to %s", tagged)
+	}
+	if objType := gitCmd(t, dir, "cat-file", "-t", "v1.3.0"); objType != "tag" {
+		t.Fatalf("Expected an annotated tag, got a %s", objType)
+	}
+
+	notes := gitCmd(t, dir, "tag", "-l", "--format=%(contents)", "v1.3.0")
+	for _, expected := range []string{"## v1.3.0 - ", "This release adds the tag command.", "### 🚀 Enhancements", "- Add tag command"} {
+		if !strings.Contains(notes, expected) {
+			t.Fatalf("Expected tag message to contain %q, got:\n%s", expected, notes)
+		}
+	}
+}
+
+//nolint:paralleltest
+func TestTag_Nothing_Changed(t *testing.T) {
+	t.Setenv(common.GHAOutputEnv, "")
+
+	dir, yamlPath := repoWithReleaseFiles(t)
+	head := gitCmd(t, dir, "rev-parse", "HEAD")
+
+	rt := app.App()
+	buf := &strings.Builder{}
+	rt.Writer = buf
+
+	err := rt.Run(strings.Fields(fmt.Sprintf(
+		"rt -yaml %s -gha tag -git-root %s -markdown %s -version v1.3.0 -message chore(release):{{.Version}}",
+		yamlPath, dir, path.Join(dir, "CHANGELOG.md"),
+	)))
+	if err != nil {
+		t.Fatalf("Error running app: %v", err)
+	}
+
+	if expected := fmt.Sprintf("::set-output name=commit::%s\n", head); !strings.Contains(buf.String(), expected) {
+		t.Fatalf("Expected HEAD to be output as the commit, got:\n%s", buf.String())
+	}
+
+	if current := gitCmd(t, dir, "rev-parse", "HEAD"); current != head {
+		t.Fatalf("Expected no commit to be created, HEAD moved from %s to %s", head, current)
+	}
+	if tagged := gitCmd(t, dir, "rev-parse", "v1.3.0^{commit}"); tagged != head {
+		t.Fatalf("Expected tag to point to HEAD, points to %s", tagged)
+	}
+}
+
+//nolint:paralleltest
+func TestTag_Errors(t *testing.T) {
+	t.Setenv(common.GHAOutputEnv, "")
+
+	for _, tc := range []struct {
+		name     string
+		args     string
+		modified []string
+		tags     []string
+		expected error
+	}{
+		{
+			name:     "Other_Files_Changed",
+			args:     "-version v1.3.0",
+			modified: []string{"CHANGELOG.md", "other"},
+			expected: tag.ErrDirtyWorktree,
+		},
+		{
+			name:     "No_Version",
+			modified: []string{"CHANGELOG.md"},
+			expected: tag.ErrNoVersion,
+		},
+		{
+			name:     "Tag_Exists",
+			args:     "-version v1.3.0",
+			modified: []string{"CHANGELOG.md"},
+			tags:     []string{"v1.3.0"},
+			expected: git.ErrTagExists,
+		},
+		{
+			name:     "Invalid_Tag",
+			args:     "-version v1.3.0 -tag-prefix release..",
+			modified: []string{"CHANGELOG.md"},
+			expected: git.ErrInvalidTagName,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			dir, yamlPath := repoWithReleaseFiles(t)
+			for _, name := range tc.tags {
+				gitCmd(t, dir, "tag", name)
+			}
+			for _, file := range tc.modified {
+				writeFile(t, path.Join(dir, file), "updated\n")
+			}
+			head := gitCmd(t, dir, "rev-parse", "HEAD")
+
+			err := app.App().Run(strings.Fields(fmt.Sprintf(
+				"rt -yaml %s tag -git-root %s -markdown %s %s", yamlPath, dir, path.Join(dir, "CHANGELOG.md"), tc.args,
+			)))
+			if !errors.Is(err, tc.expected) {
+				t.Fatalf("Expected %v, got %v", tc.expected, err)
+			}
+
+			if current := gitCmd(t, dir, "rev-parse", "HEAD"); current != head {
+				t.Fatalf("Expected no commit to be created, HEAD moved from %s to %s", head, current)
+			}
+			if tags := gitCmd(t, dir, "tag", "-l"); tags != strings.Join(tc.tags, "\n") {
+				t.Fatalf("Expected no tag to be created, got %q", tags)
+			}
+		})
+	}
+}
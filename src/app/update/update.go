@@ -7,16 +7,21 @@ import (
 
 	"github.com/Masterminds/semver"
 	"github.com/newrelic/release-toolkit/src/app/common"
+	"github.com/newrelic/release-toolkit/src/app/config"
 	"github.com/newrelic/release-toolkit/src/changelog"
 	"github.com/newrelic/release-toolkit/src/changelog/sources/markdown/merger"
+	"github.com/newrelic/release-toolkit/src/monorepo"
 	"github.com/urfave/cli/v2"
 	"gopkg.in/yaml.v3"
 )
 
 const (
-	markdownPathFlag = "markdown"
-	versionFlag      = "version"
-	dateFlag         = "date"
+	markdownPathFlag  = "markdown"
+	versionFlag       = "version"
+	versionPrefixFlag = "version-prefix"
+	dateFlag          = "date"
+	templateFlag      = "template"
+	releaseNotesFlag  = "release-notes-for"
 )
 
 // Cmd is the cli.Command object for the update-markdown command.
@@ -39,6 +44,12 @@ var Cmd = &cli.Command{
 				"If omitted, no version header will be generated.",
 			Value: "",
 		},
+		&cli.StringFlag{
+			Name:    versionPrefixFlag,
+			EnvVars: common.EnvFor(versionPrefixFlag),
+			Usage:   "Prefix prepended to the version in the changelog section header.",
+			Value:   "v",
+		},
 		&cli.TimestampFlag{
 			Name:    dateFlag,
 			EnvVars: common.EnvFor(dateFlag),
@@ -47,17 +58,111 @@ var Cmd = &cli.Command{
 			Value:  cli.NewTimestamp(time.Now()),
 			Layout: "2006-01-02",
 		},
+		&cli.StringFlag{
+			Name:    templateFlag,
+			EnvVars: common.EnvFor(templateFlag),
+			Usage: "Path to a Go text/template file used to render the changelog section instead of the default layout. " +
+				"See README_CLI.md for the data model and available functions.",
+			Value: "",
+		},
+		&cli.StringSliceFlag{
+			Name:    releaseNotesFlag,
+			EnvVars: common.EnvFor(releaseNotesFlag),
+			Usage: "Names of the dependencies whose upstream release notes, embedded by link-dependencies --release-notes, " +
+				"are rendered in a collapsible block. The release notes of all dependencies are rendered if empty.",
+		},
 	},
+	Before: config.Apply,
 	Action: Update,
 }
 
+// Options holds the settings of update-markdown, which are taken from its flags by OptionsFromCli.
+type Options struct {
+	// YAML is the path to the changelog.yaml merged into Markdown.
+	YAML string
+	// Markdown is the path to the CHANGELOG.md that is updated.
+	Markdown string
+	Version  string
+	// VersionPrefix is prepended to Version in the header of the new section.
+	VersionPrefix string
+	// Date is stamped in the header of the new section. The current time is used if it is zero.
+	Date time.Time
+	// Template, if not empty, is the path to the template the new section is rendered with.
+	Template        string
+	ReleaseNotesFor []string
+}
+
+// OptionsFromCli returns the Options set by the flags of update-markdown in cCtx.
+func OptionsFromCli(cCtx *cli.Context) Options {
+	opts := Options{
+		YAML:          cCtx.String(common.YAMLFlag),
+		Markdown:      cCtx.String(markdownPathFlag),
+		Version:       cCtx.String(versionFlag),
+		VersionPrefix: cCtx.String(versionPrefixFlag),
+		Template:      cCtx.String(templateFlag),
+	}
+
+	if t := cCtx.Timestamp(dateFlag); t != nil {
+		opts.Date = *t
+	}
+
+	for _, name := range cCtx.StringSlice(releaseNotesFlag) {
+		if name != "" {
+			opts.ReleaseNotesFor = append(opts.ReleaseNotesFor, name)
+		}
+	}
+
+	return opts
+}
+
 // Update is a command function which loads a changelog.yaml file and merges it into an existing CHANGELOG.md document.
+// If a monorepo manifest is supplied, the changelog of each component marked for release in the summary is merged
+// into the component's CHANGELOG.md instead, using the version recorded for it.
 func Update(cCtx *cli.Context) error {
-	chPath := cCtx.String(common.YAMLFlag)
+	opts := OptionsFromCli(cCtx)
+
+	if manifestPath := cCtx.String(common.MonorepoFlag); manifestPath != "" {
+		return updateMonorepo(opts, manifestPath)
+	}
+
+	return Run(opts)
+}
+
+func updateMonorepo(opts Options, manifestPath string) error {
+	manifest, err := monorepo.Load(manifestPath)
+	if err != nil {
+		return fmt.Errorf("loading monorepo manifest: %w", err)
+	}
+
+	summary, err := monorepo.ReadSummary(manifest.Summary)
+	if err != nil {
+		return fmt.Errorf("loading monorepo summary: %w", err)
+	}
+
+	for _, release := range manifest.Releases(summary) {
+		componentOpts := opts
+		componentOpts.YAML = release.YAML
+		componentOpts.Markdown = release.Markdown
+		componentOpts.Version = release.Version
+
+		err = Run(componentOpts)
+		if err != nil {
+			return fmt.Errorf("updating component %q: %w", release.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// Run merges the changelog.yaml into the CHANGELOG.md of a single repository as configured by opts.
+func Run(opts Options) error {
+	chPath, currentMdPath := opts.YAML, opts.Markdown
+
 	chFile, err := os.Open(chPath)
 	if err != nil {
 		return fmt.Errorf("opening changelog file %q: %w", chPath, err)
 	}
+	defer chFile.Close()
 
 	ch := &changelog.Changelog{}
 	err = yaml.NewDecoder(chFile).Decode(ch)
@@ -65,7 +170,6 @@ func Update(cCtx *cli.Context) error {
 		return fmt.Errorf("loading changelog from file: %w", err)
 	}
 
-	currentMdPath := cCtx.String(markdownPathFlag)
 	newMdPath := currentMdPath + ".new"
 	bakMdPath := currentMdPath + ".bak"
 
@@ -73,22 +177,34 @@ func Update(cCtx *cli.Context) error {
 	if err != nil {
 		return fmt.Errorf("opening existing changelog at %q: %w", currentMdPath, err)
 	}
+	defer currentMdFile.Close()
 
 	newMdFile, err := os.Create(newMdPath)
 	if err != nil {
 		return fmt.Errorf("creating destination file at %q: %w", newMdPath, err)
 	}
+	defer newMdFile.Close()
 
-	version, err := semver.NewVersion(cCtx.String(versionFlag))
+	version, err := semver.NewVersion(opts.Version)
 	if err != nil {
 		return fmt.Errorf("parsing version: %w", err)
 	}
 
 	mrg := merger.New(ch, version)
-	if t := cCtx.Timestamp(dateFlag); t != nil {
-		tv := *t
+	mrg.ReleaseNotesFor = opts.ReleaseNotesFor
+	mrg.VersionPrefix = opts.VersionPrefix
+
+	if tplPath := opts.Template; tplPath != "" {
+		tpl, tErr := os.ReadFile(tplPath)
+		if tErr != nil {
+			return fmt.Errorf("reading template %q: %w", tplPath, tErr)
+		}
+		mrg.Template = string(tpl)
+	}
+
+	if !opts.Date.IsZero() {
 		mrg.ReleasedOn = func() time.Time {
-			return tv
+			return opts.Date
 		}
 	}
 
@@ -135,6 +135,38 @@ This is a release note
 
 ## v1.2.3 - 20YY-DD-MM
 
+### Enhancements
+- This is in the past and should be preserved
+			`) + "\n",
+		},
+		{
+			name: "Changelog_Without_Version_Prefix",
+			args: "-version v1.2.4 -version-prefix= -date 1993-09-21",
+			yaml: strings.TrimSpace(`
+changes:
+- type: enhancement
+  message: New feature has been added
+			`),
+			existing: strings.TrimSpace(`
+# Changelog
+This is based on blah blah blah
+
+## 1.2.3 - 20YY-DD-MM
+
+### Enhancements
+- This is in the past and should be preserved
+			`) + "\n",
+			expected: strings.TrimSpace(`
+# Changelog
+This is based on blah blah blah
+
+## 1.2.4 - 1993-09-21
+
+### 🚀 Enhancements
+- New feature has been added
+
+## 1.2.3 - 20YY-DD-MM
+
 ### Enhancements
 - This is in the past and should be preserved
 			`) + "\n",
@@ -314,3 +346,81 @@ Yada yada
 		)
 	}
 }
+
+//nolint:paralleltest // urfave/cli cannot be tested concurrently.
+func TestUpdate_Monorepo(t *testing.T) {
+	tDir := t.TempDir()
+
+	existing := "# Changelog\n\n## v0.1.0 - 2022-01-01\n\n### Enhancements\n- This is in the past and should be preserved\n"
+	files := map[string]string{
+		"monorepo.yaml": strings.TrimSpace(`
+summary: summary.yaml
+components:
+- name: agent
+  path: agent
+  tag-prefix: agent-
+- name: operator
+  path: operator
+  tag-prefix: operator-
+		`),
+		"summary.yaml": strings.TrimSpace(`
+components:
+- name: agent
+  next: v1.3.0
+  release: true
+- name: operator
+  next: v0.2.0
+  release: false
+		`),
+		"agent/changelog.yaml": strings.TrimSpace(`
+changes:
+- type: enhancement
+  message: New feature has been added
+		`),
+		"agent/CHANGELOG.md":    existing,
+		"operator/CHANGELOG.md": existing,
+	}
+	for name, content := range files {
+		_ = os.MkdirAll(path.Dir(path.Join(tDir, name)), 0o755)
+		if err := os.WriteFile(path.Join(tDir, name), []byte(content), 0o600); err != nil {
+			t.Fatalf("Error creating %s: %v", name, err)
+		}
+	}
+
+	err := app.App().Run(strings.Fields(fmt.Sprintf(
+		"rt --monorepo %s update-markdown -date 1993-09-21", path.Join(tDir, "monorepo.yaml"),
+	)))
+	if err != nil {
+		t.Fatalf("Error running app: %v", err)
+	}
+
+	expected := strings.TrimSpace(`
+# Changelog
+
+## v1.3.0 - 1993-09-21
+
+### 🚀 Enhancements
+- New feature has been added
+
+## v0.1.0 - 2022-01-01
+
+### Enhancements
+- This is in the past and should be preserved
+	`) + "\n"
+
+	agentMd, err := os.ReadFile(path.Join(tDir, "agent", "CHANGELOG.md"))
+	if err != nil {
+		t.Fatalf("Error reading agent changelog: %v", err)
+	}
+	if diff := cmp.Diff(expected, string(agentMd)); diff != "" {
+		t.Fatalf("Agent changelog is not as expected:\n%s", diff)
+	}
+
+	operatorMd, err := os.ReadFile(path.Join(tDir, "operator", "CHANGELOG.md"))
+	if err != nil {
+		t.Fatalf("Error reading operator changelog: %v", err)
+	}
+	if diff := cmp.Diff(existing, string(operatorMd)); diff != "" {
+		t.Fatalf("Operator changelog should not have been modified:\n%s", diff)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"os"
 
 	"github.com/newrelic/release-toolkit/src/app/common"
+	"github.com/newrelic/release-toolkit/src/app/config"
 	"github.com/newrelic/release-toolkit/src/app/gha"
 	"github.com/newrelic/release-toolkit/src/changelog/sources/markdown"
 	"github.com/urfave/cli/v2"
@@ -36,6 +37,7 @@ var Cmd = &cli.Command{
 			Value:   1,
 		},
 	},
+	Before: config.Apply,
 	Action: Validate,
 }
 
@@ -59,6 +61,7 @@ func Validate(cCtx *cli.Context) error {
 
 	for _, err := range errs {
 		_, _ = fmt.Fprintln(cCtx.App.ErrWriter, err)
+		gh.Error(gha.Annotation{Title: "Invalid changelog", File: mdPath}, err.Error())
 	}
 	gh.SetOutput(validOutput, len(errs) == 0)
 
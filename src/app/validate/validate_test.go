@@ -8,10 +8,15 @@ import (
 	"testing"
 
 	"github.com/newrelic/release-toolkit/src/app"
+	"github.com/newrelic/release-toolkit/src/app/common"
 )
 
 //nolint:paralleltest,funlen // urfave/cli cannot be tested concurrently.
 func TestValidate(t *testing.T) {
+	// Workflow commands are asserted on stdout, so environment files set when running on Github Actions are ignored.
+	t.Setenv(common.GHAOutputEnv, "")
+	t.Setenv(common.GHAStepSummaryEnv, "")
+
 	for _, tc := range []struct {
 		name        string
 		ghaArg      string
@@ -74,7 +79,11 @@ Support has been removed
 "Important announcement (note)" header found with empty content
 "Breaking" header must contain only an itemized list
 `, "\n"),
-			expectedGha: "::set-output name=valid::false\n",
+			expectedGha: strings.TrimLeft(`
+::error title=Invalid changelog,file={MD}::"Important announcement (note)" header found with empty content
+::error title=Invalid changelog,file={MD}::"Breaking" header must contain only an itemized list
+::set-output name=valid::false
+`, "\n"),
 		},
 		{
 			name: "Valid_Changelog",
@@ -179,6 +188,7 @@ unreleased changelog can't only contain notes
 				t.Fatalf("Expected:\n%s\n\napp printed:\n%s", tc.expectedErr, actual)
 			}
 
+			tc.expectedGha = strings.ReplaceAll(tc.expectedGha, "{MD}", mdPath)
 			if actual := buf.String(); actual != tc.expectedGha {
 				t.Fatalf("Expected:\n%s\n\napp printed:\n%s", tc.expectedGha, actual)
 			}
@@ -3,7 +3,10 @@ package bumper
 import (
 	"errors"
 	"fmt"
+	"regexp"
 	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/Masterminds/semver"
 	"github.com/newrelic/release-toolkit/src/bump"
@@ -12,15 +15,32 @@ import (
 )
 
 var (
-	ErrEmptySource  = errors.New("could not find any existing version")
-	ErrNoNewVersion = errors.New("bump did not change the version")
+	ErrEmptySource          = errors.New("could not find any existing version")
+	ErrNoNewVersion         = errors.New("bump did not change the version")
+	ErrNoPrerelease         = errors.New("latest version is not a prerelease")
+	ErrInvalidPrereleaseID  = errors.New("prerelease identifier must be a non-empty string of alphanumerics and hyphens")
+	ErrPrereleaseAndPromote = errors.New("prerelease and promote modes are mutually exclusive")
+	ErrOutsideConstraint    = errors.New("next version does not satisfy the version constraint")
+	ErrPrereleaseDowngrade  = errors.New("next prerelease would not be greater than the latest version")
 )
 
+var prereleaseIDRegex = regexp.MustCompile(`^[0-9A-Za-z-]+$`)
+
 // Bumper takes a changelog and a version and figures out the next one.
 type Bumper struct {
 	changelog     changelog.Changelog
 	EntryCap      bump.Type
 	DependencyCap bump.Type
+	// Prerelease, if not empty, makes BumpSource compute the next prerelease version using this identifier, e.g.
+	// `rc` for `v2.0.0-rc.1`.
+	Prerelease string
+	// Promote makes BumpSource drop the prerelease suffix from the latest version instead of bumping it.
+	Promote bool
+	// Scheme defines how versions are bumped. It defaults to version.Semver.
+	Scheme version.Scheme
+	// Constraint, if set, makes BumpSource fail with ErrOutsideConstraint instead of returning a version that does not
+	// satisfy it, such as a new major on a maintenance line.
+	Constraint *version.Constraint
 }
 
 // New creates a new bumper.
@@ -29,6 +49,7 @@ func New(c changelog.Changelog) Bumper {
 		changelog:     c,
 		EntryCap:      bump.Major,
 		DependencyCap: bump.Major,
+		Scheme:        version.Semver{},
 	}
 }
 
@@ -46,12 +67,31 @@ func (b Bumper) Bump(v *semver.Version) *semver.Version {
 	}
 	dependencyBump = dependencyBump.Cap(b.DependencyCap)
 
-	return bump.Bump(v, entryBump.With(dependencyBump))
+	scheme := b.Scheme
+	if scheme == nil {
+		scheme = version.Semver{}
+	}
+
+	return scheme.Bump(v, entryBump.With(dependencyBump))
 }
 
 // BumpSource operates just like Bump, except it extracts tags from the supplied tag.Source and applies the bump
 // on the latest (in semver order) version it finds.
+// If Prerelease or Promote are set, the next prerelease or the promoted version are returned instead.
 func (b Bumper) BumpSource(source version.Source) (*semver.Version, error) {
+	next, err := b.bumpSource(source)
+	if err != nil {
+		return next, err
+	}
+
+	if b.Constraint != nil && !b.Constraint.Check(next) {
+		return nil, fmt.Errorf("%w: %s does not satisfy %q", ErrOutsideConstraint, next.Original(), b.Constraint)
+	}
+
+	return next, nil
+}
+
+func (b Bumper) bumpSource(source version.Source) (*semver.Version, error) {
 	versions, err := source.Versions()
 	if err != nil {
 		return nil, fmt.Errorf("getting versions from source: %w", err)
@@ -66,6 +106,15 @@ func (b Bumper) BumpSource(source version.Source) (*semver.Version, error) {
 		return versions[i].GreaterThan(versions[j])
 	})
 
+	switch {
+	case b.Promote && b.Prerelease != "":
+		return nil, ErrPrereleaseAndPromote
+	case b.Promote:
+		return promote(versions[0])
+	case b.Prerelease != "":
+		return b.bumpPrerelease(versions)
+	}
+
 	nextVersion := b.Bump(versions[0])
 	if versions[0] == nextVersion {
 		return nextVersion, ErrNoNewVersion
@@ -73,3 +122,120 @@ func (b Bumper) BumpSource(source version.Source) (*semver.Version, error) {
 
 	return nextVersion, nil
 }
+
+// promote returns the supplied prerelease version without its prerelease and metadata suffixes.
+func promote(latest *semver.Version) (*semver.Version, error) {
+	if latest.Prerelease() == "" {
+		return latest, fmt.Errorf("promoting %s: %w", latest.Original(), ErrNoPrerelease)
+	}
+
+	return base(latest), nil
+}
+
+// bumpPrerelease computes the next prerelease from a list of versions sorted from largest to smallest.
+// If the latest version is a stable one, or if the bump required by the changelog, applied to the latest stable
+// version, is larger than the one the current prerelease line is heading to, a new `<identifier>.1` prerelease is
+// started. Otherwise, the numeric suffix of the latest prerelease with the same base version and identifier is
+// incremented. ErrPrereleaseDowngrade is returned if the resulting prerelease would precede the latest one, e.g. when
+// going from `rc` back to `beta`.
+func (b Bumper) bumpPrerelease(versions []*semver.Version) (*semver.Version, error) {
+	if !prereleaseIDRegex.MatchString(b.Prerelease) {
+		return nil, fmt.Errorf("%q: %w", b.Prerelease, ErrInvalidPrereleaseID)
+	}
+
+	latest := versions[0]
+
+	if latest.Prerelease() == "" {
+		next := b.Bump(latest)
+		if next == latest {
+			return latest, ErrNoNewVersion
+		}
+
+		return withPrerelease(next, b.Prerelease, 1)
+	}
+
+	// The latest version is a prerelease. The bump required by the changelog, which only holds the changes since the
+	// latest tag, is applied to the latest stable version to tell whether the current prerelease line still covers
+	// them or a larger one is needed. Changes released in earlier prereleases of the line are already covered by it.
+	lineBase := base(latest)
+	required := lineBase
+	if stable := latestStable(versions); stable != nil {
+		required = b.Bump(stable)
+		if required == stable {
+			return latest, ErrNoNewVersion
+		}
+	}
+
+	if required.GreaterThan(lineBase) {
+		return withPrerelease(required, b.Prerelease, 1)
+	}
+
+	next, err := withPrerelease(lineBase, b.Prerelease, lastPrereleaseNumber(versions, lineBase, b.Prerelease)+1)
+	if err != nil {
+		return nil, err
+	}
+
+	if !next.GreaterThan(latest) {
+		return nil, fmt.Errorf("%w: %s is not greater than %s", ErrPrereleaseDowngrade, next.Original(), latest.Original())
+	}
+
+	return next, nil
+}
+
+// latestStable returns the first version that is not a prerelease from a list of versions sorted from largest to
+// smallest, or nil if there is none.
+func latestStable(versions []*semver.Version) *semver.Version {
+	for _, v := range versions {
+		if v.Prerelease() == "" {
+			return v
+		}
+	}
+
+	return nil
+}
+
+// lastPrereleaseNumber returns the largest numeric suffix of prereleases of the given base version that use the
+// specified identifier, e.g. 2 for [v1.0.0-rc.1, v1.0.0-rc.2, v1.0.0-beta.3]. It returns 0 if there are none.
+func lastPrereleaseNumber(versions []*semver.Version, baseVersion *semver.Version, id string) int64 {
+	var last int64
+
+	for _, v := range versions {
+		if !base(v).Equal(baseVersion) {
+			continue
+		}
+
+		parts := strings.Split(v.Prerelease(), ".")
+		if len(parts) != 2 || parts[0] != id {
+			continue
+		}
+
+		n, err := strconv.ParseInt(parts[1], 10, 64)
+		if err != nil {
+			continue
+		}
+
+		if n > last {
+			last = n
+		}
+	}
+
+	return last
+}
+
+// base returns a copy of v without prerelease and metadata.
+func base(v *semver.Version) *semver.Version {
+	// Errors are only returned for invalid prerelease and metadata strings, and empty strings are always valid.
+	b, _ := v.SetPrerelease("")
+	b, _ = b.SetMetadata("")
+
+	return &b
+}
+
+func withPrerelease(v *semver.Version, id string, n int64) (*semver.Version, error) {
+	pre, err := base(v).SetPrerelease(fmt.Sprintf("%s.%d", id, n))
+	if err != nil {
+		return nil, fmt.Errorf("setting prerelease on %s: %w", v.Original(), err)
+	}
+
+	return &pre, nil
+}
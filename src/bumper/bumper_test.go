@@ -3,11 +3,13 @@ package bumper_test
 import (
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/Masterminds/semver"
 	"github.com/newrelic/release-toolkit/src/bump"
 	"github.com/newrelic/release-toolkit/src/bumper"
 	"github.com/newrelic/release-toolkit/src/changelog"
+	"github.com/newrelic/release-toolkit/src/version"
 )
 
 type testCase struct {
@@ -361,6 +363,287 @@ func TestBumper_BumpSource_Errors(t *testing.T) {
 	}
 }
 
+//nolint:funlen
+func TestBumper_BumpSource_Prerelease(t *testing.T) {
+	t.Parallel()
+
+	bugfix := changelog.Changelog{Changes: []changelog.Entry{{Type: changelog.TypeBugfix}}}
+	enhancement := changelog.Changelog{Changes: []changelog.Entry{{Type: changelog.TypeEnhancement}}}
+	breaking := changelog.Changelog{Changes: []changelog.Entry{{Type: changelog.TypeBreaking}}}
+
+	for _, tc := range []struct {
+		name          string
+		changelog     changelog.Changelog
+		source        mockSource
+		prerelease    string
+		promote       bool
+		expected      string
+		errorExpected error
+	}{
+		{
+			name:       "Starts_Prerelease_From_Stable",
+			changelog:  breaking,
+			source:     mockSource{"v1.4.0", "v1.3.0"},
+			prerelease: "rc",
+			expected:   "v2.0.0-rc.1",
+		},
+		{
+			name:       "Increments_Prerelease_When_Base_Is_Unchanged",
+			changelog:  breaking,
+			source:     mockSource{"v1.4.0", "v2.0.0-rc.1", "v2.0.0-rc.2"},
+			prerelease: "rc",
+			expected:   "v2.0.0-rc.3",
+		},
+		{
+			name:       "Increments_Prerelease_Numerically",
+			changelog:  bugfix,
+			source:     mockSource{"v1.4.0", "v1.4.1-rc.9", "v1.4.1-rc.10"},
+			prerelease: "rc",
+			expected:   "v1.4.1-rc.11",
+		},
+		{
+			name:       "Increments_Prerelease_When_Smaller_Bump_Is_Required",
+			changelog:  bugfix,
+			source:     mockSource{"v1.4.0", "v2.0.0-rc.1"},
+			prerelease: "rc",
+			expected:   "v2.0.0-rc.2",
+		},
+		{
+			name:       "Starts_New_Prerelease_When_Bump_Grows_Base",
+			changelog:  breaking,
+			source:     mockSource{"v1.4.0", "v1.5.0-rc.1", "v1.5.0-rc.2"},
+			prerelease: "rc",
+			expected:   "v2.0.0-rc.1",
+		},
+		{
+			name:       "Starts_New_Identifier_On_Same_Base",
+			changelog:  enhancement,
+			source:     mockSource{"v1.4.0", "v1.5.0-beta.3"},
+			prerelease: "rc",
+			expected:   "v1.5.0-rc.1",
+		},
+		{
+			name:          "Refuses_Downgrading_Identifier",
+			changelog:     bugfix,
+			source:        mockSource{"v0.9.0", "v1.0.0-rc.1", "v1.0.0-rc.2"},
+			prerelease:    "beta",
+			errorExpected: bumper.ErrPrereleaseDowngrade,
+		},
+		{
+			name:       "Increments_Prerelease_Without_Stable_Versions",
+			changelog:  bugfix,
+			source:     mockSource{"v1.0.0-alpha.1"},
+			prerelease: "alpha",
+			expected:   "v1.0.0-alpha.2",
+		},
+		{
+			name:          "No_Prerelease_Without_Changes",
+			changelog:     changelog.Changelog{},
+			source:        mockSource{"v1.4.0", "v2.0.0-rc.1"},
+			prerelease:    "rc",
+			expected:      "v2.0.0-rc.1",
+			errorExpected: bumper.ErrNoNewVersion,
+		},
+		{
+			name:          "Invalid_Identifier",
+			changelog:     bugfix,
+			source:        mockSource{"v1.4.0"},
+			prerelease:    "rc.1",
+			errorExpected: bumper.ErrInvalidPrereleaseID,
+		},
+		{
+			name:      "Promotes_Latest_Prerelease",
+			changelog: bugfix,
+			source:    mockSource{"v1.4.0", "v2.0.0-rc.1", "v2.0.0-rc.2+build.3"},
+			promote:   true,
+			expected:  "v2.0.0",
+		},
+		{
+			name:          "Promote_Fails_On_Stable",
+			changelog:     bugfix,
+			source:        mockSource{"v1.4.0", "v1.4.0-rc.1"},
+			promote:       true,
+			expected:      "v1.4.0",
+			errorExpected: bumper.ErrNoPrerelease,
+		},
+		{
+			name:          "Promote_And_Prerelease_Are_Exclusive",
+			changelog:     bugfix,
+			source:        mockSource{"v1.4.0"},
+			promote:       true,
+			prerelease:    "rc",
+			errorExpected: bumper.ErrPrereleaseAndPromote,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			b := bumper.New(tc.changelog)
+			b.Prerelease = tc.prerelease
+			b.Promote = tc.promote
+
+			next, err := b.BumpSource(tc.source)
+			if !errors.Is(err, tc.errorExpected) {
+				t.Fatalf("Expected error %v, got %v", tc.errorExpected, err)
+			}
+
+			if tc.expected == "" {
+				return
+			}
+
+			if next == nil || next.Original() != tc.expected {
+				t.Fatalf("Expected %v, got %v", tc.expected, next)
+			}
+		})
+	}
+}
+
+func TestBumper_BumpSource_CalVer(t *testing.T) {
+	t.Parallel()
+
+	now := func() time.Time {
+		return time.Date(2024, time.March, 15, 0, 0, 0, 0, time.UTC)
+	}
+
+	for _, tc := range []struct {
+		name          string
+		source        mockSource
+		changelog     changelog.Changelog
+		expected      string
+		errorExpected error
+	}{
+		{
+			name:     "Increments_Micro_Same_Month",
+			source:   mockSource{"v2024.2.4", "v2024.3.0", "v2024.3.1"},
+			expected: "v2024.3.2",
+			changelog: changelog.Changelog{
+				Changes: []changelog.Entry{{Type: changelog.TypeBreaking}},
+			},
+		},
+		{
+			name:     "Resets_Micro_New_Month",
+			source:   mockSource{"v2024.1.0", "v2024.2.4"},
+			expected: "v2024.3.0",
+			changelog: changelog.Changelog{
+				Changes: []changelog.Entry{{Type: changelog.TypeBugfix}},
+			},
+		},
+		{
+			name:     "Resets_Micro_New_Year",
+			source:   mockSource{"2023.12.7"},
+			expected: "2024.3.0",
+			changelog: changelog.Changelog{
+				Dependencies: []changelog.Dependency{{Name: "foo", To: semver.MustParse("v1.2.3")}},
+			},
+		},
+		{
+			name:          "No_Changes",
+			source:        mockSource{"v2024.2.4"},
+			expected:      "v2024.2.4",
+			errorExpected: bumper.ErrNoNewVersion,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			b := bumper.New(tc.changelog)
+			b.Scheme = version.CalVer{Now: now}
+
+			next, err := b.BumpSource(tc.source)
+			if !errors.Is(err, tc.errorExpected) {
+				t.Fatalf("Expected error %v, got %v", tc.errorExpected, err)
+			}
+
+			if next == nil || next.Original() != tc.expected {
+				t.Fatalf("Expected %v, got %v", tc.expected, next)
+			}
+		})
+	}
+}
+
+func TestBumper_BumpSource_Constraint(t *testing.T) {
+	t.Parallel()
+
+	bugfix := changelog.Changelog{Changes: []changelog.Entry{{Type: changelog.TypeBugfix}}}
+	enhancement := changelog.Changelog{Changes: []changelog.Entry{{Type: changelog.TypeEnhancement}}}
+	breaking := changelog.Changelog{Changes: []changelog.Entry{{Type: changelog.TypeBreaking}}}
+
+	for _, tc := range []struct {
+		name          string
+		constraint    string
+		changelog     changelog.Changelog
+		prerelease    string
+		expected      string
+		errorExpected error
+	}{
+		{
+			name:       "Patch_Within_Minor_Line",
+			constraint: "~1.4",
+			changelog:  bugfix,
+			expected:   "v1.4.3",
+		},
+		{
+			name:          "Minor_Leaves_Minor_Line",
+			constraint:    "~1.4",
+			changelog:     enhancement,
+			errorExpected: bumper.ErrOutsideConstraint,
+		},
+		{
+			name:       "Minor_Within_Major_Line",
+			constraint: "<2.0.0",
+			changelog:  enhancement,
+			expected:   "v1.5.0",
+		},
+		{
+			name:          "Breaking_Leaves_Major_Line",
+			constraint:    "<2.0.0",
+			changelog:     breaking,
+			errorExpected: bumper.ErrOutsideConstraint,
+		},
+		{
+			name:          "Breaking_Prerelease_Leaves_Major_Line",
+			constraint:    "<2.0.0",
+			changelog:     breaking,
+			prerelease:    "rc",
+			errorExpected: bumper.ErrOutsideConstraint,
+		},
+		{
+			name:          "No_Changes",
+			constraint:    "~1.4",
+			expected:      "v1.4.2",
+			errorExpected: bumper.ErrNoNewVersion,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			constraint, err := version.NewConstraint(tc.constraint)
+			if err != nil {
+				t.Fatalf("Error parsing constraint: %v", err)
+			}
+
+			b := bumper.New(tc.changelog)
+			b.Constraint = constraint
+			b.Prerelease = tc.prerelease
+
+			next, err := b.BumpSource(mockSource{"v1.4.1", "v1.4.2"})
+			if !errors.Is(err, tc.errorExpected) {
+				t.Fatalf("Expected error %v, got %v", tc.errorExpected, err)
+			}
+
+			if tc.expected == "" && next != nil {
+				t.Fatalf("Expected no version, got %v", next)
+			}
+			if tc.expected != "" && (next == nil || next.Original() != tc.expected) {
+				t.Fatalf("Expected %v, got %v", tc.expected, next)
+			}
+		})
+	}
+}
+
 type mockSource []string
 
 func (m mockSource) Versions() ([]*semver.Version, error) {
@@ -94,8 +94,8 @@ func (e Entry) String() string {
 	buf := &strings.Builder{}
 	buf.WriteString(e.Message)
 
-	if e.Meta.Author != "" {
-		_, _ = fmt.Fprintf(buf, ", by %s", e.Meta.Author)
+	if len(e.Meta.Authors) > 0 {
+		_, _ = fmt.Fprintf(buf, ", by %s", joinAuthors(e.Meta.Authors))
 	}
 
 	if e.Meta.PR != "" {
@@ -107,11 +107,44 @@ func (e Entry) String() string {
 	return buf.String()
 }
 
+// joinAuthors returns a human-readable list of authors, e.g. `@alice, @bob and Carol`.
+func joinAuthors(authors []string) string {
+	if len(authors) <= 1 {
+		return strings.Join(authors, "")
+	}
+
+	return strings.Join(authors[:len(authors)-1], ", ") + " and " + authors[len(authors)-1]
+}
+
 // EntryMeta holds information about who made the change and where.
 type EntryMeta struct {
-	Author string `yaml:"author,omitempty"`
-	PR     string `yaml:"pr,omitempty"`
-	Commit string `yaml:"commit,omitempty"`
+	// Authors holds who made the change, either names or GitHub handles such as `@alice`. The first one is the author
+	// of the commit, and the rest are its co-authors.
+	Authors []string `yaml:"authors,omitempty"`
+	PR      string   `yaml:"pr,omitempty"`
+	Commit  string   `yaml:"commit,omitempty"`
+	// Scope is the part of the project affected by the change, as noted in conventional commits.
+	Scope string `yaml:"scope,omitempty"`
+}
+
+// UnmarshalYAML reads EntryMeta, also accepting the single `author` key written by previous versions.
+func (m *EntryMeta) UnmarshalYAML(node *yaml.Node) error {
+	type plainMeta EntryMeta
+	meta := struct {
+		plainMeta `yaml:",inline"`
+		Author    string `yaml:"author"`
+	}{}
+
+	if err := node.Decode(&meta); err != nil {
+		return fmt.Errorf("decoding entry meta: %w", err)
+	}
+
+	*m = EntryMeta(meta.plainMeta)
+	if meta.Author != "" && len(m.Authors) == 0 {
+		m.Authors = []string{meta.Author}
+	}
+
+	return nil
 }
 
 // Dependency models a dependency that has been changed in the project.
@@ -120,8 +153,21 @@ type Dependency struct {
 	From *semver.Version `yaml:"from,omitempty"`
 	To   *semver.Version `yaml:"to,omitempty"`
 	// Link to the changelog for the release of this dependency.
-	Changelog string    `yaml:"changelog"`
-	Meta      EntryMeta `yaml:"meta,omitempty"`
+	Changelog string `yaml:"changelog"`
+	// Link to the differences between the From and To versions of this dependency.
+	Compare string `yaml:"compare,omitempty"`
+	// ReleaseNotes holds the notes published upstream for each release of this dependency after From and up to To,
+	// newest first.
+	ReleaseNotes []ReleaseNote `yaml:"release-notes,omitempty"`
+	Meta         EntryMeta     `yaml:"meta,omitempty"`
+}
+
+// ReleaseNote is the body of an upstream release of a dependency.
+type ReleaseNote struct {
+	// Version is the tag of the release, e.g. `v1.2.3`.
+	Version string `yaml:"version"`
+	// Notes is the markdown body of the release.
+	Notes string `yaml:"notes"`
 }
 
 // BumpType returns which version should be bumped due to this dependency update.
@@ -170,26 +216,34 @@ func (d Dependency) String() string {
 		_, _ = fmt.Fprintf(buf, " - [Changelog 🔗](%s)", d.Changelog)
 	}
 
+	if d.Compare != "" {
+		_, _ = fmt.Fprintf(buf, " - [Compare 🔗](%s)", d.Compare)
+	}
+
 	return buf.String()
 }
 
 // plainDependency is a helper struct where To and From are strings rather than semver.Version. We use this struct
 // to marshal and unmarshal from YAML format because unfortunately, semver.Version does not implement yaml.Marshaler.
 type plainDependency struct {
-	Name      string    `yaml:"name"`
-	From      string    `yaml:"from,omitempty"`
-	To        string    `yaml:"to,omitempty"`
-	Changelog string    `yaml:"changelog,omitempty"`
-	Meta      EntryMeta `yaml:"meta,omitempty"`
+	Name         string        `yaml:"name"`
+	From         string        `yaml:"from,omitempty"`
+	To           string        `yaml:"to,omitempty"`
+	Changelog    string        `yaml:"changelog,omitempty"`
+	Compare      string        `yaml:"compare,omitempty"`
+	ReleaseNotes []ReleaseNote `yaml:"release-notes,omitempty"`
+	Meta         EntryMeta     `yaml:"meta,omitempty"`
 }
 
 // MarshalYAML copies the contents of Dependency to a plainDependency and returns it for the generic marshaler to
 // encode it.
 func (d Dependency) MarshalYAML() (interface{}, error) {
 	pd := plainDependency{
-		Name:      d.Name,
-		Changelog: d.Changelog,
-		Meta:      d.Meta,
+		Name:         d.Name,
+		Changelog:    d.Changelog,
+		Compare:      d.Compare,
+		ReleaseNotes: d.ReleaseNotes,
+		Meta:         d.Meta,
 	}
 
 	if d.To != nil {
@@ -212,6 +266,8 @@ func (d *Dependency) UnmarshalYAML(value *yaml.Node) error {
 
 	d.Name = pd.Name
 	d.Changelog = pd.Changelog
+	d.Compare = pd.Compare
+	d.ReleaseNotes = pd.ReleaseNotes
 	d.Meta = pd.Meta
 
 	if pd.To != "" {
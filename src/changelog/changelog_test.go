@@ -36,7 +36,7 @@ func TestChangelog_Merge(t *testing.T) {
 					Held:  true,
 					Notes: "### Example notes section\nThey are very important",
 					Changes: []changelog.Entry{
-						{Message: "Change two", Meta: changelog.EntryMeta{Author: "roobre"}},
+						{Message: "Change two", Meta: changelog.EntryMeta{Authors: []string{"roobre"}}},
 						{Message: "Change three"},
 					},
 					Dependencies: []changelog.Dependency{
@@ -55,7 +55,7 @@ func TestChangelog_Merge(t *testing.T) {
 				Notes: "### Example notes section\nThey are very important\n\n### Another section\nEven more important",
 				Changes: []changelog.Entry{
 					{Message: "Change one", Type: changelog.TypeBugfix},
-					{Message: "Change two", Meta: changelog.EntryMeta{Author: "roobre"}},
+					{Message: "Change two", Meta: changelog.EntryMeta{Authors: []string{"roobre"}}},
 					{Message: "Change three"},
 					{Message: "Change four"},
 				},
@@ -151,11 +151,11 @@ func TestChangelog_Merge_Does_Duplicate_Entries(t *testing.T) {
 
 	ch.Merge(&ch)
 
-	if ch.Dependencies[0] != ch.Dependencies[2] {
+	if !reflect.DeepEqual(ch.Dependencies[0], ch.Dependencies[2]) {
 		t.Fatalf("Dependencies were deduplicated: %s != %s", ch.Dependencies[0].Name, ch.Dependencies[2].Name)
 	}
 
-	if ch.Changes[0] != ch.Changes[1] {
+	if !reflect.DeepEqual(ch.Changes[0], ch.Changes[1]) {
 		t.Fatalf("Changes were deduplicated: %s != %s", ch.Changes[0].Message, ch.Changes[1].Message)
 	}
 }
@@ -250,8 +250,8 @@ func TestYaml(t *testing.T) {
 				Type:    changelog.TypeBugfix,
 				Message: "Fixed this",
 				Meta: changelog.EntryMeta{
-					Author: "roobre",
-					Commit: "abcdef",
+					Authors: []string{"roobre"},
+					Commit:  "abcdef",
 				},
 			},
 			{
@@ -273,6 +273,9 @@ func TestYaml(t *testing.T) {
 			{
 				Name: "linux",
 				To:   semver.MustParse("5.15.52"),
+				ReleaseNotes: []changelog.ReleaseNote{
+					{Version: "v5.15.52", Notes: "### Fixes\n- A fix"},
+				},
 			},
 		},
 	}
@@ -285,7 +288,8 @@ changes:
     - type: bugfix
       message: Fixed this
       meta:
-        author: roobre
+        authors:
+            - roobre
         commit: abcdef
     - type: breaking
       message: Broken that
@@ -298,6 +302,11 @@ dependencies:
         pr: "22"
     - name: linux
       to: 5.15.52
+      release-notes:
+        - version: v5.15.52
+          notes: |-
+            ### Fixes
+            - A fix
 	`) + "\n"
 
 	t.Run("Marshal", func(t *testing.T) {
@@ -337,4 +346,18 @@ dependencies:
 			t.Fatalf("Changelogs are not equal")
 		}
 	})
+
+	t.Run("Unmarshal_Single_Author", func(t *testing.T) {
+		t.Parallel()
+
+		actual := changelog.Changelog{}
+		err := yaml.Unmarshal([]byte(strings.Replace(yml, "authors:\n            - roobre", "author: roobre", 1)), &actual)
+		if err != nil {
+			t.Fatalf("Error unmarshaling changelog: %v", err)
+		}
+
+		if !reflect.DeepEqual(ch, actual) {
+			t.Fatalf("Changelogs written with a single author are not read as expected")
+		}
+	})
 }
@@ -2,13 +2,23 @@
 package linker
 
 import (
+	"sync"
+
 	"github.com/newrelic/release-toolkit/src/changelog"
 	log "github.com/sirupsen/logrus"
 )
 
-// Linker is an object containing a map of Mappers that will be applied to Map a changelog to a dependency.
+// Linker is an object containing a map of Mappers that will be applied to Map a changelog to a dependency, of
+// Comparers that will be applied to link to the differences between the versions of a dependency, and of
+// NotesFetchers that will be applied to embed the upstream release notes of a dependency.
 type Linker struct {
-	Mappers []Mapper
+	Mappers       []Mapper
+	Comparers     []Comparer
+	NotesFetchers []NotesFetcher
+	// Concurrency is the maximum number of dependencies linked at the same time, as mappers and comparers may perform
+	// requests to check links. Dependencies are linked one at a time if it is lower than 2. Mappers and comparers must
+	// be safe for concurrent use if it is higher.
+	Concurrency int
 }
 
 // Mapper is any object that can link to a changelog for a dependency.
@@ -16,34 +26,86 @@ type Mapper interface {
 	Map(dep changelog.Dependency) string
 }
 
+// Comparer is any object that can link to the differences between the From and To versions of a dependency.
+// Comparers run after Mappers, so they can rely on the changelog link of the dependency if there is one.
+type Comparer interface {
+	Compare(dep changelog.Dependency) string
+}
+
+// NotesFetcher is any object that can fetch the upstream release notes of the versions of a dependency after From and
+// up to To. NotesFetchers run after Mappers, so they can rely on the changelog link of the dependency if there is one.
+type NotesFetcher interface {
+	Notes(dep changelog.Dependency) []changelog.ReleaseNote
+}
+
 func New(mappers ...Mapper) Linker {
 	return Linker{
 		Mappers: mappers,
 	}
 }
 
-// Link will try to Link all the dependencies in a changelog.yml to the changelogs found in the mappers.
+// Link will try to Link all the dependencies in a changelog.yml to the changelogs found in the mappers, to the
+// differences found by the comparers, and to the release notes found by the notes fetchers. Up to Concurrency dependencies are linked at the same time.
 func (l Linker) Link(cl *changelog.Changelog) error {
+	workers := l.Concurrency
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > len(cl.Dependencies) {
+		workers = len(cl.Dependencies)
+	}
+
+	indexes := make(chan int)
+	wg := sync.WaitGroup{}
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			// Each worker only modifies the dependencies whose index it receives.
+			for i := range indexes {
+				l.link(&cl.Dependencies[i])
+			}
+		}()
+	}
+
 	for i := range cl.Dependencies {
-		dep := &cl.Dependencies[i]
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
 
-		if dep.Changelog != "" {
-			log.Debugf("Skipping changelog linking for %q as it is already linked to %q", dep.Name, dep.Changelog)
-			continue
-		}
+	return nil
+}
 
-		if dep.To == nil {
-			log.Debugf("Skipping changelog linking for %q as new version is unknown", dep.Name)
-			continue
-		}
+// link sets the changelog and compare links, and the release notes, of dep, unless they are already set.
+func (l Linker) link(dep *changelog.Dependency) {
+	if dep.To == nil {
+		log.Debugf("Skipping changelog linking for %q as new version is unknown", dep.Name)
+		return
+	}
 
-		link := l.Map(*dep)
-		if link != "" {
-			dep.Changelog = link
-		}
+	if dep.Changelog != "" {
+		log.Debugf("Skipping changelog linking for %q as it is already linked to %q", dep.Name, dep.Changelog)
+	} else if link := l.Map(*dep); link != "" {
+		dep.Changelog = link
 	}
 
-	return nil
+	if dep.From == nil {
+		log.Debugf("Skipping compare linking and release notes for %q as previous version is unknown", dep.Name)
+		return
+	}
+
+	if dep.Compare != "" {
+		log.Debugf("Skipping compare linking for %q as it is already linked to %q", dep.Name, dep.Compare)
+	} else if link := l.Compare(*dep); link != "" {
+		dep.Compare = link
+	}
+
+	if len(dep.ReleaseNotes) > 0 {
+		log.Debugf("Skipping release notes for %q as it already has them", dep.Name)
+	} else if notes := l.Notes(*dep); len(notes) > 0 {
+		dep.ReleaseNotes = notes
+	}
 }
 
 // Map will iterate all the mappers to map dependencies with their changelogs.
@@ -57,3 +119,27 @@ func (l Linker) Map(dep changelog.Dependency) string {
 
 	return ""
 }
+
+// Compare will iterate all the comparers to link dependencies with the differences between their versions.
+func (l Linker) Compare(dep changelog.Dependency) string {
+	for _, comparer := range l.Comparers {
+		link := comparer.Compare(dep)
+		if link != "" {
+			return link
+		}
+	}
+
+	return ""
+}
+
+// Notes will iterate all the notes fetchers to embed the upstream release notes of dependencies.
+func (l Linker) Notes(dep changelog.Dependency) []changelog.ReleaseNote {
+	for _, fetcher := range l.NotesFetchers {
+		notes := fetcher.Notes(dep)
+		if len(notes) > 0 {
+			return notes
+		}
+	}
+
+	return nil
+}
@@ -1,7 +1,10 @@
 package linker_test
 
 import (
+	"fmt"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/Masterminds/semver"
 	"github.com/newrelic/release-toolkit/src/changelog"
@@ -72,3 +75,142 @@ func TestLinker_Link(t *testing.T) {
 		})
 	}
 }
+
+func TestLinker_Link_Compare(t *testing.T) {
+	t.Parallel()
+
+	dictionary := map[string]string{
+		"newrelic-infrastructure": "https://github.com/newrelic/nri-kubernetes/releases/tag/newrelic-infrastructure-{{.To.Original}}",
+	}
+
+	link := linker.New(mapper.Dictionary{Changelogs: dictionary}, mapper.Github{})
+	link.Comparers = []linker.Comparer{mapper.NewForgeCompare()}
+
+	cl := &changelog.Changelog{
+		Dependencies: []changelog.Dependency{
+			{Name: "newrelic-infrastructure", From: semver.MustParse("3.0.0"), To: semver.MustParse("3.1.0")},
+			{Name: "github.com/spf13/viper", From: semver.MustParse("v2.2.2"), To: semver.MustParse("v2.2.3"), Changelog: "https://www.newrelic.com"},
+			{Name: "github.com/spf13/cobra", From: semver.MustParse("v1.0.0"), To: semver.MustParse("v1.1.0"), Compare: "https://www.newrelic.com"},
+			{Name: "github.com/spf13/afero", To: semver.MustParse("v1.9.0")},
+		},
+	}
+
+	err := link.Link(cl)
+	if err != nil {
+		t.Fatalf("Err linking: %v", err)
+	}
+
+	expected := map[string]string{
+		"newrelic-infrastructure": "https://github.com/newrelic/nri-kubernetes/compare/newrelic-infrastructure-3.0.0...newrelic-infrastructure-3.1.0",
+		"github.com/spf13/viper":  "https://github.com/spf13/viper/compare/v2.2.2...v2.2.3",
+		"github.com/spf13/cobra":  "https://www.newrelic.com",
+		"github.com/spf13/afero":  "",
+	}
+
+	for _, dep := range cl.Dependencies {
+		if dep.Compare != expected[dep.Name] {
+			t.Fatalf("Dependency %s compare link not matching: %s != %s", dep.Name, dep.Compare, expected[dep.Name])
+		}
+	}
+}
+
+// slowMapper links every dependency after a delay, recording the maximum number of concurrent calls.
+type slowMapper struct {
+	mtx     sync.Mutex
+	current int
+	max     int
+}
+
+func (s *slowMapper) Map(dep changelog.Dependency) string {
+	s.mtx.Lock()
+	s.current++
+	if s.current > s.max {
+		s.max = s.current
+	}
+	s.mtx.Unlock()
+
+	time.Sleep(10 * time.Millisecond)
+
+	s.mtx.Lock()
+	s.current--
+	s.mtx.Unlock()
+
+	return "https://example.com/" + dep.Name
+}
+
+func TestLinker_Link_Concurrency(t *testing.T) {
+	t.Parallel()
+
+	cl := &changelog.Changelog{}
+	for i := 0; i < 20; i++ {
+		cl.Dependencies = append(cl.Dependencies, changelog.Dependency{Name: fmt.Sprintf("dep-%d", i), To: semver.MustParse("v1.0.0")})
+	}
+
+	mapper := &slowMapper{}
+	link := linker.New(mapper)
+	link.Concurrency = 4
+
+	err := link.Link(cl)
+	if err != nil {
+		t.Fatalf("Err linking: %v", err)
+	}
+
+	if mapper.max < 2 || mapper.max > link.Concurrency {
+		t.Fatalf("Expected between 2 and %d concurrent mappings, got %d", link.Concurrency, mapper.max)
+	}
+
+	for i, dep := range cl.Dependencies {
+		if expected := fmt.Sprintf("https://example.com/dep-%d", i); dep.Changelog != expected {
+			t.Fatalf("Dependency %s changelog not matching: %s != %s", dep.Name, dep.Changelog, expected)
+		}
+	}
+}
+
+// changelogNotes returns a release note holding the changelog link of the dependency, if it has one.
+type changelogNotes struct{}
+
+func (changelogNotes) Notes(dep changelog.Dependency) []changelog.ReleaseNote {
+	if dep.Changelog == "" {
+		return nil
+	}
+
+	return []changelog.ReleaseNote{{Version: dep.To.Original(), Notes: dep.Changelog}}
+}
+
+func TestLinker_Link_Notes(t *testing.T) {
+	t.Parallel()
+
+	link := linker.New(mapper.Github{})
+	link.NotesFetchers = []linker.NotesFetcher{changelogNotes{}}
+
+	existing := []changelog.ReleaseNote{{Version: "v1.1.0", Notes: "Existing"}}
+	cl := &changelog.Changelog{
+		Dependencies: []changelog.Dependency{
+			{Name: "github.com/spf13/viper", From: semver.MustParse("v2.2.2"), To: semver.MustParse("v2.2.3")},
+			{Name: "github.com/spf13/cobra", From: semver.MustParse("v1.0.0"), To: semver.MustParse("v1.1.0"), ReleaseNotes: existing},
+			{Name: "github.com/spf13/afero", To: semver.MustParse("v1.9.0")},
+			{Name: "unknown", From: semver.MustParse("v1.0.0"), To: semver.MustParse("v1.9.0")},
+		},
+	}
+
+	err := link.Link(cl)
+	if err != nil {
+		t.Fatalf("Err linking: %v", err)
+	}
+
+	expected := map[string]string{
+		"github.com/spf13/viper": "https://github.com/spf13/viper/releases/tag/v2.2.3",
+		"github.com/spf13/cobra": "Existing",
+	}
+
+	for _, dep := range cl.Dependencies {
+		notes := ""
+		if len(dep.ReleaseNotes) > 0 {
+			notes = dep.ReleaseNotes[0].Notes
+		}
+
+		if notes != expected[dep.Name] {
+			t.Fatalf("Dependency %s release notes not matching: %s != %s", dep.Name, notes, expected[dep.Name])
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package mapper
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/newrelic/release-toolkit/src/changelog"
+	log "github.com/sirupsen/logrus"
+)
+
+// DefaultArtifactHubURL is the URL of Artifact Hub.
+const DefaultArtifactHubURL = "https://artifacthub.io"
+
+// helmNameRegex matches the names of Helm charts, optionally prefixed by the name of their repository in Artifact Hub.
+var helmNameRegex = regexp.MustCompile(`^([a-z0-9][a-z0-9._-]*/)?[a-z0-9][a-z0-9._-]*$`)
+
+// ArtifactHub links Helm charts to the release of their new version in the repository listed in their links, as
+// served by the Artifact Hub instance at its base URL.
+// Dependencies named `repository/chart` are looked up in that Artifact Hub repository. Dependencies named after the
+// chart alone are looked up among all repositories, and only linked if a single chart has that name.
+type ArtifactHub struct {
+	registry
+}
+
+// NewArtifactHub returns an ArtifactHub that queries the Artifact Hub at baseURL, which is DefaultArtifactHubURL if
+// empty.
+func NewArtifactHub(baseURL string, opts ...RegistryOptionFunc) ArtifactHub {
+	if baseURL == "" {
+		baseURL = DefaultArtifactHubURL
+	}
+
+	return ArtifactHub{registry: newRegistry("Artifact Hub", baseURL, opts...)}
+}
+
+// artifactHubPackage holds the fields of a package returned by the Artifact Hub API that are relevant to find its
+// source repository.
+type artifactHubPackage struct {
+	Name       string `json:"name"`
+	HomeURL    string `json:"home_url"`
+	Repository struct {
+		Name string `json:"name"`
+	} `json:"repository"`
+	Links []struct {
+		Name string `json:"name"`
+		URL  string `json:"url"`
+	} `json:"links"`
+}
+
+// artifactHubKindHelm is the kind of Helm chart packages in the Artifact Hub API.
+const artifactHubKindHelm = "0"
+
+func (a ArtifactHub) Map(dep changelog.Dependency) string {
+	if !helmNameRegex.MatchString(dep.Name) {
+		log.Debugf("Artifact Hub mapper: Dependency %q is not a Helm chart name.", dep.Name)
+		return ""
+	}
+
+	return a.link(dep, func() (string, string, error) {
+		return a.resolve(dep)
+	})
+}
+
+func (a ArtifactHub) resolve(dep changelog.Dependency) (string, string, error) {
+	repo, chart, found := strings.Cut(dep.Name, "/")
+	if !found {
+		var err error
+		chart = dep.Name
+		repo, err = a.chartRepository(chart)
+		if err != nil {
+			return "", "", err
+		}
+	}
+
+	pkg := artifactHubPackage{}
+	version := url.PathEscape(strings.TrimPrefix(dep.To.Original(), "v"))
+	err := a.getJSON(fmt.Sprintf("%s/api/v1/packages/helm/%s/%s/%s", a.baseURL, repo, chart, version), nil, &pkg)
+	if err != nil {
+		return "", "", err
+	}
+
+	for _, link := range pkg.Links {
+		if strings.Contains(strings.ToLower(link.Name), "source") {
+			if _, _, found := githubRepo(link.URL); found {
+				return link.URL, "", nil
+			}
+		}
+	}
+
+	for _, link := range pkg.Links {
+		if _, _, found := githubRepo(link.URL); found {
+			return link.URL, "", nil
+		}
+	}
+
+	return pkg.HomeURL, "", nil
+}
+
+// chartRepository returns the Artifact Hub repository of the only Helm chart named chart.
+func (a ArtifactHub) chartRepository(chart string) (string, error) {
+	query := url.Values{}
+	query.Set("kind", artifactHubKindHelm)
+	query.Set("ts_query_web", chart)
+	query.Set("limit", "60")
+
+	results := struct {
+		Packages []artifactHubPackage `json:"packages"`
+	}{}
+	err := a.getJSON(fmt.Sprintf("%s/api/v1/packages/search?%s", a.baseURL, query.Encode()), nil, &results)
+	if err != nil {
+		return "", err
+	}
+
+	var repos []string
+	for _, pkg := range results.Packages {
+		if pkg.Name == chart {
+			repos = append(repos, pkg.Repository.Name)
+		}
+	}
+
+	switch len(repos) {
+	case 0:
+		return "", fmt.Errorf("%w: no chart named %q", ErrRegistryNotFound, chart)
+	case 1:
+		return repos[0], nil
+	default:
+		return "", fmt.Errorf("%w: chart name %q is ambiguous, found in repositories %v", ErrRegistryNotFound, chart, repos)
+	}
+}
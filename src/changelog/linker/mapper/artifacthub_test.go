@@ -0,0 +1,48 @@
+package mapper_test
+
+import (
+	"testing"
+
+	"github.com/Masterminds/semver"
+	"github.com/newrelic/release-toolkit/src/changelog"
+	"github.com/newrelic/release-toolkit/src/changelog/linker/mapper"
+)
+
+func TestArtifactHub_Map(t *testing.T) {
+	t.Parallel()
+
+	server := registryServer(t, map[string]string{
+		"/api/v1/packages/helm/newrelic/nri-bundle/5.0.1": `{"name": "nri-bundle", "home_url": "https://docs.newrelic.com", "links": [
+			{"name": "Chart", "url": "https://github.com/newrelic/helm-charts/tree/master/charts/nri-bundle"},
+			{"name": "Source", "url": "https://github.com/newrelic/nri-bundle"}
+		]}`,
+		"/api/v1/packages/helm/bitnami/redis/17.3.11": `{"name": "redis", "home_url": "https://github.com/bitnami/charts/tree/main/bitnami/redis"}`,
+		"/api/v1/packages/helm/other/no-source/1.0.0": `{"name": "no-source", "home_url": "https://example.com"}`,
+		"/api/v1/packages/search": `{"packages": [
+			{"name": "nri-bundle", "repository": {"name": "newrelic"}},
+			{"name": "nri-bundle-extras", "repository": {"name": "someone"}},
+			{"name": "redis", "repository": {"name": "bitnami"}},
+			{"name": "redis", "repository": {"name": "someone"}}
+		]}`,
+	})
+	artifactHub := mapper.NewArtifactHub(server.URL)
+
+	for _, tc := range []struct {
+		name     string
+		version  string
+		expected string
+	}{
+		{name: "newrelic/nri-bundle", version: "5.0.1", expected: "https://github.com/newrelic/nri-bundle/releases/tag/5.0.1"},
+		{name: "nri-bundle", version: "5.0.1", expected: "https://github.com/newrelic/nri-bundle/releases/tag/5.0.1"},
+		{name: "bitnami/redis", version: "17.3.11", expected: "https://github.com/bitnami/charts/releases/tag/17.3.11"},
+		{name: "redis", version: "17.3.11", expected: ""},
+		{name: "other/no-source", version: "1.0.0", expected: ""},
+		{name: "newrelic/unknown", version: "1.0.0", expected: ""},
+		{name: "github.com/not/helm", version: "1.0.0", expected: ""},
+	} {
+		dep := changelog.Dependency{Name: tc.name, To: semver.MustParse(tc.version)}
+		if actual := artifactHub.Map(dep); actual != tc.expected {
+			t.Errorf("Expected %q to be linked to %q, got %q", tc.name, tc.expected, actual)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package mapper
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/newrelic/release-toolkit/src/changelog"
+	log "github.com/sirupsen/logrus"
+)
+
+// Forge is the software a git host runs, which determines the format of its links.
+type Forge string
+
+const (
+	ForgeGithub Forge = "github"
+	ForgeGitlab Forge = "gitlab"
+	ForgeGitea  Forge = "gitea"
+)
+
+var ErrUnknownForge = errors.New("unknown forge")
+
+// ParseForge returns the Forge named s.
+func ParseForge(s string) (Forge, error) {
+	switch forge := Forge(strings.ToLower(s)); forge {
+	case ForgeGithub, ForgeGitlab, ForgeGitea:
+		return forge, nil
+	default:
+		return "", fmt.Errorf("%w %q, supported forges are %s, %s and %s", ErrUnknownForge, s, ForgeGithub, ForgeGitlab, ForgeGitea)
+	}
+}
+
+// ForgeCompare links dependencies hosted on GitHub, GitLab or Gitea to the comparison between the tags of their From
+// and To versions, e.g. `https://github.com/org/repo/compare/v1.2.0...v1.3.0`.
+// The repository is taken from the changelog link of the dependency if it points to a known host, so dependencies
+// linked from a dictionary or a registry are supported, or from its name otherwise, e.g. `github.com/org/repo`. In the
+// first case, tags are assumed to be named as the one in the changelog link, so prefixes such as `chart-name-` in
+// `chart-name-1.3.0` are kept.
+type ForgeCompare struct {
+	hosts map[string]Forge
+}
+
+// ForgeCompareOptionFunc configures a ForgeCompare.
+type ForgeCompareOptionFunc func(f *ForgeCompare)
+
+// ForgeHost adds a host running the given forge, such as a self-hosted GitLab or Gitea instance.
+func ForgeHost(host string, forge Forge) ForgeCompareOptionFunc {
+	return func(f *ForgeCompare) {
+		f.hosts[strings.ToLower(host)] = forge
+	}
+}
+
+// NewForgeCompare returns a ForgeCompare that knows about github.com, gitlab.com, gitea.com and codeberg.org, and the
+// hosts added with ForgeHost.
+func NewForgeCompare(opts ...ForgeCompareOptionFunc) ForgeCompare {
+	f := ForgeCompare{
+		hosts: map[string]Forge{
+			"github.com":   ForgeGithub,
+			"gitlab.com":   ForgeGitlab,
+			"gitea.com":    ForgeGitea,
+			"codeberg.org": ForgeGitea,
+		},
+	}
+
+	for _, opt := range opts {
+		opt(&f)
+	}
+
+	return f
+}
+
+func (f ForgeCompare) Compare(dep changelog.Dependency) string {
+	if dep.From == nil || dep.To == nil || dep.From.Original() == "" || dep.To.Original() == "" {
+		log.Debugf("Forge compare: Dependency %q does not have both versions.", dep.Name)
+		return ""
+	}
+
+	host, repo, tag, found := f.fromChangelog(dep.Changelog)
+	if !found {
+		host, repo, found = f.fromName(dep.Name)
+	}
+	if !found {
+		log.Debugf("Forge compare: Dependency %q is not hosted on a known forge.", dep.Name)
+		return ""
+	}
+
+	fromTag, toTag := dep.From.Original(), dep.To.Original()
+
+	// Tags are named as the one in the changelog link, replacing its version.
+	toVersion := strings.TrimPrefix(toTag, "v")
+	if tag != "" && strings.HasSuffix(tag, toVersion) {
+		prefix := strings.TrimSuffix(tag, toVersion)
+		fromTag, toTag = prefix+strings.TrimPrefix(fromTag, "v"), tag
+	}
+
+	if f.hosts[host] == ForgeGitlab {
+		return fmt.Sprintf("https://%s/%s/-/compare/%s...%s", host, repo, fromTag, toTag)
+	}
+
+	return fmt.Sprintf("https://%s/%s/compare/%s...%s", host, repo, fromTag, toTag)
+}
+
+// fromChangelog returns the host, repository and tag a link to a release in a known forge points to.
+func (f ForgeCompare) fromChangelog(link string) (string, string, string, bool) {
+	if link == "" {
+		return "", "", "", false
+	}
+
+	u, err := url.Parse(link)
+	if err != nil {
+		return "", "", "", false
+	}
+
+	host := strings.ToLower(u.Host)
+	forge, known := f.hosts[host]
+	if !known {
+		return "", "", "", false
+	}
+
+	path := strings.Trim(u.Path, "/")
+
+	// GitLab repositories can be nested in groups, and their pages are separated from the repository path by `/-/`.
+	if forge == ForgeGitlab {
+		repo, page, found := strings.Cut(path, "/-/")
+		if !found {
+			return "", "", "", false
+		}
+
+		return host, repo, strings.TrimPrefix(page, "releases/"), true
+	}
+
+	parts := strings.SplitN(path, "/", 3)
+	if len(parts) < 3 || !strings.HasPrefix(parts[2], "releases/tag/") {
+		return "", "", "", false
+	}
+
+	return host, parts[0] + "/" + parts[1], strings.TrimPrefix(parts[2], "releases/tag/"), true
+}
+
+// fromName returns the host and repository of a dependency named `host/owner/repo`, as Go modules are.
+func (f ForgeCompare) fromName(name string) (string, string, bool) {
+	parts := strings.Split(name, "/")
+	if len(parts) < 3 {
+		return "", "", false
+	}
+
+	host := strings.ToLower(parts[0])
+	if _, known := f.hosts[host]; !known {
+		return "", "", false
+	}
+
+	return host, parts[1] + "/" + parts[2], true
+}
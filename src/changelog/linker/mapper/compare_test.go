@@ -0,0 +1,117 @@
+package mapper_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Masterminds/semver"
+	"github.com/newrelic/release-toolkit/src/changelog"
+	"github.com/newrelic/release-toolkit/src/changelog/linker/mapper"
+)
+
+//nolint:funlen
+func TestForgeCompare_Compare(t *testing.T) {
+	t.Parallel()
+
+	compare := mapper.NewForgeCompare(
+		mapper.ForgeHost("git.example.com", mapper.ForgeGitea),
+		mapper.ForgeHost("GitLab.Example.com", mapper.ForgeGitlab),
+	)
+
+	for _, tc := range []struct {
+		name       string
+		dependency changelog.Dependency
+		expected   string
+	}{
+		{
+			name:       "Github_Name",
+			dependency: changelog.Dependency{Name: "github.com/spf13/viper/v2", From: semver.MustParse("v1.11.0"), To: semver.MustParse("v1.12.0")},
+			expected:   "https://github.com/spf13/viper/compare/v1.11.0...v1.12.0",
+		},
+		{
+			name:       "Gitlab_Name",
+			dependency: changelog.Dependency{Name: "gitlab.com/org/repo", From: semver.MustParse("1.0.0"), To: semver.MustParse("1.1.0")},
+			expected:   "https://gitlab.com/org/repo/-/compare/1.0.0...1.1.0",
+		},
+		{
+			name:       "Codeberg_Name",
+			dependency: changelog.Dependency{Name: "codeberg.org/org/repo", From: semver.MustParse("v1.0.0"), To: semver.MustParse("v1.1.0")},
+			expected:   "https://codeberg.org/org/repo/compare/v1.0.0...v1.1.0",
+		},
+		{
+			name:       "Custom_Gitea_Name",
+			dependency: changelog.Dependency{Name: "git.example.com/org/repo", From: semver.MustParse("v1.0.0"), To: semver.MustParse("v1.1.0")},
+			expected:   "https://git.example.com/org/repo/compare/v1.0.0...v1.1.0",
+		},
+		{
+			name: "Prefixed_Tag_In_Changelog",
+			dependency: changelog.Dependency{
+				Name:      "newrelic-infrastructure",
+				From:      semver.MustParse("3.0.0"),
+				To:        semver.MustParse("3.1.0"),
+				Changelog: "https://github.com/newrelic/nri-kubernetes/releases/tag/newrelic-infrastructure-3.1.0",
+			},
+			expected: "https://github.com/newrelic/nri-kubernetes/compare/newrelic-infrastructure-3.0.0...newrelic-infrastructure-3.1.0",
+		},
+		{
+			name: "Leading_V_In_Changelog_Only",
+			dependency: changelog.Dependency{
+				Name:      "left-pad",
+				From:      semver.MustParse("1.2.0"),
+				To:        semver.MustParse("1.3.0"),
+				Changelog: "https://github.com/left-pad/left-pad/releases/tag/v1.3.0",
+			},
+			expected: "https://github.com/left-pad/left-pad/compare/v1.2.0...v1.3.0",
+		},
+		{
+			name: "Nested_Gitlab_Repository_In_Changelog",
+			dependency: changelog.Dependency{
+				Name:      "some-chart",
+				From:      semver.MustParse("v0.9.0"),
+				To:        semver.MustParse("v1.0.0"),
+				Changelog: "https://gitlab.example.com/group/subgroup/repo/-/releases/v1.0.0",
+			},
+			expected: "https://gitlab.example.com/group/subgroup/repo/-/compare/v0.9.0...v1.0.0",
+		},
+		{
+			name: "Unknown_Changelog_Host_Falls_Back_To_Name",
+			dependency: changelog.Dependency{
+				Name:      "github.com/org/repo",
+				From:      semver.MustParse("v1.0.0"),
+				To:        semver.MustParse("v1.1.0"),
+				Changelog: "https://example.com/changelog",
+			},
+			expected: "https://github.com/org/repo/compare/v1.0.0...v1.1.0",
+		},
+		{
+			name:       "Unknown_Host",
+			dependency: changelog.Dependency{Name: "example.com/org/repo", From: semver.MustParse("v1.0.0"), To: semver.MustParse("v1.1.0")},
+			expected:   "",
+		},
+		{
+			name:       "No_From_Version",
+			dependency: changelog.Dependency{Name: "github.com/org/repo", To: semver.MustParse("v1.1.0")},
+			expected:   "",
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			if actual := compare.Compare(tc.dependency); actual != tc.expected {
+				t.Fatalf("Compare link not matching: %s != %s", actual, tc.expected)
+			}
+		})
+	}
+}
+
+func TestParseForge(t *testing.T) {
+	t.Parallel()
+
+	if forge, err := mapper.ParseForge("GitLab"); err != nil || forge != mapper.ForgeGitlab {
+		t.Fatalf("Expected %q, got %q, %v", mapper.ForgeGitlab, forge, err)
+	}
+
+	if _, err := mapper.ParseForge("bitbucket"); !errors.Is(err, mapper.ErrUnknownForge) {
+		t.Fatalf("Expected %v, got %v", mapper.ErrUnknownForge, err)
+	}
+}
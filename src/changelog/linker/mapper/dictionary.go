@@ -4,6 +4,9 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 	"text/template"
 
@@ -12,12 +15,59 @@ import (
 	"gopkg.in/yaml.v3"
 )
 
-var ErrNilTemplateField = errors.New("required link template field is nil")
+var (
+	ErrNilTemplateField       = errors.New("required link template field is nil")
+	ErrInvalidDictionaryEntry = errors.New("invalid dictionary entry")
+	ErrInvalidDictionary      = errors.New("dictionary must be a map or a list of entries")
+)
 
 // Dictionary stores a mapping between dependency names and templates to obtain a changelog link.
-// Templates have access to fields of the changelog.Dependency object.
+// Templates have access to fields of the changelog.Dependency object, and to the groups captured by the matching
+// entry in Match.
+//
+// Dependencies are matched deterministically, in this order:
+//  1. Entries whose name is the name of the dependency, with the ones in Entries taking precedence over Changelogs.
+//  2. Regex and glob entries in Entries, in order.
+//  3. Keys in Changelogs contained in the name of the dependency, longest first and alphabetically for keys of the same
+//     length.
 type Dictionary struct {
-	Changelogs map[string]string `yaml:"dictionary"`
+	// Changelogs maps dependency names to templates. It holds dictionaries written as a map.
+	Changelogs map[string]string
+	// Entries holds dictionaries written as a list, whose entries can match names, regexes or globs.
+	Entries []DictionaryEntry
+}
+
+// DictionaryEntry is an entry of a dictionary written as a list, which matches dependencies by exactly one of Name,
+// Regex or Glob.
+type DictionaryEntry struct {
+	// Name matches dependencies with this exact name.
+	Name string `yaml:"name,omitempty"`
+	// Regex matches dependencies whose name matches this regular expression, which is not anchored. Named groups are
+	// available in the template by name, e.g. {{.Match.repo}}, and all groups by position, e.g. {{index .Match "1"}}.
+	Regex string `yaml:"regex,omitempty"`
+	// Glob matches dependencies whose whole name matches this glob, where `*` and `?` match any characters but `/`,
+	// and `**` matches any characters. Wildcards are available in the template by position, e.g. {{index .Match "1"}}.
+	Glob string `yaml:"glob,omitempty"`
+	// Changelog is the template for the changelog link.
+	Changelog string `yaml:"changelog"`
+
+	pattern *regexp.Regexp
+}
+
+// DictionaryMatch describes the dictionary entry that matched a dependency.
+type DictionaryMatch struct {
+	// Entry describes the entry, e.g. `regex "^foo-(.+)$"`.
+	Entry string
+	// Template is the template of the entry.
+	Template string
+	// Captures holds the groups captured by the entry.
+	Captures map[string]string
+}
+
+// dictionaryTemplateData is what dictionary templates are rendered with.
+type dictionaryTemplateData struct {
+	changelog.Dependency
+	Match map[string]string
 }
 
 func NewDictionary(r io.Reader) (Dictionary, error) {
@@ -28,7 +78,11 @@ func NewDictionary(r io.Reader) (Dictionary, error) {
 		return d, fmt.Errorf("reading dictionary from source: %w", err)
 	}
 
-	err = yaml.Unmarshal(buf, &d)
+	file := struct {
+		Dictionary *Dictionary `yaml:"dictionary"`
+	}{Dictionary: &d}
+
+	err = yaml.Unmarshal(buf, &file)
 	if err != nil {
 		return d, fmt.Errorf("unmarshaling yaml: %w", err)
 	}
@@ -36,49 +90,153 @@ func NewDictionary(r io.Reader) (Dictionary, error) {
 	return d, nil
 }
 
+// UnmarshalYAML decodes a dictionary written either as a map from names to templates or as a list of entries.
+func (d *Dictionary) UnmarshalYAML(value *yaml.Node) error {
+	switch value.Kind {
+	case yaml.MappingNode:
+		return value.Decode(&d.Changelogs) //nolint:wrapcheck
+	case yaml.SequenceNode:
+		if err := value.Decode(&d.Entries); err != nil {
+			return fmt.Errorf("decoding dictionary entries: %w", err)
+		}
+
+		for i := range d.Entries {
+			if err := d.Entries[i].compile(); err != nil {
+				return fmt.Errorf("entry %d: %w", i+1, err)
+			}
+		}
+
+		return nil
+	default:
+		return fmt.Errorf("%w, found %q at line %d", ErrInvalidDictionary, value.Tag, value.Line)
+	}
+}
+
+// MarshalYAML encodes the dictionary as a map if it has no Entries, or as a list with Changelogs as name entries
+// after Entries otherwise.
+func (d Dictionary) MarshalYAML() (interface{}, error) {
+	if len(d.Entries) == 0 {
+		return d.Changelogs, nil
+	}
+
+	entries := append([]DictionaryEntry{}, d.Entries...)
+	for _, name := range sortedKeys(d.Changelogs) {
+		entries = append(entries, DictionaryEntry{Name: name, Changelog: d.Changelogs[name]})
+	}
+
+	return entries, nil
+}
+
+// Merge returns a dictionary with the entries of d and fallback, where entries of d take precedence over the ones
+// of fallback.
+func (d Dictionary) Merge(fallback Dictionary) Dictionary {
+	names := map[string]bool{}
+	for name := range d.Changelogs {
+		names[name] = true
+	}
+	for _, entry := range d.Entries {
+		if entry.Name != "" {
+			names[entry.Name] = true
+		}
+	}
+
+	merged := Dictionary{Changelogs: map[string]string{}}
+	for name, tpl := range fallback.Changelogs {
+		if !names[name] {
+			merged.Changelogs[name] = tpl
+		}
+	}
+	for name, tpl := range d.Changelogs {
+		merged.Changelogs[name] = tpl
+	}
+
+	merged.Entries = append(merged.Entries, d.Entries...)
+	for _, entry := range fallback.Entries {
+		if !names[entry.Name] {
+			merged.Entries = append(merged.Entries, entry)
+		}
+	}
+
+	return merged
+}
+
+// Empty returns whether the dictionary has no entries.
+func (d Dictionary) Empty() bool {
+	return len(d.Changelogs) == 0 && len(d.Entries) == 0
+}
+
 func (d Dictionary) Map(dep changelog.Dependency) string {
-	if len(d.Changelogs) == 0 {
+	match, found := d.Match(dep)
+	if !found {
 		return ""
 	}
 
+	link, err := d.Render(dep, match)
+	if err != nil {
+		log.Errorf("Error mapping changelog for %q from dictionary: %v", dep.Name, err)
+		return ""
+	}
+
+	return link
+}
+
+// Match returns the entry that matches dep, following the precedence rules of Dictionary.
+func (d Dictionary) Match(dep changelog.Dependency) (DictionaryMatch, bool) {
+	for _, entry := range d.Entries {
+		if entry.Name != "" && entry.Name == dep.Name {
+			return DictionaryMatch{Entry: entry.String(), Template: entry.Changelog}, true
+		}
+	}
+
 	if tpl := d.Changelogs[dep.Name]; tpl != "" {
-		link, err := d.template(dep, tpl)
-		if err != nil {
-			log.Errorf("Error mapping changelog for %q from dictionary: %v", dep.Name, err)
-			return ""
+		return DictionaryMatch{Entry: fmt.Sprintf("name %q", dep.Name), Template: tpl}, true
+	}
+
+	for _, entry := range d.Entries {
+		if entry.pattern == nil {
+			continue
 		}
 
-		return link
+		if captures, found := entry.match(dep.Name); found {
+			log.Infof("Linking changelog for %q from dictionary entry %s", dep.Name, entry.String())
+			return DictionaryMatch{Entry: entry.String(), Template: entry.Changelog, Captures: captures}, true
+		}
 	}
 
 	log.Debugf("Dependency %q did not match any entry in the dictionary, attempting a partial match", dep.Name)
-	for name, tpl := range d.Changelogs {
+
+	keys := sortedKeys(d.Changelogs)
+	sort.SliceStable(keys, func(i, j int) bool {
+		return len(keys[i]) > len(keys[j])
+	})
+
+	for _, name := range keys {
 		if !strings.Contains(dep.Name, name) {
 			continue
 		}
 
 		log.Infof("Linking changelog for %q from dictionary entry %q", dep.Name, name)
 
-		link, err := d.template(dep, tpl)
-		if err != nil {
-			log.Errorf("Error mapping changelog for %q from dictionary: %v", dep.Name, err)
-			return ""
-		}
-
-		return link
+		return DictionaryMatch{Entry: fmt.Sprintf("partial name %q", name), Template: d.Changelogs[name]}, true
 	}
 
-	return ""
+	return DictionaryMatch{}, false
 }
 
-func (d Dictionary) template(dep changelog.Dependency, tplString string) (string, error) {
-	tpl, err := template.New("changelog").Parse(tplString)
+// Render renders the template of match for dep.
+func (d Dictionary) Render(dep changelog.Dependency, match DictionaryMatch) (string, error) {
+	tpl, err := template.New("changelog").Option("missingkey=error").Parse(match.Template)
 	if err != nil {
 		return "", fmt.Errorf("parsing template: %w", err)
 	}
 
+	captures := match.Captures
+	if captures == nil {
+		captures = map[string]string{}
+	}
+
 	linkTemplate := &strings.Builder{}
-	err = tpl.Execute(linkTemplate, dep)
+	err = tpl.Execute(linkTemplate, dictionaryTemplateData{Dependency: dep, Match: captures})
 	if err != nil {
 		return "", fmt.Errorf("executing template: %w", err)
 	}
@@ -90,3 +248,96 @@ func (d Dictionary) template(dep changelog.Dependency, tplString string) (string
 
 	return link, nil
 }
+
+// String describes the entry by what it matches.
+func (e DictionaryEntry) String() string {
+	switch {
+	case e.Regex != "":
+		return fmt.Sprintf("regex %q", e.Regex)
+	case e.Glob != "":
+		return fmt.Sprintf("glob %q", e.Glob)
+	default:
+		return fmt.Sprintf("name %q", e.Name)
+	}
+}
+
+// compile validates the entry and compiles its regex or glob.
+func (e *DictionaryEntry) compile() error {
+	set := 0
+	for _, field := range []string{e.Name, e.Regex, e.Glob} {
+		if field != "" {
+			set++
+		}
+	}
+	if set != 1 {
+		return fmt.Errorf("%w: exactly one of name, regex or glob must be set", ErrInvalidDictionaryEntry)
+	}
+	if e.Changelog == "" {
+		return fmt.Errorf("%w: %s has no changelog template", ErrInvalidDictionaryEntry, e.String())
+	}
+
+	var err error
+	switch {
+	case e.Regex != "":
+		e.pattern, err = regexp.Compile(e.Regex)
+	case e.Glob != "":
+		e.pattern, err = regexp.Compile(globToRegex(e.Glob))
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %s: %v", ErrInvalidDictionaryEntry, e.String(), err)
+	}
+
+	return nil
+}
+
+// match returns the groups captured by the pattern of the entry in name, keyed by position and, if named, by name.
+func (e DictionaryEntry) match(name string) (map[string]string, bool) {
+	matches := e.pattern.FindStringSubmatch(name)
+	if matches == nil {
+		return nil, false
+	}
+
+	captures := map[string]string{}
+	for i, groupName := range e.pattern.SubexpNames() {
+		captures[strconv.Itoa(i)] = matches[i]
+		if groupName != "" {
+			captures[groupName] = matches[i]
+		}
+	}
+
+	return captures, true
+}
+
+// globToRegex returns an anchored regular expression equivalent to glob, capturing each wildcard.
+func globToRegex(glob string) string {
+	re := &strings.Builder{}
+	re.WriteString("^")
+
+	for i := 0; i < len(glob); i++ {
+		switch {
+		case strings.HasPrefix(glob[i:], "**"):
+			re.WriteString("(.*)")
+			i++
+		case glob[i] == '*':
+			re.WriteString("([^/]*)")
+		case glob[i] == '?':
+			re.WriteString("([^/])")
+		default:
+			re.WriteString(regexp.QuoteMeta(glob[i : i+1]))
+		}
+	}
+
+	re.WriteString("$")
+
+	return re.String()
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
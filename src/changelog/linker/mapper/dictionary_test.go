@@ -1,6 +1,7 @@
 package mapper_test
 
 import (
+	"errors"
 	"reflect"
 	"strings"
 	"testing"
@@ -8,6 +9,7 @@ import (
 	"github.com/Masterminds/semver"
 	"github.com/newrelic/release-toolkit/src/changelog"
 	"github.com/newrelic/release-toolkit/src/changelog/linker/mapper"
+	"gopkg.in/yaml.v3"
 )
 
 func TestDictionary_NewDictionary(t *testing.T) {
@@ -91,3 +93,117 @@ dictionary:
 		})
 	}
 }
+
+func TestDictionary_NewDictionary_Entries(t *testing.T) {
+	t.Parallel()
+
+	dic, err := mapper.NewDictionary(strings.NewReader(strings.TrimSpace(`
+dictionary:
+  - name: infrastructure-agent
+    changelog: "https://github.com/newrelic/infrastructure-agent/releases/tag/{{.To.Original}}"
+  - regex: '^newrelic/nri-(?P<integration>[^/]+)$'
+    changelog: "https://github.com/newrelic/nri-{{.Match.integration}}/releases/tag/v{{.To}}"
+  - glob: "newrelic/*"
+    changelog: "https://github.com/newrelic/{{index .Match \"1\"}}/releases/tag/{{.To.Original}}"
+	`)))
+	if err != nil {
+		t.Fatalf("Error creating dictionary: %v", err)
+	}
+
+	if len(dic.Entries) != 3 || len(dic.Changelogs) != 0 {
+		t.Fatalf("Expected 3 entries and no changelogs, got %d and %d", len(dic.Entries), len(dic.Changelogs))
+	}
+
+	for _, tc := range []struct {
+		input    string
+		expected error
+	}{
+		{input: `dictionary: [{changelog: "https://example.com"}]`, expected: mapper.ErrInvalidDictionaryEntry},
+		{input: `dictionary: [{name: foo, glob: "foo*", changelog: "https://example.com"}]`, expected: mapper.ErrInvalidDictionaryEntry},
+		{input: `dictionary: [{name: foo}]`, expected: mapper.ErrInvalidDictionaryEntry},
+		{input: `dictionary: [{regex: "foo(", changelog: "https://example.com"}]`, expected: mapper.ErrInvalidDictionaryEntry},
+		{input: `dictionary: "https://example.com"`, expected: mapper.ErrInvalidDictionary},
+	} {
+		if _, err := mapper.NewDictionary(strings.NewReader(tc.input)); !errors.Is(err, tc.expected) {
+			t.Errorf("Expected %v parsing %s, got %v", tc.expected, tc.input, err)
+		}
+	}
+}
+
+//nolint:funlen
+func TestDictionary_Map_Precedence(t *testing.T) {
+	t.Parallel()
+
+	dic, err := mapper.NewDictionary(strings.NewReader(strings.TrimSpace(`
+dictionary:
+  - glob: "newrelic/**"
+    changelog: "https://example.com/glob/{{index .Match \"1\"}}/{{.To}}"
+  - regex: '^newrelic/nri-(?P<integration>[^/]+)$'
+    changelog: "https://example.com/regex/{{.Match.integration}}/{{.To}}"
+  - name: newrelic/nri-kafka
+    changelog: "https://example.com/name/kafka/{{.To}}"
+  - regex: '^org/(?P<repo>.+)$'
+    changelog: "https://example.com/{{.Match.missing}}"
+	`)))
+	if err != nil {
+		t.Fatalf("Error creating dictionary: %v", err)
+	}
+
+	dic = dic.Merge(mapper.Dictionary{Changelogs: map[string]string{
+		"newrelic/nri-redis": "https://example.com/fallback/redis/{{.To}}",
+		"infra":              "https://example.com/partial/infra/{{.To}}",
+		"infrastructure":     "https://example.com/partial/infrastructure/{{.To}}",
+		"agent":              "https://example.com/partial/agent/{{.To}}",
+	}})
+
+	for _, tc := range []struct {
+		name     string
+		expected string
+		entry    string
+	}{
+		// Names take precedence over patterns.
+		{name: "newrelic/nri-kafka", expected: "https://example.com/name/kafka/1.2.3", entry: `name "newrelic/nri-kafka"`},
+		{name: "newrelic/nri-redis", expected: "https://example.com/fallback/redis/1.2.3", entry: `name "newrelic/nri-redis"`},
+		// Patterns are matched in order.
+		{name: "newrelic/nri-mysql", expected: "https://example.com/glob/nri-mysql/1.2.3", entry: `glob "newrelic/**"`},
+		{name: "newrelic/some/thing", expected: "https://example.com/glob/some/thing/1.2.3", entry: `glob "newrelic/**"`},
+		// Longest partial matches first, regardless of map order.
+		{name: "newrelic-infrastructure-agent", expected: "https://example.com/partial/infrastructure/1.2.3", entry: `partial name "infrastructure"`},
+		// Missing captures fail to render.
+		{name: "org/repo", expected: "", entry: `regex "^org/(?P<repo>.+)$"`},
+		{name: "unknown", expected: ""},
+	} {
+		dep := changelog.Dependency{Name: tc.name, To: semver.MustParse("1.2.3")}
+		if actual := dic.Map(dep); actual != tc.expected {
+			t.Errorf("Expected %q to be linked to %q, got %q", tc.name, tc.expected, actual)
+		}
+
+		match, _ := dic.Match(dep)
+		if match.Entry != tc.entry {
+			t.Errorf("Expected %q to match %s, got %s", tc.name, tc.entry, match.Entry)
+		}
+	}
+}
+
+func TestDictionary_Glob(t *testing.T) {
+	t.Parallel()
+
+	dic := mapper.Dictionary{}
+	err := yaml.Unmarshal([]byte(`[{glob: "github.com/*/nri-?ql", changelog: "{{index .Match \"1\"}} {{index .Match \"2\"}}"}]`), &dic)
+	if err != nil {
+		t.Fatalf("Error creating dictionary: %v", err)
+	}
+
+	for name, expected := range map[string]string{
+		"github.com/newrelic/nri-mysql":  "",
+		"github.com/newrelic/nri-mql":    "newrelic m",
+		"github.com/new/relic/nri-mql":   "",
+		"github.com/newrelic/nri-mql/v2": "",
+		"xgithub.com/newrelic/nri-mql":   "",
+		"github.com/newrelic.io/nri-mql": "newrelic.io m",
+	} {
+		if actual := dic.Map(changelog.Dependency{Name: name, To: semver.MustParse("1.0.0")}); actual != expected {
+			t.Errorf("Expected %q to be linked to %q, got %q", name, expected, actual)
+		}
+	}
+}
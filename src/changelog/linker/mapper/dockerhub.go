@@ -0,0 +1,186 @@
+package mapper
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/newrelic/release-toolkit/src/changelog"
+	log "github.com/sirupsen/logrus"
+)
+
+// DefaultDockerRegistryURL is the URL of the Docker Hub registry.
+const DefaultDockerRegistryURL = "https://registry-1.docker.io"
+
+// ociSourceLabel is the label, or annotation, OCI images use to point to their source repository.
+const ociSourceLabel = "org.opencontainers.image.source"
+
+// ociManifestTypes are the media types of image manifests and indexes accepted from the registry.
+//
+//nolint:gochecknoglobals
+var ociManifestTypes = strings.Join([]string{
+	"application/vnd.oci.image.index.v1+json",
+	"application/vnd.oci.image.manifest.v1+json",
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+}, ", ")
+
+var (
+	// dockerNameRegex matches the names of images in Docker Hub, optionally prefixed by their namespace.
+	dockerNameRegex = regexp.MustCompile(`^[a-z0-9]+(?:[._-][a-z0-9]+)*(?:/[a-z0-9]+(?:[._-][a-z0-9]+)*)?$`)
+	// challengeParamRegex matches the parameters of a WWW-Authenticate challenge.
+	challengeParamRegex = regexp.MustCompile(`(\w+)="([^"]*)"`)
+)
+
+// DockerHub links Docker images to the release of their new version in the repository their
+// `org.opencontainers.image.source` label or annotation points to, as served by the registry at its base URL.
+// Images without a namespace are looked up as official images, in `library`.
+type DockerHub struct {
+	registry
+}
+
+// NewDockerHub returns a DockerHub that queries the registry at baseURL, which is DefaultDockerRegistryURL if empty.
+func NewDockerHub(baseURL string, opts ...RegistryOptionFunc) DockerHub {
+	if baseURL == "" {
+		baseURL = DefaultDockerRegistryURL
+	}
+
+	return DockerHub{registry: newRegistry("Docker Hub", baseURL, opts...)}
+}
+
+// ociManifest holds the fields of an image manifest or index that are relevant to find the source of the image.
+type ociManifest struct {
+	Manifests []struct {
+		Digest   string `json:"digest"`
+		Platform *struct {
+			OS           string `json:"os"`
+			Architecture string `json:"architecture"`
+		} `json:"platform"`
+	} `json:"manifests"`
+	Config struct {
+		Digest string `json:"digest"`
+	} `json:"config"`
+	Annotations map[string]string `json:"annotations"`
+}
+
+func (d DockerHub) Map(dep changelog.Dependency) string {
+	image := strings.TrimPrefix(strings.TrimPrefix(dep.Name, "docker.io/"), "index.docker.io/")
+	if !dockerNameRegex.MatchString(image) {
+		log.Debugf("Docker Hub mapper: Dependency %q is not a Docker Hub image name.", dep.Name)
+		return ""
+	}
+
+	if !strings.Contains(image, "/") {
+		image = "library/" + image
+	}
+
+	return d.link(dep, func() (string, string, error) {
+		return d.resolve(image, dep.To.Original())
+	})
+}
+
+func (d DockerHub) resolve(image, tag string) (string, string, error) {
+	headers, err := d.authenticate(image)
+	if err != nil {
+		return "", "", err
+	}
+
+	manifest := ociManifest{}
+	headers["Accept"] = ociManifestTypes
+	err = d.getJSON(fmt.Sprintf("%s/v2/%s/manifests/%s", d.baseURL, image, url.PathEscape(tag)), headers, &manifest)
+	if err != nil {
+		return "", "", err
+	}
+
+	if source := manifest.Annotations[ociSourceLabel]; source != "" {
+		return source, "", nil
+	}
+
+	// Multi-platform images point to one manifest per platform, whose labels are assumed to be the same.
+	if len(manifest.Manifests) > 0 {
+		digest := manifest.Manifests[0].Digest
+		for _, m := range manifest.Manifests {
+			if m.Platform != nil && m.Platform.OS == "linux" && m.Platform.Architecture == "amd64" {
+				digest = m.Digest
+				break
+			}
+		}
+
+		manifest = ociManifest{}
+		err = d.getJSON(fmt.Sprintf("%s/v2/%s/manifests/%s", d.baseURL, image, digest), headers, &manifest)
+		if err != nil {
+			return "", "", err
+		}
+
+		if source := manifest.Annotations[ociSourceLabel]; source != "" {
+			return source, "", nil
+		}
+	}
+
+	if manifest.Config.Digest == "" {
+		return "", "", fmt.Errorf("%w: manifest of %s:%s has no config", ErrRegistryNotFound, image, tag)
+	}
+
+	config := struct {
+		Config struct {
+			Labels map[string]string `json:"Labels"`
+		} `json:"config"`
+	}{}
+	delete(headers, "Accept")
+	err = d.getJSON(fmt.Sprintf("%s/v2/%s/blobs/%s", d.baseURL, image, manifest.Config.Digest), headers, &config)
+	if err != nil {
+		return "", "", err
+	}
+
+	if source := config.Config.Labels[ociSourceLabel]; source != "" {
+		return source, "", nil
+	}
+
+	return "", "", fmt.Errorf("%w: %s:%s has no %s label", ErrRegistryNotFound, image, tag, ociSourceLabel)
+}
+
+// authenticate returns the headers needed to pull image from the registry, which include an anonymous token if the
+// registry requires one, as Docker Hub does.
+func (d DockerHub) authenticate(image string) (map[string]string, error) {
+	resp, err := d.do(d.baseURL+"/v2/", nil)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusUnauthorized {
+		return map[string]string{}, nil
+	}
+
+	challenge := resp.Header.Get("WWW-Authenticate")
+	if !strings.HasPrefix(strings.ToLower(challenge), "bearer ") {
+		return nil, fmt.Errorf("%w: unsupported authentication challenge %q", ErrRegistryStatus, challenge)
+	}
+
+	params := map[string]string{}
+	for _, match := range challengeParamRegex.FindAllStringSubmatch(challenge, -1) {
+		params[match[1]] = match[2]
+	}
+
+	query := url.Values{}
+	query.Set("scope", fmt.Sprintf("repository:%s:pull", image))
+	if service := params["service"]; service != "" {
+		query.Set("service", service)
+	}
+
+	token := struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}{}
+	if err := d.getJSON(params["realm"]+"?"+query.Encode(), nil, &token); err != nil {
+		return nil, fmt.Errorf("getting registry token: %w", err)
+	}
+
+	if token.Token == "" {
+		token.Token = token.AccessToken
+	}
+
+	return map[string]string{"Authorization": "Bearer " + token.Token}, nil
+}
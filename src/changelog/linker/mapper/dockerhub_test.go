@@ -0,0 +1,89 @@
+package mapper_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Masterminds/semver"
+	"github.com/newrelic/release-toolkit/src/changelog"
+	"github.com/newrelic/release-toolkit/src/changelog/linker/mapper"
+)
+
+//nolint:funlen
+func TestDockerHub_Map(t *testing.T) {
+	t.Parallel()
+
+	const token = "anonymous-token"
+
+	routes := map[string]string{
+		// Single-platform image with the source in the labels of its config.
+		"/v2/newrelic/infrastructure/manifests/1.33.2": `{"config": {"digest": "sha256:config"}}`,
+		"/v2/newrelic/infrastructure/blobs/sha256:config": `{"config": {"Labels": {
+			"org.opencontainers.image.source": "https://github.com/newrelic/infrastructure-agent"
+		}}}`,
+		// Multi-platform image with the source in the annotations of the manifest for linux/amd64.
+		"/v2/library/alpine/manifests/3.17.0": `{"manifests": [
+			{"digest": "sha256:arm", "platform": {"os": "linux", "architecture": "arm64"}},
+			{"digest": "sha256:amd", "platform": {"os": "linux", "architecture": "amd64"}}
+		]}`,
+		"/v2/library/alpine/manifests/sha256:amd": `{"annotations": {
+			"org.opencontainers.image.source": "https://github.com/alpinelinux/docker-alpine.git"
+		}}`,
+		// Image index with the source in its own annotations.
+		"/v2/owner/annotated/manifests/v2.0.0":       `{"annotations": {"org.opencontainers.image.source": "https://github.com/owner/annotated"}, "manifests": []}`,
+		"/v2/owner/unlabeled/manifests/1.0.0":        `{"config": {"digest": "sha256:unlabeled"}}`,
+		"/v2/owner/unlabeled/blobs/sha256:unlabeled": `{"config": {"Labels": null}}`,
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/token":
+			if r.URL.Query().Get("service") != "registry.test" || r.URL.Query().Get("scope") == "" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			_, _ = fmt.Fprintf(w, `{"token": %q}`, token)
+			return
+		case "/v2/":
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="http://%s/token",service="registry.test"`, r.Host))
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		if r.Header.Get("Authorization") != "Bearer "+token {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		body, found := routes[r.URL.Path]
+		if !found {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+
+	dockerHub := mapper.NewDockerHub(server.URL)
+
+	for _, tc := range []struct {
+		name     string
+		version  string
+		expected string
+	}{
+		{name: "newrelic/infrastructure", version: "1.33.2", expected: "https://github.com/newrelic/infrastructure-agent/releases/tag/1.33.2"},
+		{name: "docker.io/newrelic/infrastructure", version: "1.33.2", expected: "https://github.com/newrelic/infrastructure-agent/releases/tag/1.33.2"},
+		{name: "alpine", version: "3.17.0", expected: "https://github.com/alpinelinux/docker-alpine/releases/tag/3.17.0"},
+		{name: "owner/annotated", version: "v2.0.0", expected: "https://github.com/owner/annotated/releases/tag/v2.0.0"},
+		{name: "owner/unlabeled", version: "1.0.0", expected: ""},
+		{name: "owner/unknown", version: "1.0.0", expected: ""},
+		{name: "quay.io/owner/image", version: "1.0.0", expected: ""},
+	} {
+		dep := changelog.Dependency{Name: tc.name, To: semver.MustParse(tc.version)}
+		if actual := dockerHub.Map(dep); actual != tc.expected {
+			t.Errorf("Expected %s:%s to be linked to %q, got %q", tc.name, tc.version, tc.expected, actual)
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package mapper
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/newrelic/release-toolkit/src/changelog"
+	log "github.com/sirupsen/logrus"
+)
+
+// DefaultGoProxyURL is the URL of the public Go module proxy.
+const DefaultGoProxyURL = "https://proxy.golang.org"
+
+var (
+	// goImportRegex matches the go-import meta tag served by vanity import paths, capturing its content.
+	goImportRegex = regexp.MustCompile(`<meta\s+name=["']go-import["']\s+content=["']([^"']*)["']`)
+	// majorSuffixRegex matches the major version suffix of a module path, e.g. `/v2`.
+	majorSuffixRegex = regexp.MustCompile(`/v[0-9]+$`)
+)
+
+// GoProxy links Go modules to the release of their new version in their source repository, which is taken from the
+// origin the module proxy at its base URL reports for that version or, if not reported and VanityImports is set, from
+// the go-import meta tag of its import path, as for vanity import paths such as `golang.org/x/net` or
+// `k8s.io/client-go`.
+type GoProxy struct {
+	registry
+	// VanityImports enables looking up the go-import meta tag of import paths, which requests the host of each
+	// module directly. It is ignored unless the proxy is DefaultGoProxyURL, as modules served by other proxies may be
+	// private and hosted in hosts that must not be contacted.
+	VanityImports bool
+}
+
+// NewGoProxy returns a GoProxy that queries the module proxy at baseURL, which is DefaultGoProxyURL if empty.
+func NewGoProxy(baseURL string, opts ...RegistryOptionFunc) GoProxy {
+	if baseURL == "" {
+		baseURL = DefaultGoProxyURL
+	}
+
+	return GoProxy{registry: newRegistry("Go proxy", baseURL, opts...)}
+}
+
+// goInfo is the response of the proxy to `$module/@v/$version.info`.
+type goInfo struct {
+	Version string
+	Origin  *struct {
+		VCS string
+		URL string
+		Ref string
+	}
+}
+
+func (g GoProxy) Map(dep changelog.Dependency) string {
+	if !isGoModule(dep.Name) {
+		log.Debugf("Go proxy mapper: Dependency %q is not a Go module path.", dep.Name)
+		return ""
+	}
+
+	return g.link(dep, func() (string, string, error) {
+		return g.resolve(dep)
+	})
+}
+
+func (g GoProxy) resolve(dep changelog.Dependency) (string, string, error) {
+	version := dep.To.Original()
+	if !strings.HasPrefix(version, "v") {
+		version = "v" + version
+	}
+
+	info := goInfo{}
+	url := fmt.Sprintf("%s/%s/@v/%s.info", g.baseURL, escapeGoPath(dep.Name), escapeGoPath(version))
+	if err := g.getJSON(url, nil, &info); err != nil {
+		return "", "", err
+	}
+
+	if info.Origin != nil && info.Origin.VCS == "git" && info.Origin.URL != "" {
+		return info.Origin.URL, strings.TrimPrefix(info.Origin.Ref, "refs/tags/"), nil
+	}
+
+	if !g.VanityImports || g.baseURL != DefaultGoProxyURL {
+		return "", "", fmt.Errorf("%w: proxy did not report the origin of %s@%s", ErrRegistryNotFound, dep.Name, version)
+	}
+
+	log.Debugf("Go proxy mapper: Proxy did not report the origin of %s@%s, looking up its import path", dep.Name, version)
+
+	return g.vanity(dep.Name, version)
+}
+
+// vanity returns the repository the go-import meta tag of the import path of module points to, and the tag of version
+// in it, which is prefixed by the directory of the module if it is not in the root of the repository.
+func (g GoProxy) vanity(module, version string) (string, string, error) {
+	resp, err := g.get("https://"+module+"?go-get=1", nil)
+	if err != nil {
+		return "", "", err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxRegistryResponseBytes))
+	if err != nil {
+		return "", "", fmt.Errorf("reading go-import meta tag of %q: %w", module, err)
+	}
+
+	for _, match := range goImportRegex.FindAllStringSubmatch(string(body), -1) {
+		// The content of the tag is `import-prefix vcs repo-root`.
+		fields := strings.Fields(match[1])
+		if len(fields) != 3 || fields[1] != "git" {
+			continue
+		}
+
+		prefix := fields[0]
+		if module != prefix && !strings.HasPrefix(module, prefix+"/") {
+			continue
+		}
+
+		dir := strings.TrimPrefix(majorSuffixRegex.ReplaceAllString(module, ""), prefix)
+		dir = strings.TrimPrefix(dir, "/")
+		if dir == "" {
+			return fields[2], version, nil
+		}
+
+		return fields[2], dir + "/" + version, nil
+	}
+
+	return "", "", fmt.Errorf("%w: no git go-import meta tag for %q", ErrRegistryNotFound, module)
+}
+
+// isGoModule returns whether name looks like a module path, whose first element is a domain name.
+func isGoModule(name string) bool {
+	host, _, found := strings.Cut(name, "/")
+	return found && strings.Contains(host, ".") && !strings.ContainsAny(name, " @:")
+}
+
+// escapeGoPath escapes a module path or version as the module proxy protocol requires, replacing uppercase letters by
+// an exclamation mark followed by their lowercase.
+func escapeGoPath(path string) string {
+	escaped := &strings.Builder{}
+	for _, r := range path {
+		if unicode.IsUpper(r) {
+			escaped.WriteRune('!')
+			r = unicode.ToLower(r)
+		}
+		escaped.WriteRune(r)
+	}
+
+	return escaped.String()
+}
@@ -0,0 +1,99 @@
+package mapper_test
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/Masterminds/semver"
+	"github.com/newrelic/release-toolkit/src/changelog"
+	"github.com/newrelic/release-toolkit/src/changelog/linker/mapper"
+)
+
+// rewriteTransport sends all requests to target, prefixing their path with the host they were made to.
+type rewriteTransport struct {
+	target *url.URL
+}
+
+func (rt rewriteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Path = "/" + req.URL.Host + req.URL.Path
+	req.URL.Scheme = rt.target.Scheme
+	req.URL.Host = rt.target.Host
+
+	return http.DefaultTransport.RoundTrip(req) //nolint:wrapcheck
+}
+
+func TestGoProxy_Map(t *testing.T) {
+	t.Parallel()
+
+	// The same server stands in for the proxy, at proxy.golang.org, and for the vanity import paths.
+	server := registryServer(t, map[string]string{
+		"/proxy.golang.org/github.com/!burnt!sushi/toml/@v/v1.2.1.info": `{"Version": "v1.2.1", "Origin": {
+			"VCS": "git", "URL": "https://github.com/BurntSushi/toml", "Ref": "refs/tags/v1.2.1"
+		}}`,
+		"/proxy.golang.org/go.opentelemetry.io/otel/metric/@v/v0.33.0.info": `{"Version": "v0.33.0", "Origin": {
+			"VCS": "git", "URL": "https://github.com/open-telemetry/opentelemetry-go", "Ref": "refs/tags/metric/v0.33.0"
+		}}`,
+		"/proxy.golang.org/k8s.io/client-go/@v/v0.25.4.info": `{"Version": "v0.25.4"}`,
+		"/k8s.io/client-go": `<html><head><meta name="go-import" content="k8s.io/client-go git https://github.com/kubernetes/client-go"></head></html>`,
+		"/proxy.golang.org/example.com/mono/sub/v2/@v/v2.1.0.info":  `{"Version": "v2.1.0"}`,
+		"/example.com/mono/sub/v2":                                  `<meta name="go-import" content="example.com/mono git https://github.com/example/mono.git">`,
+		"/proxy.golang.org/example.com/elsewhere/@v/v1.0.0.info":    `{"Version": "v1.0.0"}`,
+		"/example.com/elsewhere":                                    `<meta name="go-import" content="example.com/elsewhere git https://gitlab.com/example/elsewhere">`,
+		"/proxy.golang.org/example.com/no-meta/@v/v1.0.0.info":      `{"Version": "v1.0.0"}`,
+		"/proxy.golang.org/example.com/mercurial/@v/v1.0.0.info":    `{"Version": "v1.0.0"}`,
+		"/example.com/mercurial":                                    `<meta name="go-import" content="example.com/mercurial hg https://github.com/example/mercurial">`,
+		"/proxy.golang.org/example.com/wrong-prefix/@v/v1.0.0.info": `{"Version": "v1.0.0"}`,
+		"/example.com/wrong-prefix":                                 `<meta name="go-import" content="example.com/other git https://github.com/example/other">`,
+	})
+	target, _ := url.Parse(server.URL)
+	client := mapper.RegistryHTTPClient(&http.Client{Transport: rewriteTransport{target: target}})
+	goProxy := mapper.NewGoProxy(mapper.DefaultGoProxyURL, client)
+	goProxy.VanityImports = true
+
+	for _, tc := range []struct {
+		name     string
+		version  string
+		expected string
+	}{
+		{name: "github.com/BurntSushi/toml", version: "v1.2.1", expected: "https://github.com/BurntSushi/toml/releases/tag/v1.2.1"},
+		{name: "go.opentelemetry.io/otel/metric", version: "0.33.0", expected: "https://github.com/open-telemetry/opentelemetry-go/releases/tag/metric/v0.33.0"},
+		{name: "k8s.io/client-go", version: "v0.25.4", expected: "https://github.com/kubernetes/client-go/releases/tag/v0.25.4"},
+		{name: "example.com/mono/sub/v2", version: "v2.1.0", expected: "https://github.com/example/mono/releases/tag/sub/v2.1.0"},
+		{name: "example.com/elsewhere", version: "v1.0.0", expected: ""},
+		{name: "example.com/no-meta", version: "v1.0.0", expected: ""},
+		{name: "example.com/mercurial", version: "v1.0.0", expected: ""},
+		{name: "example.com/wrong-prefix", version: "v1.0.0", expected: ""},
+		{name: "example.com/unknown", version: "v1.0.0", expected: ""},
+		{name: "left-pad", version: "v1.0.0", expected: ""},
+	} {
+		dep := changelog.Dependency{Name: tc.name, To: semver.MustParse(tc.version)}
+		if actual := goProxy.Map(dep); actual != tc.expected {
+			t.Errorf("Expected %s@%s to be linked to %q, got %q", tc.name, tc.version, tc.expected, actual)
+		}
+	}
+}
+
+func TestGoProxy_Map_No_Vanity_Imports(t *testing.T) {
+	t.Parallel()
+
+	server := registryServer(t, map[string]string{
+		"/proxy.golang.org/k8s.io/client-go/@v/v0.25.4.info": `{"Version": "v0.25.4"}`,
+		"/proxy.test/k8s.io/client-go/@v/v0.25.4.info":       `{"Version": "v0.25.4"}`,
+		"/k8s.io/client-go": `<meta name="go-import" content="k8s.io/client-go git https://github.com/kubernetes/client-go">`,
+	})
+	target, _ := url.Parse(server.URL)
+	client := mapper.RegistryHTTPClient(&http.Client{Transport: rewriteTransport{target: target}})
+
+	disabled := mapper.NewGoProxy(mapper.DefaultGoProxyURL, client)
+	otherProxy := mapper.NewGoProxy("https://proxy.test", client)
+	otherProxy.VanityImports = true
+
+	dep := changelog.Dependency{Name: "k8s.io/client-go", To: semver.MustParse("v0.25.4")}
+	for name, goProxy := range map[string]mapper.GoProxy{"Disabled": disabled, "Other_Proxy": otherProxy} {
+		if actual := goProxy.Map(dep); actual != "" {
+			t.Errorf("%s: Expected import path not to be looked up, got %q", name, actual)
+		}
+	}
+}
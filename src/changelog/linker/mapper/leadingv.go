@@ -2,9 +2,7 @@ package mapper
 
 import (
 	"fmt"
-	"net/http"
 	"strings"
-	"time"
 
 	"github.com/Masterminds/semver"
 	"github.com/newrelic/release-toolkit/src/changelog"
@@ -12,21 +10,55 @@ import (
 	log "github.com/sirupsen/logrus"
 )
 
-const (
-	checkTimeoutSeconds = 1
-)
-
 // LeadingVCheck wraps another mapper and performs a check over the result of Map. If the check is not
 // successful, it tries to generate it prepending a leading v to the version.
+// It can also wrap a linker.Comparer, in which case Compare performs the same check and the leading v of both
+// versions is switched.
 type LeadingVCheck struct {
 	mapper    linker.Mapper
 	checkLink func(link string) (bool, error)
 }
 
+// LeadingVCheckOptionFunc configures a LeadingVCheck.
+type LeadingVCheckOptionFunc func(l *LeadingVCheck)
+
+// LeadingVCheckLinkChecker sets the LinkChecker used to check links, which can be shared among several LeadingVCheck.
+func LeadingVCheckLinkChecker(checker *LinkChecker) LeadingVCheckOptionFunc {
+	return func(l *LeadingVCheck) {
+		l.checkLink = checker.Check
+	}
+}
+
 // NewWithLeadingVCheck returns a LeadingVCheck with the provided underlying mapper and a check function which
-// performs a request to the url corresponding to the link and check its status code.
-func NewWithLeadingVCheck(mapper linker.Mapper) *LeadingVCheck {
-	return &LeadingVCheck{mapper: mapper, checkLink: checkLinkResponse}
+// performs a request to the url corresponding to the link and check its status code, using a LinkChecker with the
+// default settings unless LeadingVCheckLinkChecker is set.
+func NewWithLeadingVCheck(mapper linker.Mapper, opts ...LeadingVCheckOptionFunc) *LeadingVCheck {
+	l := &LeadingVCheck{mapper: mapper, checkLink: NewLinkChecker().Check}
+	for _, opt := range opts {
+		opt(l)
+	}
+
+	return l
+}
+
+// NewCompareWithLeadingVCheck returns a LeadingVCheck with the provided underlying comparer, which is checked as
+// NewWithLeadingVCheck does with mappers.
+func NewCompareWithLeadingVCheck(comparer linker.Comparer, opts ...LeadingVCheckOptionFunc) *LeadingVCheck {
+	return NewWithLeadingVCheck(comparerMapper{comparer: comparer}, opts...)
+}
+
+// comparerMapper adapts a linker.Comparer to linker.Mapper, so LeadingVCheck can wrap it.
+type comparerMapper struct {
+	comparer linker.Comparer
+}
+
+func (c comparerMapper) Map(dep changelog.Dependency) string {
+	return c.comparer.Compare(dep)
+}
+
+// Compare is Map, for LeadingVCheck to also be a linker.Comparer when wrapping one.
+func (l *LeadingVCheck) Compare(dep changelog.Dependency) string {
+	return l.Map(dep)
 }
 
 func (l *LeadingVCheck) Map(dep changelog.Dependency) string {
@@ -56,23 +88,41 @@ func (l *LeadingVCheck) Map(dep changelog.Dependency) string {
 	return ""
 }
 
+// switchDepLeadingV returns dep with the leading v of its To version, and its From version if set, switched.
 func (l *LeadingVCheck) switchDepLeadingV(dep changelog.Dependency) (changelog.Dependency, error) {
-	literal := dep.To.Original()
+	switchedDep := dep
+
+	var err error
+	switchedDep.To, err = switchLeadingV(dep.To)
+	if err != nil {
+		return dep, err
+	}
+
+	if dep.From != nil {
+		switchedDep.From, err = switchLeadingV(dep.From)
+		if err != nil {
+			return dep, err
+		}
+	}
+
+	return switchedDep, nil
+}
+
+func switchLeadingV(version *semver.Version) (*semver.Version, error) {
+	literal := version.Original()
 	var switchedLiteral string
 	if strings.HasPrefix(literal, "v") {
 		switchedLiteral = strings.TrimPrefix(literal, "v")
 	} else {
 		switchedLiteral = "v" + literal
 	}
-	switchedDep := dep
+
 	switchedVersion, err := semver.NewVersion(switchedLiteral)
 	if err != nil {
-		return dep, fmt.Errorf("error switching leading v in %q: %w", literal, err)
+		return nil, fmt.Errorf("error switching leading v in %q: %w", literal, err)
 	}
 
-	switchedDep.To = switchedVersion
-
-	return switchedDep, nil
+	return switchedVersion, nil
 }
 
 func (l *LeadingVCheck) isValid(link string) bool {
@@ -86,16 +136,3 @@ func (l *LeadingVCheck) isValid(link string) bool {
 
 	return linkOK
 }
-
-func checkLinkResponse(link string) (bool, error) {
-	client := http.Client{
-		Timeout: checkTimeoutSeconds * time.Second,
-	}
-	resp, err := client.Get(link) //nolint:noctx
-	if err != nil {
-		return false, fmt.Errorf("error performing the request to check %q link: %w", link, err)
-	}
-	defer resp.Body.Close()
-
-	return resp.StatusCode == http.StatusOK, nil
-}
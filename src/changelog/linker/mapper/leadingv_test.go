@@ -148,6 +148,24 @@ func TestLeadingVCheck_Map(t *testing.T) {
 	}
 }
 
+type comparerMock struct{}
+
+func (comparerMock) Compare(dep changelog.Dependency) string {
+	return "compare-" + dep.From.Original() + "..." + dep.To.Original()
+}
+
+func TestLeadingVCheck_Compare(t *testing.T) {
+	t.Parallel()
+
+	m := NewCompareWithLeadingVCheck(comparerMock{})
+	m.checkLink = func(link string) (bool, error) {
+		return link == "compare-v1.2.2...v1.2.3", nil
+	}
+
+	dep := changelog.Dependency{From: semver.MustParse("1.2.2"), To: semver.MustParse("1.2.3")}
+	assert.Equal(t, "compare-v1.2.2...v1.2.3", m.Compare(dep))
+}
+
 func TestLeadingVCheck_switchDepLeadingV(t *testing.T) {
 	t.Parallel()
 
@@ -169,6 +187,11 @@ func TestLeadingVCheck_switchDepLeadingV(t *testing.T) {
 			original: changelog.Dependency{To: semver.MustParse("1.2.3")},
 			expected: changelog.Dependency{To: semver.MustParse("v1.2.3")},
 		},
+		{
+			name:     "From is also switched",
+			original: changelog.Dependency{From: semver.MustParse("1.2.2"), To: semver.MustParse("1.2.3")},
+			expected: changelog.Dependency{From: semver.MustParse("v1.2.2"), To: semver.MustParse("v1.2.3")},
+		},
 		{
 			name:     "Error including leading v",
 			original: changelog.Dependency{To: &semver.Version{}},
@@ -232,7 +255,7 @@ func TestLeadingVCheck_checkLink(t *testing.T) {
 			t.Parallel()
 			server := httptest.NewServer(tc.handler)
 			defer server.Close()
-			result, err := checkLinkResponse(server.URL)
+			result, err := NewLinkChecker(LinkCheckerRetries(0, 0)).Check(server.URL)
 			if tc.err {
 				require.Error(t, err)
 				return
@@ -0,0 +1,357 @@
+package mapper
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	checkTimeoutSeconds = 1
+	defaultCheckRetries = 2
+	defaultCheckBackoff = 500 * time.Millisecond
+	// maxCheckRetryWait caps the time a server can ask to wait before retrying, so checks are not retried, rather than
+	// stalled, until a rate limit that has been exhausted for a long time is reset.
+	maxCheckRetryWait  = time.Minute
+	linkCacheFilePerms = 0o600
+	linkCacheDirPerms  = 0o755
+)
+
+var ErrCheckStatus = errors.New("unexpected status code checking link")
+
+var (
+	// ghReleasePageRegex matches the pages of GitHub releases, checked through the releases API when a token is set.
+	ghReleasePageRegex = regexp.MustCompile(`^https://github\.com/([\w.-]+)/([\w.-]+)/releases/tag/([^/?#]+)$`)
+	// ghComparePageRegex matches the pages of GitHub comparisons, checked through the compare API when a token is set.
+	ghComparePageRegex = regexp.MustCompile(`^https://github\.com/([\w.-]+)/([\w.-]+)/compare/([^/?#]+)$`)
+)
+
+// LinkChecker checks whether links exist by performing HEAD requests to them, retrying with exponential backoff on
+// network errors, authorization errors, rate limits and server errors, waiting longer if the server asks to. Results
+// can be stored in a LinkCache so links are checked only once across runs. It is safe for concurrent use.
+type LinkChecker struct {
+	client  *http.Client
+	retries int
+	backoff time.Duration
+	cache   *LinkCache
+	apiURL  string
+	token   string
+}
+
+// LinkCheckerOptionFunc configures a LinkChecker.
+type LinkCheckerOptionFunc func(c *LinkChecker)
+
+// LinkCheckerTimeout sets the timeout of each request.
+func LinkCheckerTimeout(timeout time.Duration) LinkCheckerOptionFunc {
+	return func(c *LinkChecker) {
+		c.client.Timeout = timeout
+	}
+}
+
+// LinkCheckerRetries sets how many times a check is retried, waiting backoff before the first retry and doubling it
+// before each of the following ones.
+func LinkCheckerRetries(retries int, backoff time.Duration) LinkCheckerOptionFunc {
+	return func(c *LinkChecker) {
+		c.retries = retries
+		c.backoff = backoff
+	}
+}
+
+// LinkCheckerGithubAPI sets the GitHub API release and compare pages of github.com are checked through, authenticated
+// with token, so checks are subject to the higher rate limits of authenticated requests. The token is only sent to
+// apiURL, which is DefaultGithubAPIURL if empty. Pages are checked directly if token is empty.
+func LinkCheckerGithubAPI(apiURL, token string) LinkCheckerOptionFunc {
+	return func(c *LinkChecker) {
+		if apiURL == "" {
+			apiURL = DefaultGithubAPIURL
+		}
+
+		c.apiURL = strings.TrimSuffix(apiURL, "/")
+		c.token = token
+	}
+}
+
+// LinkCheckerCache sets the cache results are read from and stored in.
+func LinkCheckerCache(cache *LinkCache) LinkCheckerOptionFunc {
+	return func(c *LinkChecker) {
+		c.cache = cache
+	}
+}
+
+// NewLinkChecker returns a LinkChecker that times out after one second and retries failed checks twice, without
+// cache, unless configured otherwise.
+func NewLinkChecker(opts ...LinkCheckerOptionFunc) *LinkChecker {
+	c := &LinkChecker{
+		client:  &http.Client{Timeout: checkTimeoutSeconds * time.Second},
+		retries: defaultCheckRetries,
+		backoff: defaultCheckBackoff,
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// Check returns whether link exists, this is, if it responds with a 2xx status. Links responding with a 4xx status
+// other than 401, 403 and 429, which GitHub uses for rate limits, do not exist. An error is returned if the check fails
+// after all retries. Only the results of links that exist, or that respond with 404 or 410, are cached.
+func (c *LinkChecker) Check(link string) (bool, error) {
+	if ok, found := c.cache.get(link); found {
+		log.Debugf("Link check for %q found in cache: %v", link, ok)
+		return ok, nil
+	}
+
+	backoff := c.backoff
+	wait := backoff
+
+	var err error
+	for attempt := 0; attempt <= c.retries; attempt++ {
+		if attempt > 0 {
+			log.Debugf("Retrying link check for %q in %v: %v", link, wait, err)
+			time.Sleep(wait)
+			backoff *= 2
+			wait = backoff
+		}
+
+		var ok, cacheable bool
+		ok, cacheable, err = c.check(link)
+		if err == nil {
+			if cacheable {
+				c.cache.set(link, ok)
+			}
+			return ok, nil
+		}
+
+		var retryable retryableError
+		if !errors.As(err, &retryable) {
+			break
+		}
+
+		if retryable.after > maxCheckRetryWait {
+			log.Debugf("Not retrying link check for %q as the server asked to wait %v", link, retryable.after)
+			break
+		}
+
+		if retryable.after > wait {
+			wait = retryable.after
+		}
+	}
+
+	return false, err
+}
+
+// retryableError is returned by check for failures worth retrying, after the time the server asked to wait, if any.
+type retryableError struct {
+	err   error
+	after time.Duration
+}
+
+func (e retryableError) Error() string {
+	return e.err.Error()
+}
+
+func (e retryableError) Unwrap() error {
+	return e.err
+}
+
+// check performs a single check of link, returning whether it exists and whether that result can be cached.
+func (c *LinkChecker) check(link string) (bool, bool, error) {
+	target, token := c.target(link)
+
+	resp, err := c.request(http.MethodHead, target, token)
+	if err != nil {
+		return false, false, retryableError{err: err}
+	}
+	resp.Body.Close()
+
+	// Some servers do not implement HEAD.
+	if resp.StatusCode == http.StatusMethodNotAllowed || resp.StatusCode == http.StatusNotImplemented {
+		resp, err = c.request(http.MethodGet, target, token)
+		if err != nil {
+			return false, false, retryableError{err: err}
+		}
+		resp.Body.Close()
+	}
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return true, true, nil
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone:
+		return false, true, nil
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden ||
+		resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return false, false, retryableError{
+			err:   fmt.Errorf("%w: %q returned %d", ErrCheckStatus, link, resp.StatusCode),
+			after: retryAfter(resp, time.Now()),
+		}
+	case resp.StatusCode >= 400:
+		return false, false, nil
+	default:
+		return false, false, fmt.Errorf("%w: %q returned %d", ErrCheckStatus, link, resp.StatusCode)
+	}
+}
+
+// retryAfter returns the time resp asks to wait before retrying, taken from its Retry-After header or, if the rate
+// limit is exhausted, from the X-RateLimit-Reset header sent by GitHub. It is zero if resp does not ask to wait.
+func retryAfter(resp *http.Response, now time.Time) time.Duration {
+	if header := resp.Header.Get("Retry-After"); header != "" {
+		if seconds, err := strconv.Atoi(header); err == nil {
+			return time.Duration(seconds) * time.Second
+		}
+
+		if date, err := http.ParseTime(header); err == nil {
+			return date.Sub(now)
+		}
+	}
+
+	if resp.Header.Get("X-RateLimit-Remaining") == "0" {
+		if reset, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+			return time.Unix(reset, 0).Sub(now)
+		}
+	}
+
+	return 0
+}
+
+// target returns the URL requested to check link, which is the one of the GitHub API for the release and compare pages
+// of github.com if a token is set, and the token sent with the request, if any.
+func (c *LinkChecker) target(link string) (string, string) {
+	if c.token == "" {
+		return link, ""
+	}
+
+	if m := ghReleasePageRegex.FindStringSubmatch(link); m != nil {
+		return fmt.Sprintf("%s/repos/%s/%s/releases/tags/%s", c.apiURL, m[1], m[2], m[3]), c.token
+	}
+
+	if m := ghComparePageRegex.FindStringSubmatch(link); m != nil {
+		return fmt.Sprintf("%s/repos/%s/%s/compare/%s", c.apiURL, m[1], m[2], m[3]), c.token
+	}
+
+	return link, ""
+}
+
+func (c *LinkChecker) request(method, link, token string) (*http.Response, error) {
+	req, err := http.NewRequest(method, link, nil) //nolint:noctx
+	if err != nil {
+		return nil, fmt.Errorf("building request to check %q link: %w", link, err)
+	}
+
+	if token != "" {
+		req.Header.Set("Accept", "application/vnd.github+json")
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error performing the request to check %q link: %w", link, err)
+	}
+
+	return resp, nil
+}
+
+// LinkCache stores the results of link checks in a file, keyed by URL, for a given time to live. A nil LinkCache
+// stores nothing. It is safe for concurrent use.
+type LinkCache struct {
+	path    string
+	ttl     time.Duration
+	mtx     sync.Mutex
+	entries map[string]linkCacheEntry
+}
+
+type linkCacheEntry struct {
+	OK      bool      `json:"ok"`
+	Checked time.Time `json:"checked"`
+}
+
+// LoadLinkCache reads the cache stored in path, dropping the entries older than ttl. An empty cache is returned if
+// the file does not exist or cannot be parsed.
+func LoadLinkCache(path string, ttl time.Duration) (*LinkCache, error) {
+	cache := &LinkCache{path: path, ttl: ttl, entries: map[string]linkCacheEntry{}}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cache, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading link cache %q: %w", path, err)
+	}
+
+	entries := map[string]linkCacheEntry{}
+	if err := json.Unmarshal(data, &entries); err != nil {
+		log.Warnf("Ignoring link cache %q as it could not be parsed: %v", path, err)
+		return cache, nil
+	}
+
+	for link, entry := range entries {
+		if time.Since(entry.Checked) < ttl {
+			cache.entries[link] = entry
+		}
+	}
+
+	return cache, nil
+}
+
+// Save writes the cache to the file it was loaded from.
+func (lc *LinkCache) Save() error {
+	if lc == nil {
+		return nil
+	}
+
+	lc.mtx.Lock()
+	defer lc.mtx.Unlock()
+
+	data, err := json.MarshalIndent(lc.entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding link cache: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(lc.path), linkCacheDirPerms); err != nil {
+		return fmt.Errorf("creating link cache directory: %w", err)
+	}
+
+	if err := os.WriteFile(lc.path, data, linkCacheFilePerms); err != nil {
+		return fmt.Errorf("writing link cache %q: %w", lc.path, err)
+	}
+
+	return nil
+}
+
+func (lc *LinkCache) get(link string) (bool, bool) {
+	if lc == nil {
+		return false, false
+	}
+
+	lc.mtx.Lock()
+	defer lc.mtx.Unlock()
+
+	entry, found := lc.entries[link]
+	if !found || time.Since(entry.Checked) >= lc.ttl {
+		return false, false
+	}
+
+	return entry.OK, true
+}
+
+func (lc *LinkCache) set(link string, ok bool) {
+	if lc == nil {
+		return
+	}
+
+	lc.mtx.Lock()
+	defer lc.mtx.Unlock()
+
+	lc.entries[link] = linkCacheEntry{OK: ok, Checked: time.Now()}
+}
@@ -0,0 +1,226 @@
+package mapper_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/newrelic/release-toolkit/src/changelog/linker/mapper"
+)
+
+//nolint:funlen
+func TestLinkChecker_Check(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name string
+		// statuses are returned in order for each request, repeating the last one.
+		statuses []int
+		// headers are sent in every response.
+		headers  map[string]string
+		expected bool
+		err      bool
+		requests int32
+		// minDuration is the minimum time the check is expected to take.
+		minDuration time.Duration
+	}{
+		{name: "OK", statuses: []int{http.StatusOK}, expected: true, requests: 1},
+		{name: "Not_Found", statuses: []int{http.StatusNotFound}, expected: false, requests: 1},
+		{name: "Gone", statuses: []int{http.StatusGone}, expected: false, requests: 1},
+		{name: "Bad_Request", statuses: []int{http.StatusBadRequest}, expected: false, requests: 1},
+		{name: "Retried_Unauthorized", statuses: []int{http.StatusUnauthorized, http.StatusOK}, expected: true, requests: 2},
+		{name: "Retried_Forbidden", statuses: []int{http.StatusForbidden, http.StatusOK}, expected: true, requests: 2},
+		{name: "Retried_Server_Error", statuses: []int{http.StatusBadGateway, http.StatusOK}, expected: true, requests: 2},
+		{name: "Retried_Rate_Limit", statuses: []int{http.StatusTooManyRequests, http.StatusTooManyRequests, http.StatusOK}, expected: true, requests: 3},
+		{name: "Retries_Exhausted", statuses: []int{http.StatusServiceUnavailable}, err: true, requests: 3},
+		{name: "HEAD_Not_Allowed", statuses: []int{http.StatusMethodNotAllowed, http.StatusOK}, expected: true, requests: 2},
+		{
+			name:        "Retry_After",
+			statuses:    []int{http.StatusTooManyRequests, http.StatusOK},
+			headers:     map[string]string{"Retry-After": "1"},
+			expected:    true,
+			requests:    2,
+			minDuration: time.Second,
+		},
+		{
+			name:     "Retry_After_Too_Long",
+			statuses: []int{http.StatusTooManyRequests},
+			headers:  map[string]string{"Retry-After": "3600"},
+			err:      true,
+			requests: 1,
+		},
+		{
+			name:     "Rate_Limit_Reset_Too_Long",
+			statuses: []int{http.StatusForbidden},
+			headers: map[string]string{
+				"X-RateLimit-Remaining": "0",
+				"X-RateLimit-Reset":     strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10),
+			},
+			err:      true,
+			requests: 1,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var requests int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				n := int(atomic.AddInt32(&requests, 1))
+				if n > len(tc.statuses) {
+					n = len(tc.statuses)
+				}
+
+				status := tc.statuses[n-1]
+				if r.Method != http.MethodHead && status == http.StatusMethodNotAllowed {
+					t.Errorf("Unexpected %s request", r.Method)
+				}
+				for name, value := range tc.headers {
+					w.Header().Set(name, value)
+				}
+
+				w.WriteHeader(status)
+			}))
+			defer server.Close()
+
+			checker := mapper.NewLinkChecker(mapper.LinkCheckerRetries(2, time.Millisecond))
+			start := time.Now()
+			ok, err := checker.Check(server.URL)
+			if tc.err != (err != nil) {
+				t.Fatalf("Expected error to be %v, got %v", tc.err, err)
+			}
+			if ok != tc.expected {
+				t.Fatalf("Expected check to be %v, got %v", tc.expected, ok)
+			}
+			if actual := atomic.LoadInt32(&requests); actual != tc.requests {
+				t.Fatalf("Expected %d requests, got %d", tc.requests, actual)
+			}
+			if elapsed := time.Since(start); elapsed < tc.minDuration {
+				t.Fatalf("Expected check to take at least %v, took %v", tc.minDuration, elapsed)
+			}
+		})
+	}
+}
+
+func TestLinkChecker_Cache(t *testing.T) {
+	t.Parallel()
+
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		switch r.URL.Path {
+		case "/found":
+			w.WriteHeader(http.StatusOK)
+		case "/error":
+			w.WriteHeader(http.StatusInternalServerError)
+		case "/rate-limited":
+			w.WriteHeader(http.StatusForbidden)
+		case "/invalid":
+			w.WriteHeader(http.StatusBadRequest)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	cachePath := filepath.Join(t.TempDir(), "cache", "links.json")
+	check := func(ttl time.Duration) {
+		t.Helper()
+
+		cache, err := mapper.LoadLinkCache(cachePath, ttl)
+		if err != nil {
+			t.Fatalf("Error loading cache: %v", err)
+		}
+
+		checker := mapper.NewLinkChecker(mapper.LinkCheckerCache(cache), mapper.LinkCheckerRetries(0, 0))
+		for path, expected := range map[string]bool{
+			"/found": true, "/missing": false, "/error": false, "/rate-limited": false, "/invalid": false,
+		} {
+			if ok, _ := checker.Check(server.URL + path); ok != expected {
+				t.Fatalf("Expected check of %s to be %v, got %v", path, expected, ok)
+			}
+		}
+
+		if err := cache.Save(); err != nil {
+			t.Fatalf("Error saving cache: %v", err)
+		}
+	}
+
+	check(time.Hour)
+	if actual := atomic.LoadInt32(&requests); actual != 5 {
+		t.Fatalf("Expected 5 requests, got %d", actual)
+	}
+
+	// Results are read from the cache, except for errors and statuses other than 404, which are not cached.
+	check(time.Hour)
+	if actual := atomic.LoadInt32(&requests); actual != 8 {
+		t.Fatalf("Expected 3 more requests, got %d", actual-5)
+	}
+
+	// Expired results are checked again.
+	check(time.Nanosecond)
+	if actual := atomic.LoadInt32(&requests); actual != 13 {
+		t.Fatalf("Expected 5 more requests, got %d", actual-8)
+	}
+}
+
+func TestLinkChecker_GithubAPI(t *testing.T) {
+	t.Parallel()
+
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if auth := r.Header.Get("Authorization"); auth != "Bearer secret" {
+			t.Errorf("Expected token to be sent to the API, got Authorization %q", auth)
+		}
+
+		switch r.URL.Path {
+		case "/repos/org/repo/releases/tags/v1.2.3", "/repos/org/repo/compare/v1.0.0...v1.2.3":
+			w.WriteHeader(http.StatusOK)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer api.Close()
+
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "" {
+			t.Errorf("Token sent to a host other than the API")
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer other.Close()
+
+	checker := mapper.NewLinkChecker(mapper.LinkCheckerGithubAPI(api.URL, "secret"), mapper.LinkCheckerRetries(0, 0))
+	for link, expected := range map[string]bool{
+		"https://github.com/org/repo/releases/tag/v1.2.3":        true,
+		"https://github.com/org/repo/releases/tag/v9.9.9":        false,
+		"https://github.com/org/repo/compare/v1.0.0...v1.2.3":    true,
+		"https://github.com/org/missing/compare/v1.0.0...v1.2.3": false,
+		other.URL + "/org/repo/releases/tag/v1.2.3":              true,
+	} {
+		ok, err := checker.Check(link)
+		if err != nil {
+			t.Fatalf("Error checking %q: %v", link, err)
+		}
+		if ok != expected {
+			t.Fatalf("Expected check of %q to be %v, got %v", link, expected, ok)
+		}
+	}
+}
+
+func TestLoadLinkCache_Invalid(t *testing.T) {
+	t.Parallel()
+
+	cachePath := filepath.Join(t.TempDir(), "links.json")
+	if err := os.WriteFile(cachePath, []byte("not json"), 0o600); err != nil {
+		t.Fatalf("Error writing cache: %v", err)
+	}
+
+	if _, err := mapper.LoadLinkCache(cachePath, time.Hour); err != nil {
+		t.Fatalf("Expected invalid cache to be ignored, got %v", err)
+	}
+}
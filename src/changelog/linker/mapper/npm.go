@@ -0,0 +1,82 @@
+package mapper
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/newrelic/release-toolkit/src/changelog"
+	log "github.com/sirupsen/logrus"
+)
+
+// DefaultNpmURL is the URL of the public npm registry.
+const DefaultNpmURL = "https://registry.npmjs.org"
+
+var (
+	// npmNameRegex matches the names of npm packages, optionally scoped.
+	npmNameRegex = regexp.MustCompile(`^(@[a-z0-9~-][a-z0-9._~-]*/)?[a-z0-9~-][a-z0-9._~-]*$`)
+	// npmShorthandRegex matches the `github:owner/repo` and `owner/repo` shorthands npm allows for repositories.
+	npmShorthandRegex = regexp.MustCompile(`^(?:github:)?([\w.-]+/[\w.-]+)$`)
+)
+
+// Npm links npm packages to the release of their new version in the repository declared in their package.json, as
+// served by the npm registry at its base URL.
+type Npm struct {
+	registry
+}
+
+// NewNpm returns an Npm that queries the registry at baseURL, which is DefaultNpmURL if empty.
+func NewNpm(baseURL string, opts ...RegistryOptionFunc) Npm {
+	if baseURL == "" {
+		baseURL = DefaultNpmURL
+	}
+
+	return Npm{registry: newRegistry("npm", baseURL, opts...)}
+}
+
+// npmManifest is the response of the registry to `$package/$version`. The repository of a package can be either a
+// string or an object with an url field.
+type npmManifest struct {
+	Repository json.RawMessage `json:"repository"`
+}
+
+func (n Npm) Map(dep changelog.Dependency) string {
+	if !npmNameRegex.MatchString(dep.Name) {
+		log.Debugf("npm mapper: Dependency %q is not an npm package name.", dep.Name)
+		return ""
+	}
+
+	return n.link(dep, func() (string, string, error) {
+		return n.resolve(dep)
+	})
+}
+
+func (n Npm) resolve(dep changelog.Dependency) (string, string, error) {
+	// Scoped packages are requested with their slash escaped.
+	name := strings.Replace(dep.Name, "/", "%2F", 1)
+	version := url.PathEscape(strings.TrimPrefix(dep.To.Original(), "v"))
+
+	manifest := npmManifest{}
+	if err := n.getJSON(fmt.Sprintf("%s/%s/%s", n.baseURL, name, version), nil, &manifest); err != nil {
+		return "", "", err
+	}
+
+	var repository string
+	if err := json.Unmarshal(manifest.Repository, &repository); err != nil {
+		object := struct {
+			URL string `json:"url"`
+		}{}
+		if err := json.Unmarshal(manifest.Repository, &object); err != nil {
+			return "", "", fmt.Errorf("%w: %q declares no repository", ErrRegistryNotFound, dep.Name)
+		}
+		repository = object.URL
+	}
+
+	if matches := npmShorthandRegex.FindStringSubmatch(repository); matches != nil {
+		repository = "github.com/" + matches[1]
+	}
+
+	return repository, "", nil
+}
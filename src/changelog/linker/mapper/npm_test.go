@@ -0,0 +1,48 @@
+package mapper_test
+
+import (
+	"testing"
+
+	"github.com/Masterminds/semver"
+	"github.com/newrelic/release-toolkit/src/changelog"
+	"github.com/newrelic/release-toolkit/src/changelog/linker/mapper"
+)
+
+func TestNpm_Map(t *testing.T) {
+	t.Parallel()
+
+	server := registryServer(t, map[string]string{
+		"/left-pad/1.3.0":       `{"name": "left-pad", "repository": {"type": "git", "url": "git+https://github.com/left-pad/left-pad.git"}}`,
+		"/@types/node/18.11.9":  `{"name": "@types/node", "repository": {"type": "git", "url": "https://github.com/DefinitelyTyped/DefinitelyTyped.git", "directory": "types/node"}}`,
+		"/shorthand/2.0.0":      `{"name": "shorthand", "repository": "github:owner/shorthand"}`,
+		"/bare-shorthand/2.0.0": `{"name": "bare-shorthand", "repository": "owner/bare-shorthand"}`,
+		"/gitlab/1.0.0":         `{"name": "gitlab", "repository": "https://gitlab.com/owner/gitlab"}`,
+		"/no-repo/1.0.0":        `{"name": "no-repo"}`,
+	})
+	npm := mapper.NewNpm(server.URL)
+
+	for _, tc := range []struct {
+		name     string
+		version  string
+		expected string
+	}{
+		{name: "left-pad", version: "1.3.0", expected: "https://github.com/left-pad/left-pad/releases/tag/1.3.0"},
+		{name: "left-pad", version: "v1.3.0", expected: "https://github.com/left-pad/left-pad/releases/tag/v1.3.0"},
+		{name: "@types/node", version: "18.11.9", expected: "https://github.com/DefinitelyTyped/DefinitelyTyped/releases/tag/18.11.9"},
+		{name: "shorthand", version: "2.0.0", expected: "https://github.com/owner/shorthand/releases/tag/2.0.0"},
+		{name: "bare-shorthand", version: "2.0.0", expected: "https://github.com/owner/bare-shorthand/releases/tag/2.0.0"},
+		{name: "gitlab", version: "1.0.0", expected: ""},
+		{name: "no-repo", version: "1.0.0", expected: ""},
+		{name: "unknown", version: "1.0.0", expected: ""},
+		{name: "github.com/not/npm", version: "1.0.0", expected: ""},
+	} {
+		dep := changelog.Dependency{Name: tc.name, To: semver.MustParse(tc.version)}
+		if actual := npm.Map(dep); actual != tc.expected {
+			t.Errorf("Expected %s@%s to be linked to %q, got %q", tc.name, tc.version, tc.expected, actual)
+		}
+	}
+
+	if actual := npm.Map(changelog.Dependency{Name: "left-pad"}); actual != "" {
+		t.Errorf("Expected dependency without version not to be linked, got %q", actual)
+	}
+}
@@ -0,0 +1,90 @@
+package mapper
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/newrelic/release-toolkit/src/changelog"
+	log "github.com/sirupsen/logrus"
+)
+
+// DefaultPyPIURL is the URL of the public Python Package Index.
+const DefaultPyPIURL = "https://pypi.org"
+
+// pypiNameRegex matches the names of Python packages.
+var pypiNameRegex = regexp.MustCompile(`^[A-Za-z0-9]([A-Za-z0-9._-]*[A-Za-z0-9])?$`)
+
+// pypiSourceLabels are the project URL labels that usually point to the source repository, in order of preference.
+// Labels are compared in lowercase and without spaces, dashes or underscores.
+//
+//nolint:gochecknoglobals
+var pypiSourceLabels = []string{"source", "sourcecode", "repository", "code", "github", "homepage"}
+
+// PyPI links Python packages to the release of their new version in the repository listed in their project URLs, as
+// served by the JSON API of the package index at its base URL.
+type PyPI struct {
+	registry
+}
+
+// NewPyPI returns a PyPI that queries the package index at baseURL, which is DefaultPyPIURL if empty.
+func NewPyPI(baseURL string, opts ...RegistryOptionFunc) PyPI {
+	if baseURL == "" {
+		baseURL = DefaultPyPIURL
+	}
+
+	return PyPI{registry: newRegistry("PyPI", baseURL, opts...)}
+}
+
+// pypiProject is the response of the index to `/pypi/$project/json`.
+type pypiProject struct {
+	Info struct {
+		HomePage    string            `json:"home_page"`
+		ProjectURLs map[string]string `json:"project_urls"`
+	} `json:"info"`
+}
+
+func (p PyPI) Map(dep changelog.Dependency) string {
+	if !pypiNameRegex.MatchString(dep.Name) {
+		log.Debugf("PyPI mapper: Dependency %q is not a Python package name.", dep.Name)
+		return ""
+	}
+
+	return p.link(dep, func() (string, string, error) {
+		return p.resolve(dep)
+	})
+}
+
+func (p PyPI) resolve(dep changelog.Dependency) (string, string, error) {
+	project := pypiProject{}
+	if err := p.getJSON(fmt.Sprintf("%s/pypi/%s/json", p.baseURL, dep.Name), nil, &project); err != nil {
+		return "", "", err
+	}
+
+	byLabel := map[string]string{}
+	for label, url := range project.Info.ProjectURLs {
+		byLabel[strings.NewReplacer(" ", "", "-", "", "_", "").Replace(strings.ToLower(label))] = url
+	}
+
+	for _, label := range pypiSourceLabels {
+		if _, _, found := githubRepo(byLabel[label]); found {
+			return byLabel[label], "", nil
+		}
+	}
+
+	// Any other project URL pointing to GitHub, such as the changelog or the issue tracker, is in the repository.
+	urls := make([]string, 0, len(project.Info.ProjectURLs)+1)
+	for _, url := range project.Info.ProjectURLs {
+		urls = append(urls, url)
+	}
+	sort.Strings(urls)
+
+	for _, url := range append([]string{project.Info.HomePage}, urls...) {
+		if _, _, found := githubRepo(url); found {
+			return url, "", nil
+		}
+	}
+
+	return "", "", fmt.Errorf("%w: %q lists no GitHub repository", ErrRegistryNotFound, dep.Name)
+}
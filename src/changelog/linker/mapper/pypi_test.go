@@ -0,0 +1,48 @@
+package mapper_test
+
+import (
+	"testing"
+
+	"github.com/Masterminds/semver"
+	"github.com/newrelic/release-toolkit/src/changelog"
+	"github.com/newrelic/release-toolkit/src/changelog/linker/mapper"
+)
+
+func TestPyPI_Map(t *testing.T) {
+	t.Parallel()
+
+	server := registryServer(t, map[string]string{
+		"/pypi/requests/json": `{"info": {"home_page": "https://requests.readthedocs.io", "project_urls": {
+			"Documentation": "https://requests.readthedocs.io",
+			"Source": "https://github.com/psf/requests"
+		}}}`,
+		"/pypi/source-code/json": `{"info": {"project_urls": {
+			"Homepage": "https://github.com/owner/homepage",
+			"Source Code": "https://github.com/owner/source-code/"
+		}}}`,
+		"/pypi/changelog-only/json": `{"info": {"project_urls": {
+			"Changelog": "https://github.com/owner/changelog-only/blob/main/CHANGELOG.md"
+		}}}`,
+		"/pypi/home-page/json":  `{"info": {"home_page": "https://github.com/owner/home-page", "project_urls": null}}`,
+		"/pypi/not-github/json": `{"info": {"home_page": "https://example.com", "project_urls": {"Source": "https://gitlab.com/owner/repo"}}}`,
+	})
+	pypi := mapper.NewPyPI(server.URL)
+
+	for _, tc := range []struct {
+		name     string
+		expected string
+	}{
+		{name: "requests", expected: "https://github.com/psf/requests/releases/tag/2.28.1"},
+		{name: "source-code", expected: "https://github.com/owner/source-code/releases/tag/2.28.1"},
+		{name: "changelog-only", expected: "https://github.com/owner/changelog-only/releases/tag/2.28.1"},
+		{name: "home-page", expected: "https://github.com/owner/home-page/releases/tag/2.28.1"},
+		{name: "not-github", expected: ""},
+		{name: "unknown", expected: ""},
+		{name: "@scoped/name", expected: ""},
+	} {
+		dep := changelog.Dependency{Name: tc.name, To: semver.MustParse("2.28.1")}
+		if actual := pypi.Map(dep); actual != tc.expected {
+			t.Errorf("Expected %q to be linked to %q, got %q", tc.name, tc.expected, actual)
+		}
+	}
+}
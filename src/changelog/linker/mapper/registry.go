@@ -0,0 +1,171 @@
+package mapper
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/newrelic/release-toolkit/src/changelog"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	registryTimeoutSeconds = 5
+	// maxRegistryResponseBytes caps the size of registry responses, as some of them, like npm packuments or OCI
+	// configs, can be arbitrarily large and only a few fields are needed.
+	maxRegistryResponseBytes = 16 << 20
+)
+
+var (
+	ErrRegistryNotFound = errors.New("package not found in registry")
+	ErrRegistryStatus   = errors.New("unexpected status code from registry")
+)
+
+// githubRepoRegex matches the URLs, in the many forms package registries store them, of GitHub repositories, capturing
+// the owner and the repository name. E.g. `git+https://github.com/org/repo.git`, `git@github.com:org/repo`,
+// `https://github.com/org/repo/tree/main/sub`.
+var githubRepoRegex = regexp.MustCompile(`^(?:[a-z+]+://)?(?:[^@/]+@)?(?:www\.)?github\.com[/:]([\w.-]+)/([\w.-]+?)(?:\.git)?(?:[/#?].*)?$`)
+
+// RegistryOptionFunc configures the mappers that look up dependencies in package registries.
+type RegistryOptionFunc func(r *registry)
+
+// RegistryHTTPClient sets the client used to perform requests to the registry.
+func RegistryHTTPClient(client *http.Client) RegistryOptionFunc {
+	return func(r *registry) {
+		r.client = client
+	}
+}
+
+// registry holds what is common to the mappers that resolve the source repository of a dependency from the package
+// registry of its ecosystem, and then link to the GitHub release of the new version in that repository.
+type registry struct {
+	name    string
+	baseURL string
+	client  *http.Client
+}
+
+func newRegistry(name, baseURL string, opts ...RegistryOptionFunc) registry {
+	r := registry{
+		name:    name,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		client:  &http.Client{Timeout: registryTimeoutSeconds * time.Second},
+	}
+
+	for _, opt := range opts {
+		opt(&r)
+	}
+
+	return r
+}
+
+// get performs a GET request to url with the given headers, returning the response if its status is 200,
+// or the error from checkStatus otherwise. The caller must close the body of the returned response.
+func (r registry) get(url string, headers map[string]string) (*http.Response, error) {
+	resp, err := r.do(url, headers)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkStatus(url, resp); err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// do performs a GET request to url with the given headers, returning the response whatever its status is.
+func (r registry) do(url string, headers map[string]string) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil) //nolint:noctx
+	if err != nil {
+		return nil, fmt.Errorf("building request to %q: %w", url, err)
+	}
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("requesting %q: %w", url, err)
+	}
+
+	return resp, nil
+}
+
+// checkStatus returns ErrRegistryNotFound for 404 responses, and for 401 and 403 ones, which some registries return
+// for packages that do not exist or are not public. ErrRegistryStatus is returned for any other status but 200.
+func checkStatus(url string, resp *http.Response) error {
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusNotFound, http.StatusUnauthorized, http.StatusForbidden:
+		return fmt.Errorf("%w: %q", ErrRegistryNotFound, url)
+	default:
+		return fmt.Errorf("%w: %q returned %d", ErrRegistryStatus, url, resp.StatusCode)
+	}
+}
+
+// getJSON performs a GET request to url and decodes the JSON response into v.
+func (r registry) getJSON(url string, headers map[string]string, v interface{}) error {
+	resp, err := r.get(url, headers)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	err = json.NewDecoder(io.LimitReader(resp.Body, maxRegistryResponseBytes)).Decode(v)
+	if err != nil {
+		return fmt.Errorf("decoding response from %q: %w", url, err)
+	}
+
+	return nil
+}
+
+// link returns the link to the release of dep in the repository returned by resolve, which returns the URL of the
+// source repository and the tag of the release, or an empty tag to use the version of dep. An empty string is
+// returned if dep is not in the registry, or if its repository is not hosted on GitHub.
+func (r registry) link(dep changelog.Dependency, resolve func() (string, string, error)) string {
+	if dep.To == nil || dep.To.Original() == "" {
+		log.Debugf("%s mapper: Dependency %q does not have a release version.", r.name, dep.Name)
+		return ""
+	}
+
+	repoURL, tag, err := resolve()
+	if errors.Is(err, ErrRegistryNotFound) {
+		log.Debugf("%s mapper: Dependency %q was not found in %s: %v", r.name, dep.Name, r.baseURL, err)
+		return ""
+	}
+	if err != nil {
+		log.Errorf("%s mapper: Error looking up %q in %s: %v", r.name, dep.Name, r.baseURL, err)
+		return ""
+	}
+
+	owner, repo, found := githubRepo(repoURL)
+	if !found {
+		log.Debugf("%s mapper: Source repository %q of %q is not hosted on GitHub.", r.name, repoURL, dep.Name)
+		return ""
+	}
+
+	if tag == "" {
+		tag = dep.To.Original()
+	}
+
+	log.Infof("Linking changelog for %q from its source repository %q in %s", dep.Name, repoURL, r.name)
+
+	return fmt.Sprintf(ghChangelogTemplate, owner, repo, tag)
+}
+
+// githubRepo returns the owner and name of the GitHub repository repoURL points to.
+func githubRepo(repoURL string) (string, string, bool) {
+	matches := githubRepoRegex.FindStringSubmatch(strings.TrimSpace(repoURL))
+	if matches == nil {
+		return "", "", false
+	}
+
+	return matches[1], matches[2], true
+}
@@ -0,0 +1,53 @@
+package mapper_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Masterminds/semver"
+	"github.com/newrelic/release-toolkit/src/changelog"
+	"github.com/newrelic/release-toolkit/src/changelog/linker/mapper"
+)
+
+// registryServer starts a stand-in registry that replies to each path in routes with its body, and with 404 to any
+// other path. Query strings are ignored.
+func registryServer(t *testing.T, routes map[string]string) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, found := routes[r.URL.Path]
+		if !found {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestRegistry_Errors(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/broken/1.0.0":
+			w.WriteHeader(http.StatusInternalServerError)
+		case "/garbage/1.0.0":
+			_, _ = w.Write([]byte("<html>"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	npm := mapper.NewNpm(server.URL)
+	for _, name := range []string{"broken", "garbage", "missing"} {
+		if link := npm.Map(changelog.Dependency{Name: name, To: semver.MustParse("1.0.0")}); link != "" {
+			t.Errorf("Expected no link for %q, got %q", name, link)
+		}
+	}
+}
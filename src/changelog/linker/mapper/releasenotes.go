@@ -0,0 +1,175 @@
+package mapper
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/Masterminds/semver"
+	"github.com/newrelic/release-toolkit/src/changelog"
+	log "github.com/sirupsen/logrus"
+)
+
+// DefaultGithubAPIURL is the URL of the GitHub REST API.
+const DefaultGithubAPIURL = "https://api.github.com"
+
+const (
+	releasesPerPage = 100
+	// maxReleasesPages caps the number of pages of releases fetched for a repository, as releases are listed by
+	// creation date and old versions may appear in any page for repositories maintaining several release lines.
+	maxReleasesPages = 10
+)
+
+// GithubReleases fetches the notes of the releases of a dependency after its From version and up to its To version
+// from the releases API of GitHub, or of any forge implementing it, such as GitHub Enterprise Server or Gitea.
+// The repository is taken from the changelog link of the dependency if it points to github.com or to the host of the
+// API, or from its name otherwise, e.g. `github.com/org/repo`. In the first case, tags are assumed to be named as the
+// one in the changelog link, so only releases tagged like `chart-name-1.3.0` are fetched for `chart-name-1.4.0`.
+// Drafts, releases without notes, and prereleases other than the To version are skipped.
+type GithubReleases struct {
+	registry
+	hosts map[string]bool
+	token string
+}
+
+// NewGithubReleases returns a GithubReleases that queries the API at baseURL, which is DefaultGithubAPIURL if empty,
+// authenticating with token if it is not empty.
+func NewGithubReleases(baseURL, token string, opts ...RegistryOptionFunc) GithubReleases {
+	if baseURL == "" {
+		baseURL = DefaultGithubAPIURL
+	}
+
+	g := GithubReleases{
+		registry: newRegistry("Github releases", baseURL, opts...),
+		hosts:    map[string]bool{"github.com": true},
+		token:    token,
+	}
+
+	// The API of GitHub Enterprise Server and Gitea is served on the same host as the repositories.
+	if u, err := url.Parse(baseURL); err == nil && u.Host != "" && u.Host != "api.github.com" {
+		g.hosts[strings.ToLower(u.Host)] = true
+	}
+
+	return g
+}
+
+// githubRelease holds the fields of a release returned by the releases API that are relevant to embed its notes.
+type githubRelease struct {
+	TagName    string `json:"tag_name"`
+	Body       string `json:"body"`
+	Draft      bool   `json:"draft"`
+	Prerelease bool   `json:"prerelease"`
+}
+
+func (g GithubReleases) Notes(dep changelog.Dependency) []changelog.ReleaseNote {
+	if dep.From == nil || dep.To == nil || dep.From.Original() == "" || dep.To.Original() == "" {
+		log.Debugf("Github releases: Dependency %q does not have both versions.", dep.Name)
+		return nil
+	}
+
+	repo, prefix, found := g.repo(dep)
+	if !found {
+		log.Debugf("Github releases: Dependency %q is not hosted on %s.", dep.Name, g.baseURL)
+		return nil
+	}
+
+	releases, err := g.releases(repo)
+	if errors.Is(err, ErrRegistryNotFound) {
+		log.Debugf("Github releases: Releases of %q were not found in %s: %v", repo, g.baseURL, err)
+		return nil
+	}
+	if err != nil {
+		log.Errorf("Github releases: Error fetching releases of %q from %s: %v", repo, g.baseURL, err)
+		return nil
+	}
+
+	versions := make(map[string]*semver.Version)
+	var notes []changelog.ReleaseNote
+	for _, release := range releases {
+		body := strings.TrimSpace(release.Body)
+		if release.Draft || body == "" || !strings.HasPrefix(release.TagName, prefix) {
+			continue
+		}
+
+		version, errVersion := semver.NewVersion(strings.TrimPrefix(release.TagName, prefix))
+		if errVersion != nil {
+			continue
+		}
+
+		if !version.GreaterThan(dep.From) || version.GreaterThan(dep.To) {
+			continue
+		}
+
+		if release.Prerelease && !version.Equal(dep.To) {
+			continue
+		}
+
+		versions[release.TagName] = version
+		notes = append(notes, changelog.ReleaseNote{Version: release.TagName, Notes: body})
+	}
+
+	sort.SliceStable(notes, func(i, j int) bool {
+		return versions[notes[i].Version].GreaterThan(versions[notes[j].Version])
+	})
+
+	if len(notes) > 0 {
+		log.Infof("Embedding the notes of %d releases of %q from %s", len(notes), dep.Name, repo)
+	}
+
+	return notes
+}
+
+// releases returns the releases of repo, in the form `owner/name`.
+func (g GithubReleases) releases(repo string) ([]githubRelease, error) {
+	headers := map[string]string{"Accept": "application/vnd.github+json"}
+	if g.token != "" {
+		headers["Authorization"] = "Bearer " + g.token
+	}
+
+	var releases []githubRelease
+	for page := 1; page <= maxReleasesPages; page++ {
+		var pageReleases []githubRelease
+		err := g.getJSON(
+			fmt.Sprintf("%s/repos/%s/releases?per_page=%d&page=%d", g.baseURL, repo, releasesPerPage, page),
+			headers, &pageReleases,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		releases = append(releases, pageReleases...)
+		if len(pageReleases) < releasesPerPage {
+			break
+		}
+	}
+
+	return releases, nil
+}
+
+// repo returns the repository of dep, in the form `owner/name`, and the prefix of its tags, without any leading `v`
+// as both tags with and without it are accepted.
+func (g GithubReleases) repo(dep changelog.Dependency) (string, string, bool) {
+	if u, err := url.Parse(dep.Changelog); err == nil && g.hosts[strings.ToLower(u.Host)] {
+		parts := strings.SplitN(strings.Trim(u.Path, "/"), "/", 3)
+		if len(parts) >= 2 {
+			prefix := ""
+			if len(parts) == 3 && strings.HasPrefix(parts[2], "releases/tag/") {
+				tag := strings.TrimPrefix(parts[2], "releases/tag/")
+				if toVersion := strings.TrimPrefix(dep.To.Original(), "v"); strings.HasSuffix(tag, toVersion) {
+					prefix = strings.TrimSuffix(strings.TrimSuffix(tag, toVersion), "v")
+				}
+			}
+
+			return parts[0] + "/" + parts[1], prefix, true
+		}
+	}
+
+	parts := strings.Split(dep.Name, "/")
+	if len(parts) < 3 || !g.hosts[strings.ToLower(parts[0])] {
+		return "", "", false
+	}
+
+	return parts[1] + "/" + parts[2], "", true
+}
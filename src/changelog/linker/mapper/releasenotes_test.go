@@ -0,0 +1,155 @@
+package mapper_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/Masterminds/semver"
+	"github.com/newrelic/release-toolkit/src/changelog"
+	"github.com/newrelic/release-toolkit/src/changelog/linker/mapper"
+)
+
+type fakeRelease struct {
+	TagName    string `json:"tag_name"`
+	Body       string `json:"body"`
+	Draft      bool   `json:"draft,omitempty"`
+	Prerelease bool   `json:"prerelease,omitempty"`
+}
+
+// releasesServer starts a stand-in releases API serving the releases of each repository in repos, 100 per page, and
+// replying with 401 to requests without the token.
+func releasesServer(t *testing.T, token string, repos map[string][]fakeRelease) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer "+token {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		var repo string
+		var page int
+		if _, err := fmt.Sscanf(r.URL.Path, "/repos/%s", &repo); err != nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = fmt.Sscanf(r.URL.Query().Get("page"), "%d", &page)
+
+		releases, found := repos[repo]
+		if !found || r.URL.Query().Get("per_page") != "100" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		start, end := (page-1)*100, page*100
+		if start > len(releases) {
+			start = len(releases)
+		}
+		if end > len(releases) {
+			end = len(releases)
+		}
+
+		_ = json.NewEncoder(w).Encode(releases[start:end])
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+//nolint:funlen
+func TestGithubReleases_Notes(t *testing.T) {
+	t.Parallel()
+
+	manyReleases := []fakeRelease{{TagName: "v2.0.0", Body: "Latest"}}
+	for i := 100; i > 0; i-- {
+		manyReleases = append(manyReleases, fakeRelease{TagName: fmt.Sprintf("v1.0.%d", i), Body: fmt.Sprintf("Patch %d", i)})
+	}
+
+	server := releasesServer(t, "secret", map[string][]fakeRelease{
+		"newrelic/nri-kubernetes/releases": {
+			{TagName: "newrelic-infrastructure-3.2.0", Body: "Not yet"},
+			{TagName: "nri-kubernetes-3.1.0", Body: "Other chart"},
+			{TagName: "newrelic-infrastructure-3.1.0", Body: "## Changes\n\n- Newest\n"},
+			{TagName: "newrelic-infrastructure-3.1.0-rc.1", Body: "Prerelease", Prerelease: true},
+			{TagName: "newrelic-infrastructure-3.0.1", Body: "Middle"},
+			{TagName: "newrelic-infrastructure-3.0.2", Body: "Draft", Draft: true},
+			{TagName: "newrelic-infrastructure-3.0.3", Body: "  "},
+			{TagName: "newrelic-infrastructure-3.0.0", Body: "Already released"},
+		},
+		"spf13/viper/releases": {
+			{TagName: "v1.2.0", Body: "Viper 1.2.0"},
+			{TagName: "1.1.0", Body: "Viper 1.1.0"},
+			{TagName: "v1.0.0", Body: "Viper 1.0.0"},
+		},
+		"org/many/releases": manyReleases,
+	})
+
+	releases := mapper.NewGithubReleases(server.URL+"/", "secret")
+
+	for _, tc := range []struct {
+		name     string
+		dep      changelog.Dependency
+		expected []changelog.ReleaseNote
+	}{
+		{
+			name: "Tag_Prefix_From_Changelog",
+			dep: changelog.Dependency{
+				Name:      "newrelic-infrastructure",
+				From:      semver.MustParse("3.0.0"),
+				To:        semver.MustParse("3.1.0"),
+				Changelog: "https://github.com/newrelic/nri-kubernetes/releases/tag/newrelic-infrastructure-3.1.0",
+			},
+			expected: []changelog.ReleaseNote{
+				{Version: "newrelic-infrastructure-3.1.0", Notes: "## Changes\n\n- Newest"},
+				{Version: "newrelic-infrastructure-3.0.1", Notes: "Middle"},
+			},
+		},
+		{
+			name: "Repository_From_Name",
+			dep:  changelog.Dependency{Name: "github.com/spf13/viper", From: semver.MustParse("v1.0.0"), To: semver.MustParse("v1.2.0")},
+			expected: []changelog.ReleaseNote{
+				{Version: "v1.2.0", Notes: "Viper 1.2.0"},
+				{Version: "1.1.0", Notes: "Viper 1.1.0"},
+			},
+		},
+		{
+			name: "Paginated",
+			dep:  changelog.Dependency{Name: "github.com/org/many", From: semver.MustParse("v1.0.1"), To: semver.MustParse("v1.0.2")},
+			expected: []changelog.ReleaseNote{
+				{Version: "v1.0.2", Notes: "Patch 2"},
+			},
+		},
+		{
+			name: "Unknown_Repository",
+			dep:  changelog.Dependency{Name: "github.com/org/missing", From: semver.MustParse("v1.0.0"), To: semver.MustParse("v1.2.0")},
+		},
+		{
+			name: "Not_On_Github",
+			dep:  changelog.Dependency{Name: "gitlab.com/org/repo", From: semver.MustParse("v1.0.0"), To: semver.MustParse("v1.2.0")},
+		},
+		{
+			name: "Unknown_From",
+			dep:  changelog.Dependency{Name: "github.com/spf13/viper", To: semver.MustParse("v1.2.0")},
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			if notes := releases.Notes(tc.dep); !reflect.DeepEqual(notes, tc.expected) {
+				t.Fatalf("Expected notes %v, got %v", tc.expected, notes)
+			}
+		})
+	}
+
+	// Requests without the token are rejected by the server.
+	unauthenticated := mapper.NewGithubReleases(server.URL, "")
+	dep := changelog.Dependency{Name: "github.com/spf13/viper", From: semver.MustParse("v1.0.0"), To: semver.MustParse("v1.2.0")}
+	if notes := unauthenticated.Notes(dep); notes != nil {
+		t.Fatalf("Expected no notes without token, got %v", notes)
+	}
+}
@@ -0,0 +1,382 @@
+package renderer
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/gomarkdown/markdown"
+	"github.com/newrelic/release-toolkit/src/changelog"
+)
+
+// Formatter writes the data of a parsed changelog to a writer in a particular format.
+type Formatter interface {
+	Format(w io.Writer, data Data) error
+}
+
+// Names of the available formats, as accepted by FormatterFor.
+const (
+	MarkdownFormat = "markdown"
+	HTMLFormat     = "html"
+	TextFormat     = "text"
+	JSONFormat     = "json"
+	SlackFormat    = "slack"
+)
+
+var ErrFormatNotValid = errors.New("format not valid")
+
+// FormatterFor returns the Formatter for the given format name.
+func FormatterFor(name string) (Formatter, error) {
+	switch name {
+	case MarkdownFormat, "":
+		return Markdown{}, nil
+	case HTMLFormat:
+		return HTML{}, nil
+	case TextFormat:
+		return Text{}, nil
+	case JSONFormat:
+		return JSON{}, nil
+	case SlackFormat:
+		return Slack{}, nil
+	default:
+		return nil, fmt.Errorf("%w: %q, must be one of %s, %s, %s, %s or %s",
+			ErrFormatNotValid, name, MarkdownFormat, HTMLFormat, TextFormat, JSONFormat, SlackFormat)
+	}
+}
+
+// Section is a list of entries of the same type, as returned by Data.SectionList.
+type Section struct {
+	Type    changelog.EntryType
+	Title   string
+	Entries []Stringer
+}
+
+// sectionOrder holds the order and plain-text titles in which sections are rendered by all formatters.
+//
+//nolint:gochecknoglobals
+var sectionOrder = []struct {
+	entryType changelog.EntryType
+	title     string
+}{
+	{entryType: changelog.TypeBreaking, title: "Breaking changes"},
+	{entryType: changelog.TypeSecurity, title: "Security notices"},
+	{entryType: changelog.TypeEnhancement, title: "Enhancements"},
+	{entryType: changelog.TypeBugfix, title: "Bug fixes"},
+	{entryType: changelog.TypeDependency, title: "Dependencies"},
+}
+
+// SectionList returns the non-empty sections of the changelog, in the order they should be rendered.
+func (d Data) SectionList() []Section {
+	sections := make([]Section, 0, len(sectionOrder))
+	for _, so := range sectionOrder {
+		entries := d.Sections[string(so.entryType)]
+		if len(entries) == 0 {
+			continue
+		}
+
+		sections = append(sections, Section{Type: so.entryType, Title: so.title, Entries: entries})
+	}
+
+	return sections
+}
+
+// header returns the version and date, if any, as they are shown in the title of the changelog.
+func (d Data) header() string {
+	if d.Version == "" {
+		return ""
+	}
+
+	if d.Date == "" {
+		return d.Version
+	}
+
+	return d.Version + " - " + d.Date
+}
+
+// Markdown renders the changelog using a Go template, producing markdown by default.
+type Markdown struct {
+	// Template overrides the default markdown template. See Renderer.Template.
+	Template string
+}
+
+func (m Markdown) Format(w io.Writer, data Data) error {
+	tplText := markdownTemplate
+	if m.Template != "" {
+		tplText = m.Template
+	}
+
+	tpl, err := template.New("changelog").Funcs(FuncMap()).Parse(strings.TrimSpace(tplText))
+	if err != nil {
+		return fmt.Errorf("parsing template: %w", err)
+	}
+
+	// For templates to be sane and readable, we need to put spaces between sections _after_ them. This comes with the
+	// problem of the last section of the doc also printing those spaces, leading to two empty newlines.
+	// As we need to chomp those newlines, we must write to an intermediate buffer, trim it, and then copy back to the
+	// supplied writer.
+	buf := &strings.Builder{}
+	err = tpl.Execute(buf, data)
+	if err != nil {
+		return fmt.Errorf("populating template: %w", err)
+	}
+
+	_, err = fmt.Fprint(w, strings.TrimSpace(buf.String()))
+	if err != nil {
+		return fmt.Errorf("writing output to writer: %w", err)
+	}
+
+	return nil
+}
+
+// HTML renders the default markdown layout and converts it to an HTML fragment.
+type HTML struct{}
+
+func (HTML) Format(w io.Writer, data Data) error {
+	md := &bytes.Buffer{}
+	err := Markdown{}.Format(md, data)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(bytes.TrimSpace(markdown.ToHTML(md.Bytes(), nil, nil)))
+	if err != nil {
+		return fmt.Errorf("writing output to writer: %w", err)
+	}
+
+	return nil
+}
+
+// Text renders the changelog as plain text, with sections titled by their name followed by a colon.
+type Text struct{}
+
+func (Text) Format(w io.Writer, data Data) error {
+	var blocks []string
+
+	if header := data.header(); header != "" {
+		blocks = append(blocks, header)
+	}
+
+	if data.Notes != "" {
+		blocks = append(blocks, stripLinks(data.Notes))
+	}
+
+	for _, section := range data.SectionList() {
+		lines := []string{section.Title + ":"}
+		for _, entry := range section.Entries {
+			lines = append(lines, "- "+stripLinks(entry.String()))
+		}
+
+		blocks = append(blocks, strings.Join(lines, "\n"))
+	}
+
+	_, err := fmt.Fprint(w, strings.Join(blocks, "\n\n"))
+	if err != nil {
+		return fmt.Errorf("writing output to writer: %w", err)
+	}
+
+	return nil
+}
+
+// JSON renders the changelog as a JSON document, including both the one-line text of each entry and its structured
+// fields.
+type JSON struct{}
+
+type jsonChangelog struct {
+	Version  string        `json:"version,omitempty"`
+	Date     string        `json:"date,omitempty"`
+	Notes    string        `json:"notes,omitempty"`
+	Sections []jsonSection `json:"sections"`
+}
+
+type jsonSection struct {
+	Type    string      `json:"type"`
+	Title   string      `json:"title"`
+	Entries []jsonEntry `json:"entries"`
+}
+
+type jsonEntry struct {
+	Text         string            `json:"text"`
+	Message      string            `json:"message,omitempty"`
+	Authors      []string          `json:"authors,omitempty"`
+	PR           string            `json:"pr,omitempty"`
+	Commit       string            `json:"commit,omitempty"`
+	Scope        string            `json:"scope,omitempty"`
+	Name         string            `json:"name,omitempty"`
+	From         string            `json:"from,omitempty"`
+	To           string            `json:"to,omitempty"`
+	Changelog    string            `json:"changelog,omitempty"`
+	Compare      string            `json:"compare,omitempty"`
+	ReleaseNotes []jsonReleaseNote `json:"releaseNotes,omitempty"`
+}
+
+type jsonReleaseNote struct {
+	Version string `json:"version"`
+	Notes   string `json:"notes"`
+}
+
+func (JSON) Format(w io.Writer, data Data) error {
+	doc := jsonChangelog{
+		Version:  data.Version,
+		Date:     data.Date,
+		Notes:    data.Notes,
+		Sections: []jsonSection{},
+	}
+
+	for _, section := range data.SectionList() {
+		js := jsonSection{Type: string(section.Type), Title: section.Title}
+		for _, entry := range section.Entries {
+			js.Entries = append(js.Entries, newJSONEntry(entry))
+		}
+
+		doc.Sections = append(doc.Sections, js)
+	}
+
+	err := writeJSON(w, doc)
+	if err != nil {
+		return fmt.Errorf("encoding changelog as json: %w", err)
+	}
+
+	return nil
+}
+
+func newJSONEntry(s Stringer) jsonEntry {
+	je := jsonEntry{Text: s.String()}
+
+	switch e := s.(type) {
+	case changelog.Entry:
+		je.Message = e.Message
+		je.Authors = e.Meta.Authors
+		je.PR = e.Meta.PR
+		je.Commit = e.Meta.Commit
+		je.Scope = e.Meta.Scope
+	case changelog.Dependency:
+		je.Name = e.Name
+		je.Changelog = e.Changelog
+		je.Compare = e.Compare
+		for _, note := range e.ReleaseNotes {
+			je.ReleaseNotes = append(je.ReleaseNotes, jsonReleaseNote{Version: note.Version, Notes: note.Notes})
+		}
+		if e.From != nil {
+			je.From = e.From.Original()
+		}
+		if e.To != nil {
+			je.To = e.To.Original()
+		}
+	}
+
+	return je
+}
+
+// Slack renders the changelog as a Slack Block Kit message, which can be posted as is to the Slack API or to an
+// incoming webhook.
+type Slack struct{}
+
+// slackTextLimit is the maximum length of the text of a section block, as documented by Slack.
+const slackTextLimit = 3000
+
+type slackMessage struct {
+	Blocks []slackBlock `json:"blocks"`
+}
+
+type slackBlock struct {
+	Type string     `json:"type"`
+	Text *slackText `json:"text,omitempty"`
+}
+
+type slackText struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+func (Slack) Format(w io.Writer, data Data) error {
+	msg := slackMessage{Blocks: []slackBlock{}}
+
+	if header := data.header(); header != "" {
+		msg.Blocks = append(msg.Blocks, slackBlock{Type: "header", Text: &slackText{Type: "plain_text", Text: header}})
+	}
+
+	if data.Notes != "" {
+		msg.Blocks = append(msg.Blocks, slackSections(strings.Split(slackLinks(data.Notes), "\n"))...)
+	}
+
+	for _, section := range data.SectionList() {
+		lines := []string{"*" + section.Title + "*"}
+		for _, entry := range section.Entries {
+			lines = append(lines, "• "+slackLinks(entry.String()))
+		}
+
+		msg.Blocks = append(msg.Blocks, slackSections(lines)...)
+	}
+
+	err := writeJSON(w, msg)
+	if err != nil {
+		return fmt.Errorf("encoding changelog as slack blocks: %w", err)
+	}
+
+	return nil
+}
+
+// slackSections joins lines into as many mrkdwn section blocks as needed to stay within slackTextLimit.
+func slackSections(lines []string) []slackBlock {
+	var blocks []slackBlock
+	current := &strings.Builder{}
+
+	flush := func() {
+		if current.Len() == 0 {
+			return
+		}
+		blocks = append(blocks, slackBlock{Type: "section", Text: &slackText{Type: "mrkdwn", Text: current.String()}})
+		current.Reset()
+	}
+
+	for _, line := range lines {
+		if current.Len() > 0 && current.Len()+len(line)+1 > slackTextLimit {
+			flush()
+		}
+		if current.Len() > 0 {
+			current.WriteString("\n")
+		}
+		current.WriteString(line)
+	}
+	flush()
+
+	return blocks
+}
+
+// writeJSON writes v as indented JSON without escaping HTML characters, which would mangle links, and without the
+// trailing newline added by json.Encoder so the output is consistent with other formatters.
+func writeJSON(w io.Writer, v interface{}) error {
+	buf := &bytes.Buffer{}
+	enc := json.NewEncoder(buf)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+
+	err := enc.Encode(v)
+	if err != nil {
+		return fmt.Errorf("marshalling json: %w", err)
+	}
+
+	_, err = w.Write(bytes.TrimSpace(buf.Bytes()))
+	if err != nil {
+		return fmt.Errorf("writing output to writer: %w", err)
+	}
+
+	return nil
+}
+
+var mdLinkRegex = regexp.MustCompile(`\[([^\]]*)\]\(([^)\s]+)\)`)
+
+// slackLinks rewrites markdown links to the `<url|text>` syntax used by Slack.
+func slackLinks(s string) string {
+	return mdLinkRegex.ReplaceAllString(s, "<$2|$1>")
+}
+
+// stripLinks rewrites markdown links to `text: url`.
+func stripLinks(s string) string {
+	return mdLinkRegex.ReplaceAllString(s, "$1: $2")
+}
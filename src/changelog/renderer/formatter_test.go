@@ -0,0 +1,220 @@
+package renderer_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Masterminds/semver"
+	"github.com/google/go-cmp/cmp"
+	"github.com/newrelic/release-toolkit/src/changelog"
+	"github.com/newrelic/release-toolkit/src/changelog/renderer"
+)
+
+//nolint:funlen
+func TestRenderer_Render_Formats(t *testing.T) {
+	t.Parallel()
+
+	ch := changelog.Changelog{
+		Notes: "Read the [docs](https://example.com/docs).",
+		Changes: []changelog.Entry{
+			{
+				Type:    changelog.TypeBugfix,
+				Message: "Something was fixed",
+				Meta:    changelog.EntryMeta{Authors: []string{"@roobre"}, Commit: "abad1dea"},
+			},
+			{
+				Type:    changelog.TypeEnhancement,
+				Message: "Exciting new feature!",
+				Meta:    changelog.EntryMeta{PR: "#2", Scope: "cli"},
+			},
+		},
+		Dependencies: []changelog.Dependency{
+			{Name: "foobar", To: semver.MustParse("1.0.0")},
+			{
+				Name:      "foobar",
+				From:      semver.MustParse("1.0.0"),
+				To:        semver.MustParse("1.1.0"),
+				Changelog: "https://github.com/foo/bar/releases/tag/v1.1.0",
+			},
+		},
+	}
+
+	for _, tc := range []struct {
+		format   string
+		expected string
+	}{
+		{
+			format: renderer.HTMLFormat,
+			expected: strings.TrimSpace(`
+<h2>v1.2.3 - 1993-09-21</h2>
+
+<p>Read the <a href="https://example.com/docs">docs</a>.</p>
+
+<h3>🚀 Enhancements</h3>
+
+<ul>
+<li>Exciting new feature! (#2)</li>
+</ul>
+
+<h3>🐞 Bug fixes</h3>
+
+<ul>
+<li>Something was fixed, by @roobre (abad1dea)</li>
+</ul>
+
+<h3>⛓️ Dependencies</h3>
+
+<ul>
+<li>Upgraded foobar from 1.0.0 to 1.1.0 - <a href="https://github.com/foo/bar/releases/tag/v1.1.0">Changelog 🔗</a></li>
+</ul>
+`),
+		},
+		{
+			format: renderer.TextFormat,
+			expected: strings.TrimSpace(`
+v1.2.3 - 1993-09-21
+
+Read the docs: https://example.com/docs.
+
+Enhancements:
+- Exciting new feature! (#2)
+
+Bug fixes:
+- Something was fixed, by @roobre (abad1dea)
+
+Dependencies:
+- Upgraded foobar from 1.0.0 to 1.1.0 - Changelog 🔗: https://github.com/foo/bar/releases/tag/v1.1.0
+`),
+		},
+		{
+			format: renderer.JSONFormat,
+			expected: strings.TrimSpace(`
+{
+  "version": "v1.2.3",
+  "date": "1993-09-21",
+  "notes": "Read the [docs](https://example.com/docs).",
+  "sections": [
+    {
+      "type": "enhancement",
+      "title": "Enhancements",
+      "entries": [
+        {
+          "text": "Exciting new feature! (#2)",
+          "message": "Exciting new feature!",
+          "pr": "#2",
+          "scope": "cli"
+        }
+      ]
+    },
+    {
+      "type": "bugfix",
+      "title": "Bug fixes",
+      "entries": [
+        {
+          "text": "Something was fixed, by @roobre (abad1dea)",
+          "message": "Something was fixed",
+          "authors": [
+            "@roobre"
+          ],
+          "commit": "abad1dea"
+        }
+      ]
+    },
+    {
+      "type": "dependency",
+      "title": "Dependencies",
+      "entries": [
+        {
+          "text": "Upgraded foobar from 1.0.0 to 1.1.0 - [Changelog 🔗](https://github.com/foo/bar/releases/tag/v1.1.0)",
+          "name": "foobar",
+          "from": "1.0.0",
+          "to": "1.1.0",
+          "changelog": "https://github.com/foo/bar/releases/tag/v1.1.0"
+        }
+      ]
+    }
+  ]
+}
+`),
+		},
+		{
+			format: renderer.SlackFormat,
+			expected: strings.TrimSpace(`
+{
+  "blocks": [
+    {
+      "type": "header",
+      "text": {
+        "type": "plain_text",
+        "text": "v1.2.3 - 1993-09-21"
+      }
+    },
+    {
+      "type": "section",
+      "text": {
+        "type": "mrkdwn",
+        "text": "Read the <https://example.com/docs|docs>."
+      }
+    },
+    {
+      "type": "section",
+      "text": {
+        "type": "mrkdwn",
+        "text": "*Enhancements*\n• Exciting new feature! (#2)"
+      }
+    },
+    {
+      "type": "section",
+      "text": {
+        "type": "mrkdwn",
+        "text": "*Bug fixes*\n• Something was fixed, by @roobre (abad1dea)"
+      }
+    },
+    {
+      "type": "section",
+      "text": {
+        "type": "mrkdwn",
+        "text": "*Dependencies*\n• Upgraded foobar from 1.0.0 to 1.1.0 - <https://github.com/foo/bar/releases/tag/v1.1.0|Changelog 🔗>"
+      }
+    }
+  ]
+}
+`),
+		},
+	} {
+		tc := tc
+		t.Run(tc.format, func(t *testing.T) {
+			t.Parallel()
+
+			formatter, err := renderer.FormatterFor(tc.format)
+			if err != nil {
+				t.Fatalf("getting formatter: %v", err)
+			}
+
+			r := renderer.New(&ch)
+			r.Next = semver.MustParse("1.2.3")
+			r.ReleasedOn = brokenWristwatch
+			r.Formatter = formatter
+
+			buf := &strings.Builder{}
+			err = r.Render(buf)
+			if err != nil {
+				t.Fatalf("rendering changelog: %v", err)
+			}
+
+			if diff := cmp.Diff(tc.expected, buf.String()); diff != "" {
+				t.Fatalf("Output is not as expected:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestFormatterFor_Invalid(t *testing.T) {
+	t.Parallel()
+
+	_, err := renderer.FormatterFor("rtf")
+	if !errors.Is(err, renderer.ErrFormatNotValid) {
+		t.Fatalf("Expected ErrFormatNotValid, got %v", err)
+	}
+}
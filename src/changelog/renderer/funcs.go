@@ -0,0 +1,128 @@
+package renderer
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/newrelic/release-toolkit/src/changelog"
+)
+
+const shortHashLength = 7
+
+// ScopeGroup is a list of changelog entries sharing the same scope, as returned by the `groupByScope` template
+// function.
+type ScopeGroup struct {
+	// Scope is the scope shared by all entries in this group. It is empty for entries without scope.
+	Scope   string
+	Entries []Stringer
+}
+
+// FuncMap returns the helper functions available to templates, in addition to the text/template builtins:
+//   - `date LAYOUT TIME` formats a time.Time using a Go time layout, e.g. `{{ date "January 2, 2006" .ReleasedOn }}`.
+//   - `shortHash HASH` returns the first seven characters of a commit hash.
+//   - `prLink REPO_URL PR` returns a markdown link to a pull request, e.g. `[#123](https://github.com/org/repo/pull/123)`.
+//   - `commitLink REPO_URL HASH` returns a markdown link to a commit, using the short hash as text.
+//   - `groupByScope ENTRIES` groups a list of entries, such as `.Sections.enhancement`, by their scope. Groups are
+//     returned as a list of ScopeGroup, in the order their scope first appears, with entries without scope last.
+//   - `releaseNotes ENTRY` returns the upstream release notes of a dependency in a collapsible `<details>` block,
+//     indented to be nested in its list item, or an empty string if it has none. The block must be separated from the
+//     item and from the markdown after it by blank lines.
+func FuncMap() template.FuncMap {
+	return template.FuncMap{
+		"date":         formatDate,
+		"shortHash":    shortHash,
+		"prLink":       prLink,
+		"commitLink":   commitLink,
+		"groupByScope": groupByScope,
+		"releaseNotes": releaseNotes,
+	}
+}
+
+func formatDate(layout string, t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+
+	return t.Format(layout)
+}
+
+func shortHash(hash string) string {
+	if len(hash) <= shortHashLength {
+		return hash
+	}
+
+	return hash[:shortHashLength]
+}
+
+func prLink(repoURL, pr string) string {
+	if pr == "" {
+		return ""
+	}
+
+	number := strings.TrimPrefix(pr, "#")
+	return fmt.Sprintf("[#%s](%s/pull/%s)", number, strings.TrimSuffix(repoURL, "/"), number)
+}
+
+func commitLink(repoURL, hash string) string {
+	if hash == "" {
+		return ""
+	}
+
+	return fmt.Sprintf("[%s](%s/commit/%s)", shortHash(hash), strings.TrimSuffix(repoURL, "/"), hash)
+}
+
+func groupByScope(entries []Stringer) []ScopeGroup {
+	var groups []ScopeGroup
+	var unscoped []Stringer
+	index := map[string]int{}
+
+	for _, e := range entries {
+		entry, isEntry := e.(changelog.Entry)
+		if !isEntry || entry.Meta.Scope == "" {
+			unscoped = append(unscoped, e)
+			continue
+		}
+
+		i, found := index[entry.Meta.Scope]
+		if !found {
+			i = len(groups)
+			index[entry.Meta.Scope] = i
+			groups = append(groups, ScopeGroup{Scope: entry.Meta.Scope})
+		}
+
+		groups[i].Entries = append(groups[i].Entries, e)
+	}
+
+	if len(unscoped) > 0 {
+		groups = append(groups, ScopeGroup{Entries: unscoped})
+	}
+
+	return groups
+}
+
+func releaseNotes(entry Stringer) string {
+	dep, isDependency := entry.(changelog.Dependency)
+	if !isDependency || len(dep.ReleaseNotes) == 0 {
+		return ""
+	}
+
+	lines := []string{"<details>", "<summary>Release notes</summary>", ""}
+	for _, note := range dep.ReleaseNotes {
+		lines = append(lines, "#### "+note.Version, "")
+		lines = append(lines, strings.Split(note.Notes, "\n")...)
+		lines = append(lines, "")
+	}
+	lines = append(lines, "</details>")
+
+	for i, line := range lines {
+		if strings.TrimSpace(line) != "" {
+			lines[i] = "    " + line
+		} else {
+			lines[i] = ""
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -1,14 +1,13 @@
 package renderer
 
 import (
-	"fmt"
 	"io"
 	"strings"
-	"text/template"
 	"time"
 
 	"github.com/Masterminds/semver"
 	"github.com/newrelic/release-toolkit/src/changelog"
+	"github.com/newrelic/release-toolkit/src/version"
 )
 
 // Stringer is anything that can be printed as a list entry on the changelog. changelog.Dependency and changelog.Entry
@@ -24,61 +23,74 @@ type Renderer struct {
 	// If non-nil and Next is non-nil, the level 2 header including the version will also include the date returned by
 	// this function, signifying that the version to which this changelog corresponds was released on said date.
 	ReleasedOn func() time.Time
+	// VersionPrefix is prepended to Next when rendering the version. It defaults to "v".
+	VersionPrefix string
+	// Template is a Go text/template used to render the changelog instead of the default one. It is executed with Data
+	// and has access to the functions returned by FuncMap.
+	Template string
+	// Formatter, if not nil, is used to write the changelog instead of the markdown template. Template is ignored if
+	// Formatter is set.
+	Formatter Formatter
+	// ReleaseNotesFor, if not empty, restricts the dependencies whose upstream release notes are rendered to the ones
+	// with these names. The release notes of all dependencies are rendered otherwise.
+	ReleaseNotesFor []string
 
 	changelog *changelog.Changelog
 }
 
 func New(c *changelog.Changelog) Renderer {
 	return Renderer{
-		changelog: c,
+		VersionPrefix: "v",
+		changelog:     c,
 	}
 }
 
-type parsedChangelog struct {
-	Version  string
-	Date     string
-	Notes    string
+// Data is the object templates are executed with.
+type Data struct {
+	// Version is the prefixed version being released, e.g. `v1.2.3`. It is empty if Renderer.Next is nil.
+	Version string
+	// Next is the version being released, without any prefix. It is nil if Renderer.Next is nil.
+	Next *semver.Version
+	// Date is the release date in YYYY-MM-DD format. It is empty if Renderer.ReleasedOn is nil.
+	Date string
+	// ReleasedOn is the release date, so it can be formatted with the `date` function. It is the zero time if
+	// Renderer.ReleasedOn is nil.
+	ReleasedOn time.Time
+	// Notes is the markdown snippet with the release notes, trimmed.
+	Notes string
+	// Sections maps entry types (breaking, security, enhancement, bugfix and dependency) to the entries of that type.
+	// Entries are changelog.Entry objects, except for dependency which holds deduplicated changelog.Dependency objects.
 	Sections map[string][]Stringer
+	// Changelog is the changelog being rendered.
+	Changelog *changelog.Changelog
 }
 
-// Render writes the markdown representation of a changelog to the specified writer.
+// Render writes the markdown representation of a changelog to the specified writer, or the representation produced
+// by Formatter if it is set.
 func (r Renderer) Render(w io.Writer) error {
-	parsed := r.parse()
-	tpl, err := template.New("changelog").Parse(strings.TrimSpace(markdownTemplate))
-	if err != nil {
-		return fmt.Errorf("internal, parsing template: %w", err)
-	}
-
-	// For templates to be sane and readable, we need to put spaces between sections _after_ them. This comes with the
-	// problem of the last section of the doc also printing those spaces, leading to two empty newlines.
-	// As we need to chomp those newlines, we must write to an intermediate buffer, trim it, and then copy back to the
-	// supplied writer.
-	buf := &strings.Builder{}
-	err = tpl.Execute(buf, parsed)
-	if err != nil {
-		return fmt.Errorf("populating template: %w", err)
+	formatter := r.Formatter
+	if formatter == nil {
+		formatter = Markdown{Template: r.Template}
 	}
 
-	_, err = fmt.Fprint(w, strings.TrimSpace(buf.String()))
-	if err != nil {
-		return fmt.Errorf("writing output to writer: %w", err)
-	}
-
-	return nil
+	return formatter.Format(w, r.parse())
 }
 
-func (r Renderer) parse() parsedChangelog {
-	parsed := parsedChangelog{
-		Notes:    strings.TrimSpace(r.changelog.Notes),
-		Sections: map[string][]Stringer{},
+func (r Renderer) parse() Data {
+	parsed := Data{
+		Notes:     strings.TrimSpace(r.changelog.Notes),
+		Sections:  map[string][]Stringer{},
+		Changelog: r.changelog,
 	}
 
 	if r.Next != nil {
-		parsed.Version = "v" + r.Next.String()
+		parsed.Next = r.Next
+		parsed.Version = r.VersionPrefix + version.Format(r.Next)
 	}
 
 	if r.ReleasedOn != nil {
-		parsed.Date = r.ReleasedOn().Format("2006-01-02")
+		parsed.ReleasedOn = r.ReleasedOn()
+		parsed.Date = parsed.ReleasedOn.Format("2006-01-02")
 	}
 
 	for _, entry := range r.changelog.Changes {
@@ -88,12 +100,31 @@ func (r Renderer) parse() parsedChangelog {
 	deps := deduplicateDependencies(r.changelog.Dependencies)
 
 	for _, dep := range deps {
+		if !r.rendersReleaseNotes(dep.Name) {
+			dep.ReleaseNotes = nil
+		}
+
 		parsed.Sections[string(changelog.TypeDependency)] = append(parsed.Sections[string(changelog.TypeDependency)], dep)
 	}
 
 	return parsed
 }
 
+// rendersReleaseNotes returns whether the release notes of the dependency with the given name should be rendered.
+func (r Renderer) rendersReleaseNotes(name string) bool {
+	if len(r.ReleaseNotesFor) == 0 {
+		return true
+	}
+
+	for _, allowed := range r.ReleaseNotesFor {
+		if allowed == name {
+			return true
+		}
+	}
+
+	return false
+}
+
 // Dependencies are sorted in ascending order. We keep the latest that should be the one with the latest semVer.
 func deduplicateDependencies(dependencies []changelog.Dependency) []changelog.Dependency {
 	dedupDeps := []changelog.Dependency{}
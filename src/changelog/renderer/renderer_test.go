@@ -43,25 +43,25 @@ I am a note!
 						Type:    changelog.TypeBreaking,
 						Message: "Extremely scary breaking change",
 						Meta: changelog.EntryMeta{
-							Author: "@roobre",
-							PR:     "#1",
+							Authors: []string{"@roobre"},
+							PR:      "#1",
 						},
 					},
 					{
 						Type:    changelog.TypeBugfix,
 						Message: "Something was fixed",
 						Meta: changelog.EntryMeta{
-							Author: "@roobre",
-							Commit: "abad1dea",
+							Authors: []string{"@roobre"},
+							Commit:  "abad1dea",
 						},
 					},
 					{
 						Type:    changelog.TypeEnhancement,
 						Message: "Exciting new feature!",
 						Meta: changelog.EntryMeta{
-							Author: "@roobre",
-							PR:     "#69",
-							Commit: "abad1dea",
+							Authors: []string{"@roobre"},
+							PR:      "#69",
+							Commit:  "abad1dea",
 						},
 					},
 					{
@@ -120,25 +120,25 @@ I am a note!
 						Type:    changelog.TypeBreaking,
 						Message: "Extremely scary breaking change",
 						Meta: changelog.EntryMeta{
-							Author: "@roobre",
-							PR:     "#1",
+							Authors: []string{"@roobre"},
+							PR:      "#1",
 						},
 					},
 					{
 						Type:    changelog.TypeBugfix,
 						Message: "Something was fixed",
 						Meta: changelog.EntryMeta{
-							Author: "@roobre",
-							Commit: "abad1dea",
+							Authors: []string{"@roobre"},
+							Commit:  "abad1dea",
 						},
 					},
 					{
 						Type:    changelog.TypeEnhancement,
 						Message: "Exciting new feature!",
 						Meta: changelog.EntryMeta{
-							Author: "@roobre",
-							PR:     "#69",
-							Commit: "abad1dea",
+							Authors: []string{"@roobre"},
+							PR:      "#69",
+							Commit:  "abad1dea",
 						},
 					},
 					{
@@ -193,8 +193,8 @@ I am a note!
 						Type:    changelog.TypeBugfix,
 						Message: "Fixed a bug that was causing everything to explode",
 						Meta: changelog.EntryMeta{
-							Author: "@roobre",
-							PR:     "#1337",
+							Authors: []string{"@roobre"},
+							PR:      "#1337",
 						},
 					},
 				},
@@ -212,6 +212,22 @@ I am a note!
 
 ### ⛓️ Dependencies
 - Updated a totally legit dependency, not malicious at all
+`),
+		},
+		{
+			name:    "Padded_CalVer_Version",
+			date:    brokenWristwatch,
+			version: semver.MustParse("2024.03.1"),
+			changelog: changelog.Changelog{
+				Changes: []changelog.Entry{
+					{Type: changelog.TypeBugfix, Message: "Fixed a bug"},
+				},
+			},
+			expected: strings.TrimSpace(`
+## v2024.03.1 - 1993-09-21
+
+### 🐞 Bug fixes
+- Fixed a bug
 `),
 		},
 		{
@@ -224,8 +240,8 @@ I am a note!
 						Type:    changelog.TypeBugfix,
 						Message: "Fixed a bug that was causing everything to explode",
 						Meta: changelog.EntryMeta{
-							Author: "@roobre",
-							PR:     "#1337",
+							Authors: []string{"@roobre"},
+							PR:      "#1337",
 						},
 					},
 				},
@@ -256,6 +272,35 @@ I am a note!
 
 ### ⛓️ Dependencies
 - Upgraded Test dependency from v1.2.3 to v1.2.4 - [Changelog 🔗](https://foo.bar/baz)
+`),
+		},
+		{
+			name:    "Changelog_Dependencies_With_Compare_Link",
+			date:    brokenWristwatch,
+			version: semver.MustParse("0.0.0"),
+			changelog: changelog.Changelog{
+				Dependencies: []changelog.Dependency{
+					{
+						Name:      "github.com/foo/bar",
+						From:      semver.MustParse("v1.2.3"),
+						To:        semver.MustParse("v1.2.4"),
+						Changelog: "https://github.com/foo/bar/releases/tag/v1.2.4",
+						Compare:   "https://github.com/foo/bar/compare/v1.2.3...v1.2.4",
+					},
+					{
+						Name:    "gitlab.com/foo/baz",
+						From:    semver.MustParse("v0.1.0"),
+						To:      semver.MustParse("v0.2.0"),
+						Compare: "https://gitlab.com/foo/baz/-/compare/v0.1.0...v0.2.0",
+					},
+				},
+			},
+			expected: strings.TrimSpace(`
+## v0.0.0 - 1993-09-21
+
+### ⛓️ Dependencies
+- Upgraded github.com/foo/bar from v1.2.3 to v1.2.4 - [Changelog 🔗](https://github.com/foo/bar/releases/tag/v1.2.4) - [Compare 🔗](https://github.com/foo/bar/compare/v1.2.3...v1.2.4)
+- Upgraded gitlab.com/foo/baz from v0.1.0 to v0.2.0 - [Compare 🔗](https://gitlab.com/foo/baz/-/compare/v0.1.0...v0.2.0)
 `),
 		},
 		{
@@ -267,8 +312,8 @@ I am a note!
 						Type:    changelog.TypeBugfix,
 						Message: "Fixed a bug that was causing everything to explode",
 						Meta: changelog.EntryMeta{
-							Author: "@roobre",
-							PR:     "#1337",
+							Authors: []string{"@roobre"},
+							PR:      "#1337",
 						},
 					},
 				},
@@ -295,8 +340,8 @@ I am a note!
 						Type:    changelog.TypeBugfix,
 						Message: "Fixed a bug that was causing everything to explode",
 						Meta: changelog.EntryMeta{
-							Author: "@roobre",
-							PR:     "#1337",
+							Authors: []string{"@roobre"},
+							PR:      "#1337",
 						},
 					},
 				},
@@ -336,3 +381,226 @@ I am a note!
 		})
 	}
 }
+
+//nolint:funlen
+func TestRenderer_Render_ReleaseNotes(t *testing.T) {
+	t.Parallel()
+
+	notes := []changelog.ReleaseNote{
+		{Version: "v1.2.0", Notes: "## What's changed\n\n- A feature\n\n- A fix"},
+		{Version: "v1.1.0", Notes: "Initial release"},
+	}
+
+	cl := &changelog.Changelog{
+		Dependencies: []changelog.Dependency{
+			{Name: "foo", From: semver.MustParse("v1.0.0"), To: semver.MustParse("v1.2.0"), Changelog: "https://example.com/foo", ReleaseNotes: notes},
+			{Name: "bar", From: semver.MustParse("v1.0.0"), To: semver.MustParse("v1.2.0"), ReleaseNotes: notes},
+			{Name: "baz", To: semver.MustParse("v2.0.0")},
+		},
+	}
+
+	for _, tc := range []struct {
+		name     string
+		allowed  []string
+		expected string
+	}{
+		{
+			name: "All",
+			expected: strings.TrimSpace(`
+### ⛓️ Dependencies
+- Upgraded foo from v1.0.0 to v1.2.0 - [Changelog 🔗](https://example.com/foo)
+
+    <details>
+    <summary>Release notes</summary>
+
+    #### v1.2.0
+
+    ## What's changed
+
+    - A feature
+
+    - A fix
+
+    #### v1.1.0
+
+    Initial release
+
+    </details>
+
+- Upgraded bar from v1.0.0 to v1.2.0
+
+    <details>
+    <summary>Release notes</summary>
+
+    #### v1.2.0
+
+    ## What's changed
+
+    - A feature
+
+    - A fix
+
+    #### v1.1.0
+
+    Initial release
+
+    </details>
+
+- Updated baz to v2.0.0
+`),
+		},
+		{
+			name:    "Allowlist",
+			allowed: []string{"bar"},
+			expected: strings.TrimSpace(`
+### ⛓️ Dependencies
+- Upgraded foo from v1.0.0 to v1.2.0 - [Changelog 🔗](https://example.com/foo)
+- Upgraded bar from v1.0.0 to v1.2.0
+
+    <details>
+    <summary>Release notes</summary>
+
+    #### v1.2.0
+
+    ## What's changed
+
+    - A feature
+
+    - A fix
+
+    #### v1.1.0
+
+    Initial release
+
+    </details>
+
+- Updated baz to v2.0.0
+`),
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := renderer.New(cl)
+			r.ReleaseNotesFor = tc.allowed
+
+			buf := &strings.Builder{}
+			err := r.Render(buf)
+			if err != nil {
+				t.Fatalf("Rendering changelog: %v", err)
+			}
+			if diff := cmp.Diff(tc.expected, buf.String()); diff != "" {
+				t.Fatalf("Output format is not as expected:\n%s", diff)
+			}
+		})
+	}
+}
+
+//nolint:funlen
+func TestRenderer_Render_Template(t *testing.T) {
+	t.Parallel()
+
+	ch := changelog.Changelog{
+		Notes: "Some notes",
+		Changes: []changelog.Entry{
+			{Type: changelog.TypeEnhancement, Message: "Add --verbose", Meta: changelog.EntryMeta{Scope: "cli", PR: "12"}},
+			{Type: changelog.TypeEnhancement, Message: "Support arm64"},
+			{Type: changelog.TypeEnhancement, Message: "Add --quiet", Meta: changelog.EntryMeta{Scope: "cli", Commit: "0b5e1d0c9f3e"}},
+			{Type: changelog.TypeBugfix, Message: "Fix crash", Meta: changelog.EntryMeta{Scope: "api"}},
+		},
+		Dependencies: []changelog.Dependency{
+			{Name: "foobar", To: semver.MustParse("1.2.3")},
+		},
+	}
+
+	for _, tc := range []struct {
+		name     string
+		template string
+		prefix   string
+		expected string
+	}{
+		{
+			name: "Keep_A_Changelog",
+			template: `
+## [{{ .Version }}] - {{ date "2006-01-02" .ReleasedOn }}
+{{ with .Sections.enhancement }}
+### Added
+{{- range . }}
+- {{ .Message }}
+{{- end }}
+{{ end }}
+{{- with .Sections.bugfix }}
+### Fixed
+{{- range . }}
+- {{ .Message }}
+{{- end }}
+{{ end }}`,
+			prefix: "",
+			expected: strings.TrimSpace(`
+## [1.2.0] - 1993-09-21
+
+### Added
+- Add --verbose
+- Support arm64
+- Add --quiet
+
+### Fixed
+- Fix crash
+`),
+		},
+		{
+			name: "Grouped_With_Links",
+			template: `
+# {{ .Version }} ({{ date "January 2, 2006" .ReleasedOn }})
+{{ range groupByScope .Sections.enhancement }}
+#### {{ with .Scope }}{{ . }}{{ else }}Other{{ end }}
+{{- range .Entries }}
+- {{ .Message }}{{ with .Meta.PR }} {{ prLink "https://github.com/org/repo/" . }}{{ end }}
+{{- with .Meta.Commit }} {{ commitLink "https://github.com/org/repo" . }} {{ shortHash . }}{{ end }}
+{{- end }}
+{{ end }}`,
+			prefix: "v",
+			expected: strings.TrimSpace(`
+# v1.2.0 (September 21, 1993)
+
+#### cli
+- Add --verbose [#12](https://github.com/org/repo/pull/12)
+- Add --quiet [0b5e1d0](https://github.com/org/repo/commit/0b5e1d0c9f3e) 0b5e1d0
+
+#### Other
+- Support arm64
+`),
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := renderer.New(&ch)
+			r.ReleasedOn = brokenWristwatch
+			r.Next = semver.MustParse("v1.2.0")
+			r.VersionPrefix = tc.prefix
+			r.Template = tc.template
+
+			buf := &strings.Builder{}
+			if err := r.Render(buf); err != nil {
+				t.Fatalf("Rendering changelog: %v", err)
+			}
+			if diff := cmp.Diff(tc.expected, buf.String()); diff != "" {
+				t.Fatalf("Output format is not as expected:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRenderer_Render_Invalid_Template(t *testing.T) {
+	t.Parallel()
+
+	r := renderer.New(&changelog.Changelog{})
+	r.Template = "{{ .Version "
+
+	if err := r.Render(&strings.Builder{}); err == nil {
+		t.Fatalf("Expected an error rendering an invalid template")
+	}
+}
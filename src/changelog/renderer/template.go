@@ -52,8 +52,16 @@ const markdownTemplate = `{{- if .Version -}}
 
 {{- with .dependency -}}
 ### ⛓️ Dependencies
+{{- $notes := "" }}
 {{- range . }}
+{{- if $notes }}
+{{ end }}
 - {{ . }}
+{{- $notes = releaseNotes . }}
+{{- with $notes }}
+
+{{ . }}
+{{- end }}
 {{- end }}
 
 {{ end }}
@@ -0,0 +1,185 @@
+// Package conventional implements sourcing changelog entries from commits following the
+// [Conventional Commits](https://www.conventionalcommits.org/en/v1.0.0/) specification.
+package conventional
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/newrelic/release-toolkit/src/changelog"
+	"github.com/newrelic/release-toolkit/src/git"
+	log "github.com/sirupsen/logrus"
+)
+
+// depsScope is the scope bots use for dependency bumps. These commits are skipped, as they are already covered by the
+// renovate and dependabot sources, which produce richer changelog.Dependency entries.
+const depsScope = "deps"
+
+// types maps conventional commit types to changelog entry types. Commit types not present here (chore, docs, ci,
+// test, refactor, style, build...) do not produce changelog entries unless they are marked as breaking.
+//
+//nolint:gochecknoglobals
+var types = map[string]changelog.EntryType{
+	"feat":     changelog.TypeEnhancement,
+	"perf":     changelog.TypeEnhancement,
+	"fix":      changelog.TypeBugfix,
+	"security": changelog.TypeSecurity,
+}
+
+var (
+	headerRegex   = regexp.MustCompile(`^(\w+)(?:\(([^)]*)\))?(!)?: (.+)$`)
+	breakingRegex = regexp.MustCompile(`(?m)^BREAKING[ -]CHANGE: (.+)$`)
+	prRegex       = regexp.MustCompile(`(.+) \([#!](\d+)\)$`)
+)
+
+// Source is a changelog.Source that produces changelog entries from conventional commits made since the last version.
+type Source struct {
+	tagsVersionGetter git.TagsVersionGetter
+	commitsGetter     git.CommitsGetter
+	// handles maps lowercase emails to the GitHub handles, without the leading @, authors are credited with.
+	handles map[string]string
+}
+
+type OptionFunc func(s *Source)
+
+// AuthorHandles returns an option that credits authors whose email is a key of handles with the GitHub handle it maps
+// to, e.g. `@alice`, rather than with their name. Emails are matched case-insensitively.
+func AuthorHandles(handles map[string]string) OptionFunc {
+	return func(s *Source) {
+		for email, handle := range handles {
+			s.handles[strings.ToLower(email)] = strings.TrimPrefix(handle, "@")
+		}
+	}
+}
+
+func NewSource(tagsVersionGetter git.TagsVersionGetter, commitsGetter git.CommitsGetter, opts ...OptionFunc) Source {
+	s := Source{
+		tagsVersionGetter: tagsVersionGetter,
+		commitsGetter:     commitsGetter,
+		handles:           map[string]string{},
+	}
+
+	for _, opt := range opts {
+		opt(&s)
+	}
+
+	return s
+}
+
+func (s Source) Changelog() (*changelog.Changelog, error) {
+	lastHash, err := s.tagsVersionGetter.LastVersionHash()
+	if err != nil {
+		return nil, fmt.Errorf("getting last version hash: %w", err)
+	}
+
+	log.Debugf("Listing commits until last tag %q", lastHash)
+	gitCommits, err := s.commitsGetter.Commits(lastHash)
+	if err != nil {
+		return nil, fmt.Errorf("getting commits: %w", err)
+	}
+	if len(gitCommits) == 0 {
+		log.Infof("Conventional commits source did not find any commit since %q", lastHash)
+	}
+
+	var entries []changelog.Entry
+
+	for _, c := range gitCommits {
+		entry, ok := s.entry(c)
+		if !ok {
+			continue
+		}
+
+		entries = append(entries, entry)
+	}
+
+	// Reverse order in which entries appear in changelog, to put the oldest first.
+	// Commits are iterated in a newest-first order.
+	sort.SliceStable(entries, func(i, j int) bool {
+		return j < i
+	})
+
+	return &changelog.Changelog{Changes: entries}, nil
+}
+
+// entry builds a changelog.Entry from a commit, returning false if the commit should not be included in the changelog.
+func (s Source) entry(c git.Commit) (changelog.Entry, bool) {
+	lines := strings.Split(c.Message, "\n")
+	commitLine := strings.TrimSpace(lines[0])
+
+	matches := headerRegex.FindStringSubmatch(commitLine)
+	if len(matches) == 0 {
+		log.Debugf("skipping commit as it does not follow the conventional commits format\n> %q", commitLine)
+		return changelog.Entry{}, false
+	}
+
+	commitType := strings.ToLower(matches[1])
+	scope := matches[2]
+	description := matches[4]
+
+	if strings.EqualFold(scope, depsScope) {
+		log.Debugf("skipping commit as it has the %q scope\n> %q", depsScope, commitLine)
+		return changelog.Entry{}, false
+	}
+
+	var pr string
+	if prMatches := prRegex.FindStringSubmatch(description); len(prMatches) != 0 {
+		description = prMatches[1]
+		pr = prMatches[2]
+	}
+
+	entryType, known := types[commitType]
+
+	// Breaking changes are noted either with a `!` after the type/scope, or with a BREAKING CHANGE footer. In the
+	// latter case, the footer describes the change better than the header, so we use it as the message.
+	body := strings.Join(lines[1:], "\n")
+	if footer := breakingRegex.FindStringSubmatch(body); len(footer) != 0 {
+		entryType, known = changelog.TypeBreaking, true
+		description = strings.TrimSpace(footer[1])
+	} else if matches[3] != "" {
+		entryType, known = changelog.TypeBreaking, true
+	}
+
+	if !known {
+		log.Debugf("skipping commit as type %q does not produce changelog entries\n> %q", commitType, commitLine)
+		return changelog.Entry{}, false
+	}
+
+	return changelog.Entry{
+		Type:    entryType,
+		Message: description,
+		Meta: changelog.EntryMeta{
+			Authors: s.authors(c),
+			PR:      pr,
+			Commit:  c.Hash,
+			Scope:   scope,
+		},
+	}, true
+}
+
+// authors returns how the author and co-authors of c are credited: with their GitHub handle if their email is in
+// handles, or with their name otherwise.
+func (s Source) authors(c git.Commit) []string {
+	var authors []string
+	for _, person := range append([]string{c.Author}, c.CoAuthors...) {
+		if person == "" {
+			continue
+		}
+
+		name, email, ok := git.SplitPerson(person)
+		if !ok {
+			authors = append(authors, person)
+			continue
+		}
+
+		if handle, found := s.handles[strings.ToLower(email)]; found {
+			authors = append(authors, "@"+handle)
+			continue
+		}
+
+		authors = append(authors, name)
+	}
+
+	return authors
+}
@@ -0,0 +1,179 @@
+package conventional_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Masterminds/semver"
+	"github.com/google/go-cmp/cmp"
+	"github.com/newrelic/release-toolkit/src/changelog"
+	"github.com/newrelic/release-toolkit/src/changelog/sources/conventional"
+	"github.com/newrelic/release-toolkit/src/git"
+)
+
+var errRandomError = errors.New("a-random-error")
+
+type tagsVersionGetterMock struct {
+	errRelease error
+}
+
+func (t *tagsVersionGetterMock) Versions() ([]*semver.Version, error) {
+	version := semver.MustParse("v1.2.3")
+	return []*semver.Version{version}, nil
+}
+
+func (t *tagsVersionGetterMock) LastVersionHash() (string, error) {
+	return "", t.errRelease
+}
+
+// commitList is a mocked commit source.
+type commitList []git.Commit
+
+// Commits return the list of commits in reverse order, which is like the real commit getter would return them if
+// the first commit in the slice was committed first.
+func (cl commitList) Commits(_ string) ([]git.Commit, error) {
+	var commits []git.Commit
+	for i := len(cl) - 1; i >= 0; i-- {
+		commits = append(commits, cl[i])
+	}
+
+	return commits, nil
+}
+
+//nolint:funlen
+func TestSource_Changelog(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name        string
+		commits     []git.Commit
+		errVersion  error
+		errExpected error
+		expected    []changelog.Entry
+	}{
+		{
+			name: "Non_Conventional_Commits_Are_Skipped",
+			commits: []git.Commit{
+				{Message: "Non matching"},
+				{Message: "feat:missing space after colon"},
+				{Message: "Merge pull request #12 from newrelic/feature"},
+			},
+		},
+		{
+			name: "Types_Are_Mapped",
+			commits: []git.Commit{
+				{Message: "feat: add a new flag", Hash: "aaa"},
+				{Message: "fix: do not panic on empty input", Hash: "bbb"},
+				{Message: "perf: cache parsed templates", Hash: "ccc"},
+				{Message: "security: sanitize user input", Hash: "ddd"},
+				{Message: "docs: fix typo in readme", Hash: "eee"},
+				{Message: "chore: bump version", Hash: "fff"},
+			},
+			expected: []changelog.Entry{
+				{Type: changelog.TypeEnhancement, Message: "add a new flag", Meta: changelog.EntryMeta{Commit: "aaa"}},
+				{Type: changelog.TypeBugfix, Message: "do not panic on empty input", Meta: changelog.EntryMeta{Commit: "bbb"}},
+				{Type: changelog.TypeEnhancement, Message: "cache parsed templates", Meta: changelog.EntryMeta{Commit: "ccc"}},
+				{Type: changelog.TypeSecurity, Message: "sanitize user input", Meta: changelog.EntryMeta{Commit: "ddd"}},
+			},
+		},
+		{
+			name: "Scope_Author_And_PR_Are_Recorded",
+			commits: []git.Commit{
+				{Message: "feat(api): support pagination (#123)", Hash: "aaa", Author: "Jane Doe <jane@example.com>"},
+				{Message: "FIX(cli): exit with proper code", Hash: "bbb", Author: "roobre"},
+			},
+			expected: []changelog.Entry{
+				{
+					Type:    changelog.TypeEnhancement,
+					Message: "support pagination",
+					Meta:    changelog.EntryMeta{Authors: []string{"Jane Doe"}, PR: "123", Commit: "aaa", Scope: "api"},
+				},
+				{
+					Type:    changelog.TypeBugfix,
+					Message: "exit with proper code",
+					Meta:    changelog.EntryMeta{Authors: []string{"roobre"}, Commit: "bbb", Scope: "cli"},
+				},
+			},
+		},
+		{
+			name: "Breaking_Changes",
+			commits: []git.Commit{
+				{Message: "fix!: remove deprecated endpoint", Hash: "aaa"},
+				{Message: "refactor(config)!: rename keys", Hash: "bbb"},
+				{Message: "feat: new config format\n\nSome details.\n\nBREAKING CHANGE: old config files are no longer read", Hash: "ccc"},
+				{Message: "chore: drop go 1.18\n\nBREAKING-CHANGE: go 1.19 is required", Hash: "ddd"},
+			},
+			expected: []changelog.Entry{
+				{Type: changelog.TypeBreaking, Message: "remove deprecated endpoint", Meta: changelog.EntryMeta{Commit: "aaa"}},
+				{Type: changelog.TypeBreaking, Message: "rename keys", Meta: changelog.EntryMeta{Commit: "bbb", Scope: "config"}},
+				{Type: changelog.TypeBreaking, Message: "old config files are no longer read", Meta: changelog.EntryMeta{Commit: "ccc"}},
+				{Type: changelog.TypeBreaking, Message: "go 1.19 is required", Meta: changelog.EntryMeta{Commit: "ddd"}},
+			},
+		},
+		{
+			name: "Dependency_Scope_Is_Skipped",
+			commits: []git.Commit{
+				{Message: "fix(deps): update module github.com/spf13/viper to v1.12.0 (#42)", Author: "renovate[bot]"},
+				{Message: "feat: something useful", Hash: "aaa"},
+			},
+			expected: []changelog.Entry{
+				{Type: changelog.TypeEnhancement, Message: "something useful", Meta: changelog.EntryMeta{Commit: "aaa"}},
+			},
+		},
+		{
+			name:        "Random_Error",
+			commits:     []git.Commit{{Message: "feat: something useful"}},
+			errVersion:  errRandomError,
+			errExpected: errRandomError,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			source := conventional.NewSource(&tagsVersionGetterMock{tc.errVersion}, commitList(tc.commits))
+			cl, err := source.Changelog()
+			if !errors.Is(err, tc.errExpected) {
+				t.Fatalf("Expected error %v, got %v", tc.errExpected, err)
+			}
+			if tc.errExpected != nil {
+				return
+			}
+
+			if diff := cmp.Diff(tc.expected, cl.Changes); diff != "" {
+				t.Fatalf("Changelog entries differ from expected:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSource_Changelog_Authors(t *testing.T) {
+	t.Parallel()
+
+	commits := commitList{
+		{
+			Message:   "feat: pair programmed\n\nCo-authored-by: John <john@example.com>",
+			Hash:      "aaa",
+			Author:    "Jane Doe <Jane@Example.com>",
+			CoAuthors: []string{"John <john@example.com>", "Bob <bob@example.com>"},
+		},
+	}
+
+	source := conventional.NewSource(&tagsVersionGetterMock{}, commits, conventional.AuthorHandles(map[string]string{
+		"jane@example.com": "jdoe",
+		"bob@example.com":  "@bob",
+	}))
+	cl, err := source.Changelog()
+	if err != nil {
+		t.Fatalf("Error getting changelog: %v", err)
+	}
+
+	expected := []string{"@jdoe", "John", "@bob"}
+	if diff := cmp.Diff(expected, cl.Changes[0].Meta.Authors); diff != "" {
+		t.Fatalf("Authors differ from expected:\n%s", diff)
+	}
+
+	if s := cl.Changes[0].String(); s != "pair programmed, by @jdoe, John and @bob (aaa)" {
+		t.Fatalf("Unexpected entry %q", s)
+	}
+}
@@ -0,0 +1,243 @@
+package markdown
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/Masterminds/semver"
+	"github.com/gomarkdown/markdown"
+	"github.com/gomarkdown/markdown/ast"
+	"github.com/gomarkdown/markdown/md"
+	"github.com/newrelic/release-toolkit/src/changelog"
+	"github.com/newrelic/release-toolkit/src/changelog/sources/markdown/headingdoc"
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	// versionHeaderRegex matches headers such as `v1.2.3 - 2022-01-01`, as rendered by renderer.Renderer.
+	versionHeaderRegex = regexp.MustCompile(`^\[?(v?\d+\.\d+\.\d+[^\s\]]*)\]?(?:\s+-\s+(.+))?$`)
+	// entryRegex reverses changelog.Entry.String, capturing the message, author, and PR or commit.
+	entryRegex = regexp.MustCompile(`^(.+?)(?:, by ([^()]+?))?(?: \((#?\d+|[0-9a-f]{7,40})\))?$`)
+	// prRegex matches PR numbers, as opposed to commit hashes, in the suffix of an entry.
+	prRegex = regexp.MustCompile(`^#?\d{1,6}$`)
+	// dependencyRegex reverses changelog.Dependency.String.
+	dependencyRegex = regexp.MustCompile(`^(?:Upgraded|Downgraded|Updated) (\S+)(?: from (\S+))?(?: to (\S+))?(?: - \[Changelog 🔗\]\((\S+)\))?(?: - \[Compare 🔗\]\((\S+)\))?$`)
+)
+
+// sectionKeywords maps keywords found in L3 headers to the type of the entries under them. Headers are matched loosely,
+// so both `### Bugfixes` and `### 🐞 Bug fixes` are understood.
+//
+//nolint:gochecknoglobals
+var sectionKeywords = []struct {
+	keyword   string
+	entryType changelog.EntryType
+}{
+	{keyword: "breaking", entryType: changelog.TypeBreaking},
+	{keyword: "security", entryType: changelog.TypeSecurity},
+	{keyword: "enhancement", entryType: changelog.TypeEnhancement},
+	{keyword: "bug", entryType: changelog.TypeBugfix},
+	{keyword: "dependenc", entryType: changelog.TypeDependency},
+}
+
+// History is the list of versions that have been released according to a markdown changelog.
+type History struct {
+	// Releases is the list of released versions, in the same order as they appear in the document, which is usually
+	// from newest to oldest.
+	Releases []Release
+}
+
+// Release is a version section of a markdown changelog.
+type Release struct {
+	Version *semver.Version
+	// ReleasedOn is the date in the version header. It is the zero time if the header did not contain a valid date.
+	ReleasedOn time.Time
+	Changelog  changelog.Changelog
+}
+
+// ParseHistory reads a markdown changelog and parses every version section in it, such as `## v1.2.3 - 2022-01-01`.
+// Sections for unreleased or held changes are ignored.
+func ParseHistory(r io.Reader) (*History, error) {
+	doc, err := headingdoc.NewFromReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("parsing markdown: %w", err)
+	}
+
+	h := &History{}
+	h.collect(doc)
+
+	return h, nil
+}
+
+// Find returns the release for the specified version, or nil if it is not in the history.
+func (h *History) Find(v *semver.Version) *Release {
+	for i := range h.Releases {
+		if h.Releases[i].Version.Equal(v) {
+			return &h.Releases[i]
+		}
+	}
+
+	return nil
+}
+
+// collect recursively looks for version headers and parses them as releases.
+func (h *History) collect(doc *headingdoc.Doc) {
+	if release, ok := parseRelease(doc); ok {
+		h.Releases = append(h.Releases, release)
+		return
+	}
+
+	for _, child := range doc.Children {
+		h.collect(child)
+	}
+}
+
+// parseRelease builds a release from a version header and its children. It returns false if doc is not a version
+// header.
+func parseRelease(doc *headingdoc.Doc) (Release, bool) {
+	matches := versionHeaderRegex.FindStringSubmatch(strings.TrimSpace(doc.Name))
+	if matches == nil {
+		return Release{}, false
+	}
+
+	version, err := semver.NewVersion(matches[1])
+	if err != nil {
+		log.Debugf("Skipping header %q as it does not contain a valid version: %v", doc.Name, err)
+		return Release{}, false
+	}
+
+	release := Release{Version: version}
+
+	if date := matches[2]; date != "" {
+		release.ReleasedOn, err = time.Parse("2006-01-02", strings.TrimSpace(date))
+		if err != nil {
+			log.Warnf("Could not parse release date of %q: %v", doc.Name, err)
+		}
+	}
+
+	// First item of the headingDoc content is always the heading itself.
+	notes := renderNodes(nil, doc.Content[1:])
+
+	for _, section := range doc.Children {
+		entryType, isEntrySection := sectionType(section.Name)
+		if !isEntrySection {
+			log.Debugf("Adding header %q under %q as notes", section.Name, doc.Name)
+			notes = renderDoc(notes, section)
+			continue
+		}
+
+		for _, item := range releasedItems(section.Content[1:]) {
+			if entryType == changelog.TypeDependency {
+				release.Changelog.Dependencies = appendDependency(release.Changelog.Dependencies, item)
+				continue
+			}
+
+			release.Changelog.Changes = append(release.Changelog.Changes, parseEntry(item, entryType))
+		}
+	}
+
+	release.Changelog.Notes = strings.Join(notes, "\n\n")
+
+	return release, true
+}
+
+func sectionType(name string) (changelog.EntryType, bool) {
+	lowerName := strings.ToLower(name)
+	for _, sk := range sectionKeywords {
+		if strings.Contains(lowerName, sk.keyword) {
+			return sk.entryType, true
+		}
+	}
+
+	return "", false
+}
+
+// parseEntry reverses changelog.Entry.String.
+func parseEntry(item string, entryType changelog.EntryType) changelog.Entry {
+	entry := changelog.Entry{Type: entryType, Message: item}
+
+	matches := entryRegex.FindStringSubmatch(item)
+	if matches == nil {
+		return entry
+	}
+
+	entry.Message = matches[1]
+	entry.Meta.Authors = splitAuthors(matches[2])
+
+	if ref := matches[3]; ref != "" {
+		if prRegex.MatchString(ref) {
+			entry.Meta.PR = ref
+		} else {
+			entry.Meta.Commit = ref
+		}
+	}
+
+	return entry
+}
+
+// splitAuthors reverses the list of authors rendered by changelog.Entry.String, e.g. `@alice, @bob and Carol`.
+func splitAuthors(authors string) []string {
+	if authors == "" {
+		return nil
+	}
+
+	var split []string
+	for _, author := range strings.Split(authors, ", ") {
+		split = append(split, strings.Split(author, " and ")...)
+	}
+
+	return split
+}
+
+// appendDependency reverses changelog.Dependency.String and appends the result to deps. If it fails to parse the
+// dependency, it is added as a plain entry so no information is lost.
+func appendDependency(deps []changelog.Dependency, item string) []changelog.Dependency {
+	matches := dependencyRegex.FindStringSubmatch(item)
+	if matches == nil {
+		log.Warnf("Could not parse dependency %q, adding it with the whole line as name", item)
+		return append(deps, changelog.Dependency{Name: item})
+	}
+
+	dep := changelog.Dependency{
+		Name:      matches[1],
+		Changelog: matches[4],
+		Compare:   matches[5],
+	}
+
+	var err error
+	if from := matches[2]; from != "" {
+		if dep.From, err = semver.NewVersion(from); err != nil {
+			log.Warnf("Could not parse version %q of dependency %q: %v", from, dep.Name, err)
+		}
+	}
+	if to := matches[3]; to != "" {
+		if dep.To, err = semver.NewVersion(to); err != nil {
+			log.Warnf("Could not parse version %q of dependency %q: %v", to, dep.Name, err)
+		}
+	}
+
+	return append(deps, dep)
+}
+
+// renderDoc appends to blocks the markdown for a header, its content, and all its children recursively.
+func renderDoc(blocks []string, doc *headingdoc.Doc) []string {
+	blocks = renderNodes(blocks, doc.Content)
+	for _, child := range doc.Children {
+		blocks = renderDoc(blocks, child)
+	}
+
+	return blocks
+}
+
+// renderNodes appends to blocks the markdown for each node, so they can be later joined by blank lines.
+func renderNodes(blocks []string, nodes []ast.Node) []string {
+	for _, node := range nodes {
+		if block := strings.TrimSpace(string(markdown.Render(node, md.NewRenderer()))); block != "" {
+			blocks = append(blocks, block)
+		}
+	}
+
+	return blocks
+}
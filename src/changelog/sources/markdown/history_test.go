@@ -0,0 +1,174 @@
+package markdown_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Masterminds/semver"
+	"github.com/google/go-cmp/cmp"
+	cl "github.com/newrelic/release-toolkit/src/changelog"
+	"github.com/newrelic/release-toolkit/src/changelog/renderer"
+	"github.com/newrelic/release-toolkit/src/changelog/sources/markdown"
+)
+
+//nolint:funlen
+func TestParseHistory(t *testing.T) {
+	t.Parallel()
+
+	doc := strings.TrimSpace(`
+# Changelog
+This is based on blah blah blah
+
+## Unreleased
+
+### Enhancements
+- This is not released and should not be included
+
+## v1.3.0 - 2022-09-21
+
+### Important announcement (note)
+This is a release note
+
+### ⚠️️ Breaking changes ⚠️
+- Support has been removed, by @roobre (#123)
+- Old API dropped, by @roobre, Jane Doe and @someone (#124)
+
+### 🚀 Enhancements
+- New feature has been added, by Jane Doe (0b5e1d0c9f3e6c4f1f4c7a39f0b24e0d1b2c3d4e)
+- Feature (with parenthesis) added
+
+### 🐞 Bug fixes
+- Fixed a bug (#42)
+  - Blocks nested in released entries are not part of them
+
+### ⛓️ Dependencies
+- Upgraded foobar from 0.0.1 to 0.1.0 - [Changelog 🔗](https://github.com/foo/bar/releases/tag/v0.1.0)
+- Updated github.com/foo/baz to v1.2.3
+- Upgraded github.com/foo/qux from v1.0.0 to v1.1.0 - [Changelog 🔗](https://github.com/foo/qux/releases/tag/v1.1.0) - [Compare 🔗](https://github.com/foo/qux/compare/v1.0.0...v1.1.0)
+- Something that is not a dependency line
+
+## v1.2.3 - 20YY-DD-MM
+
+### Enhancements
+- This is in the past
+
+### Bugfixes
+- Fixed an old bug
+`)
+
+	expected := &markdown.History{
+		Releases: []markdown.Release{
+			{
+				Version:    semver.MustParse("v1.3.0"),
+				ReleasedOn: time.Date(2022, time.September, 21, 0, 0, 0, 0, time.UTC),
+				Changelog: cl.Changelog{
+					Notes: "### Important announcement (note)\n\nThis is a release note",
+					Changes: []cl.Entry{
+						{Type: cl.TypeBreaking, Message: "Support has been removed", Meta: cl.EntryMeta{Authors: []string{"@roobre"}, PR: "#123"}},
+						{Type: cl.TypeBreaking, Message: "Old API dropped", Meta: cl.EntryMeta{
+							Authors: []string{"@roobre", "Jane Doe", "@someone"}, PR: "#124",
+						}},
+						{Type: cl.TypeEnhancement, Message: "New feature has been added", Meta: cl.EntryMeta{
+							Authors: []string{"Jane Doe"}, Commit: "0b5e1d0c9f3e6c4f1f4c7a39f0b24e0d1b2c3d4e",
+						}},
+						{Type: cl.TypeEnhancement, Message: "Feature (with parenthesis) added"},
+						{Type: cl.TypeBugfix, Message: "Fixed a bug", Meta: cl.EntryMeta{PR: "#42"}},
+					},
+					Dependencies: []cl.Dependency{
+						{
+							Name:      "foobar",
+							From:      semver.MustParse("0.0.1"),
+							To:        semver.MustParse("0.1.0"),
+							Changelog: "https://github.com/foo/bar/releases/tag/v0.1.0",
+						},
+						{Name: "github.com/foo/baz", To: semver.MustParse("v1.2.3")},
+						{
+							Name:      "github.com/foo/qux",
+							From:      semver.MustParse("v1.0.0"),
+							To:        semver.MustParse("v1.1.0"),
+							Changelog: "https://github.com/foo/qux/releases/tag/v1.1.0",
+							Compare:   "https://github.com/foo/qux/compare/v1.0.0...v1.1.0",
+						},
+						{Name: "Something that is not a dependency line"},
+					},
+				},
+			},
+			{
+				Version: semver.MustParse("v1.2.3"),
+				Changelog: cl.Changelog{
+					Changes: []cl.Entry{
+						{Type: cl.TypeEnhancement, Message: "This is in the past"},
+						{Type: cl.TypeBugfix, Message: "Fixed an old bug"},
+					},
+				},
+			},
+		},
+	}
+
+	history, err := markdown.ParseHistory(strings.NewReader(doc))
+	if err != nil {
+		t.Fatalf("Error parsing history: %v", err)
+	}
+
+	if diff := cmp.Diff(expected, history, cmp.Comparer(semverEqual)); diff != "" {
+		t.Fatalf("Parsed history is not as expected:\n%s", diff)
+	}
+
+	if release := history.Find(semver.MustParse("1.2.3")); release == nil || release.Version.Original() != "v1.2.3" {
+		t.Fatalf("Could not find v1.2.3 in history, got %v", release)
+	}
+	if release := history.Find(semver.MustParse("v0.0.1")); release != nil {
+		t.Fatalf("Expected not to find v0.0.1 in history, got %v", release)
+	}
+}
+
+func TestParseHistory_Renderer_Roundtrip(t *testing.T) {
+	t.Parallel()
+
+	ch := &cl.Changelog{
+		Notes: "### Important announcement (note)\n\nThis is a release note",
+		Changes: []cl.Entry{
+			{Type: cl.TypeBreaking, Message: "Support has been removed"},
+			{Type: cl.TypeSecurity, Message: "Fixed a security issue that leaked all data", Meta: cl.EntryMeta{PR: "12"}},
+			{Type: cl.TypeEnhancement, Message: "New feature has been added", Meta: cl.EntryMeta{Authors: []string{"@someone"}}},
+			{Type: cl.TypeBugfix, Message: "Fixed a bug", Meta: cl.EntryMeta{Commit: "0b5e1d0c"}},
+		},
+		Dependencies: []cl.Dependency{
+			{Name: "foobar", From: semver.MustParse("v0.2.0"), To: semver.MustParse("v0.1.0")},
+		},
+	}
+
+	rnd := renderer.New(ch)
+	rnd.Next = semver.MustParse("v2.0.0")
+	rnd.ReleasedOn = func() time.Time {
+		return time.Date(1993, time.September, 21, 0, 0, 0, 0, time.UTC)
+	}
+
+	buf := &strings.Builder{}
+	buf.WriteString("# Changelog\n\n")
+	if err := rnd.Render(buf); err != nil {
+		t.Fatalf("Error rendering changelog: %v", err)
+	}
+
+	history, err := markdown.ParseHistory(strings.NewReader(buf.String()))
+	if err != nil {
+		t.Fatalf("Error parsing history: %v", err)
+	}
+
+	if len(history.Releases) != 1 {
+		t.Fatalf("Expected a single release, got %d", len(history.Releases))
+	}
+
+	if diff := cmp.Diff(*ch, history.Releases[0].Changelog, cmp.Comparer(semverEqual)); diff != "" {
+		t.Fatalf("Parsed changelog does not match the rendered one:\n%s", diff)
+	}
+}
+
+func semverEqual(a, b *semver.Version) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return a.Original() == b.Original()
+}
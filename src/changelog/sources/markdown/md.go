@@ -138,8 +138,18 @@ func (b builder) unvisitedAsNotes(header *headingdoc.Doc) {
 }
 
 // items receives a list of ast.Node, and for those nodes which are lists, returns the list items inside.
-// Nodes which are not lists are ignored.
+// Nodes which are not lists are ignored, as are list items containing more than one block.
 func items(content []ast.Node) []string {
+	return listItems(content, false)
+}
+
+// releasedItems works like items, but keeps the first block of list items containing more than one block, as released
+// sections nest blocks such as the release notes of a dependency in their entries.
+func releasedItems(content []ast.Node) []string {
+	return listItems(content, true)
+}
+
+func listItems(content []ast.Node, firstBlockOnly bool) []string {
 	var itemsStr []string
 
 	// FilterRenderer uses the default renderer, but skips feeding it *ast.Hardbreaks, which would make it panic.
@@ -159,11 +169,20 @@ func items(content []ast.Node) []string {
 				continue
 			}
 
-			if len(item.Children) != 1 {
-				log.Warn("ListItem has more than 1 children")
+			if len(item.Children) == 0 {
+				log.Warn("Skipping empty ListItem")
 				continue
 			}
 
+			if len(item.Children) > 1 {
+				if !firstBlockOnly {
+					log.Warn("ListItem has more than 1 children")
+					continue
+				}
+
+				log.Debugf("Ignoring %d blocks nested in ListItem", len(item.Children)-1)
+			}
+
 			buf := markdown.Render(item.Children[0], renderer)
 			itemsStr = append(itemsStr, strings.TrimSpace(string(buf)))
 		}
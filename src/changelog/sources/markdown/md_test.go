@@ -62,6 +62,29 @@ This is a release note
 				Notes: "### Important announcement (note)\nThis is a release note\n",
 			},
 		},
+		{
+			name: "Skips_Items_With_Nested_Blocks",
+			markdown: strings.TrimSpace(`
+# Changelog
+This is based on blah blah blah
+
+## Unreleased
+
+### Enhancements
+- Added this
+- Improved that
+  - With a nested item that should not be lost silently
+- Fixed those
+
+## v1.2.3 - 20YY-DD-MM
+`),
+			expected: &cl.Changelog{
+				Changes: []cl.Entry{
+					{Type: cl.TypeEnhancement, Message: "Added this"},
+					{Type: cl.TypeEnhancement, Message: "Fixed those"},
+				},
+			},
+		},
 		{
 			name: "Parses_Trailing spaces",
 			markdown: strings.TrimSpace(`
@@ -20,6 +20,15 @@ import (
 type Merger struct {
 	// ReleasedOn is a function that returns the date in which the new section was released. It defaults to time.Now.
 	ReleasedOn func() time.Time
+	// Template, if not empty, is used to render the new section instead of the default layout.
+	// See renderer.Renderer.Template.
+	Template string
+	// ReleaseNotesFor, if not empty, restricts the dependencies whose upstream release notes are rendered.
+	// See renderer.Renderer.ReleaseNotesFor.
+	ReleaseNotesFor []string
+	// VersionPrefix is prepended to the version in the header of the new section. It defaults to "v".
+	// See renderer.Renderer.VersionPrefix.
+	VersionPrefix string
 
 	// version holds the in which the new changelog was released.
 	version *semver.Version
@@ -34,9 +43,10 @@ type Merger struct {
 // to Merge.
 func New(ch *changelog.Changelog, newVersion *semver.Version) Merger {
 	return Merger{
-		ReleasedOn: time.Now,
-		ch:         ch,
-		version:    newVersion,
+		ReleasedOn:    time.Now,
+		VersionPrefix: "v",
+		ch:            ch,
+		version:       newVersion,
 	}
 }
 
@@ -58,6 +68,9 @@ func (m Merger) Merge(srcChangelog io.Reader, dst io.Writer) error {
 	rdr := renderer.New(m.ch)
 	rdr.Next = m.version
 	rdr.ReleasedOn = m.ReleasedOn
+	rdr.Template = m.Template
+	rdr.ReleaseNotesFor = m.ReleaseNotesFor
+	rdr.VersionPrefix = m.VersionPrefix
 
 	err := rdr.Render(newSection)
 	if err != nil {
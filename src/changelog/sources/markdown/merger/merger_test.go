@@ -45,9 +45,40 @@ func TestMerger_Merges(t *testing.T) {
 	for _, tc := range []struct {
 		name     string
 		ch       changelog.Changelog
+		template string
 		original string
 		expected string
 	}{
+		{
+			name:     "Custom_Template",
+			ch:       fullChangelog,
+			template: "## [{{ .Version }}] - {{ .Date }}\n{{ range .Sections.bugfix }}\n- Fixed: {{ .Message }}{{ end }}",
+			original: strings.TrimSpace(`
+# Changelog
+
+## Unreleased
+
+### Bugfixes
+- Fixed this
+
+## [v1.2.3] - 20YY-DD-MM
+
+- Added: This is in the past and should be preserved
+			`) + "\n",
+			expected: strings.TrimSpace(`
+# Changelog
+
+## Unreleased
+
+## [v1.2.4] - 1993-09-21
+
+- Fixed: Fixed this
+
+## [v1.2.3] - 20YY-DD-MM
+
+- Added: This is in the past and should be preserved
+			`) + "\n",
+		},
 		{
 			name: "Full_Changelog",
 			ch:   fullChangelog,
@@ -283,6 +314,7 @@ This is based on blah blah blah
 			buf := &strings.Builder{}
 			mrg := merger.New(&tc.ch, semver.MustParse("v1.2.4"))
 			mrg.ReleasedOn = brokenWristwatch
+			mrg.Template = tc.template
 			err := mrg.Merge(strings.NewReader(tc.original), buf)
 			if err != nil {
 				t.Fatalf("Merger returned error: %v", err)
@@ -0,0 +1,454 @@
+package git_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/newrelic/release-toolkit/src/git"
+	"github.com/stretchr/testify/assert"
+)
+
+// backend builds the getters of a git backend. All backends must pass the conformance tests below, which build
+// fixture repositories and check the commits and tags read from them.
+type backend struct {
+	name    git.Backend
+	commits func(workDir string, opts ...git.CommitsOptionFunc) git.RangeCommitsGetter
+	tags    func(workDir string, opts ...git.TagOptionFunc) (*git.RepoTagsGetter, error)
+}
+
+//nolint:gochecknoglobals
+var backends = []backend{
+	{
+		name: git.BackendNative,
+		commits: func(workDir string, opts ...git.CommitsOptionFunc) git.RangeCommitsGetter {
+			return git.NewRepoCommitsGetter(workDir, opts...)
+		},
+		tags: git.NewRepoTagsGetter,
+	},
+	{
+		name: git.BackendExec,
+		commits: func(workDir string, opts ...git.CommitsOptionFunc) git.RangeCommitsGetter {
+			return git.NewExecCommitsGetter(workDir, opts...)
+		},
+		tags: git.NewExecTagsGetter,
+	},
+}
+
+// commitSummary holds everything backends report about a commit, so results can be compared across backends.
+type commitSummary struct {
+	Hash      string
+	Message   string
+	Author    string
+	CoAuthors []string
+	Parents   []string
+	Files     []string
+}
+
+func summarize(t *testing.T, commits []git.Commit) []commitSummary {
+	t.Helper()
+
+	summaries := make([]commitSummary, 0, len(commits))
+	for _, c := range commits {
+		files, err := c.ChangedFiles()
+		if err != nil {
+			t.Fatalf("Error getting changed files of %q: %v", c.Message, err)
+		}
+
+		summaries = append(summaries, commitSummary{
+			Hash:      c.Hash,
+			Message:   c.Message,
+			Author:    c.Author,
+			CoAuthors: c.CoAuthors,
+			Parents:   c.Parents,
+			Files:     files,
+		})
+	}
+
+	return summaries
+}
+
+// assertSameAcrossBackends checks that every backend reported the same as the first one.
+func assertSameAcrossBackends(t *testing.T, results map[git.Backend]interface{}) {
+	t.Helper()
+
+	reference := backends[0].name
+	for _, b := range backends[1:] {
+		assert.Equalf(t, results[reference], results[b.name], "%s and %s backends differ", reference, b.name)
+	}
+}
+
+func TestBackends_Commits(t *testing.T) {
+	t.Parallel()
+
+	repodir := repoWithCommitsAndTags(t,
+		testCommitTag{"v1.0.0", []string{"a"}},
+		testCommitTag{"v1.1.0", []string{"dir/b", "c"}},
+		testCommitTag{"v1.2.0", []string{"d"}},
+	)
+	lastHash := gitOutput(t, repodir, "", "rev-parse", "v1.0.0")
+
+	results := map[git.Backend]interface{}{}
+	for _, b := range backends {
+		commits, err := b.commits(repodir).Commits(lastHash)
+		if err != nil {
+			t.Fatalf("%s: error fetching commits: %v", b.name, err)
+		}
+
+		summaries := summarize(t, commits)
+		if len(summaries) != 2 {
+			t.Fatalf("%s: expected 2 commits, got %v", b.name, summaries)
+		}
+		assert.Equalf(t, "v1.2.0", summaries[0].Message, "%s: unexpected message", b.name)
+		assert.Equalf(t, "Test <test@user.tld>", summaries[0].Author, "%s: unexpected author", b.name)
+		assert.Equalf(t, []string{summaries[1].Hash}, summaries[0].Parents, "%s: unexpected parents", b.name)
+		assert.Equalf(t, []string{"d"}, summaries[0].Files, "%s: unexpected files", b.name)
+		assert.ElementsMatchf(t, []string{"c", "dir/b"}, summaries[1].Files, "%s: unexpected files", b.name)
+
+		all, err := b.commits(repodir).Commits("")
+		if err != nil {
+			t.Fatalf("%s: error fetching commits: %v", b.name, err)
+		}
+		allSummaries := summarize(t, all)
+		assert.Equalf(t, []string{"a"}, allSummaries[2].Files, "%s: root commit should be compared to the empty tree", b.name)
+
+		_, err = b.commits(repodir).Commits("0123456789012345678901234567890123456789")
+		assert.ErrorIsf(t, err, git.ErrNonexistentCommitHash, "%s: unexpected error", b.name)
+
+		results[b.name] = allSummaries
+	}
+
+	assertSameAcrossBackends(t, results)
+}
+
+func TestBackends_Commits_Merges(t *testing.T) {
+	t.Parallel()
+
+	repodir := repoWithMerges(t)
+	lastHash := gitOutput(t, repodir, "", "rev-parse", "v1.0.0")
+
+	for _, tc := range []struct {
+		name            string
+		opts            []git.CommitsOptionFunc
+		expectedCommits []string
+		expectedAuthors []string
+	}{
+		{
+			name:            "Reachable_From_Head_Not_From_Tag",
+			expectedCommits: []string{"after", "Merge pull request #12 from renovate/foo\n\nUpdate foo to v1.2.3", "side"},
+			expectedAuthors: []string{"Test", "Test", "renovate[bot]"},
+		},
+		{
+			name:            "First_Parent",
+			opts:            []git.CommitsOptionFunc{git.FirstParent()},
+			expectedCommits: []string{"after", "Update foo to v1.2.3 (#12)"},
+			expectedAuthors: []string{"Test", "renovate[bot]"},
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			results := map[git.Backend]interface{}{}
+			for _, b := range backends {
+				commits, err := b.commits(repodir, tc.opts...).Commits(lastHash)
+				if err != nil {
+					t.Fatalf("%s: error fetching commits: %v", b.name, err)
+				}
+
+				messages := make([]string, 0, len(commits))
+				authors := make([]string, 0, len(commits))
+				for _, c := range commits {
+					messages = append(messages, c.Message)
+					authors = append(authors, strings.Fields(c.Author)[0])
+				}
+
+				assert.ElementsMatchf(t, tc.expectedCommits, messages, "%s: reported commits do not match", b.name)
+				assert.ElementsMatchf(t, tc.expectedAuthors, authors, "%s: reported authors do not match", b.name)
+
+				all, err := b.commits(repodir, tc.opts...).Commits("")
+				if err != nil {
+					t.Fatalf("%s: error fetching commits: %v", b.name, err)
+				}
+
+				results[b.name] = summarize(t, all)
+			}
+
+			assertSameAcrossBackends(t, results)
+		})
+	}
+}
+
+func TestBackends_CommitsInRange(t *testing.T) {
+	t.Parallel()
+
+	repodir := repoWithMerges(t)
+	executeCMDs(t, []string{
+		"git checkout -q -b release/1.0 v1.0.0",
+		"git commit --allow-empty -m backport",
+		"git checkout -q master",
+	}, repodir)
+	tagHash := gitOutput(t, repodir, "", "rev-parse", "v1.0.0^{commit}")
+
+	for _, tc := range []struct {
+		name            string
+		r               git.Range
+		expectedCommits []string
+		expectedErr     error
+	}{
+		{
+			name:            "Tag_To_Branch",
+			r:               git.Range{From: "v1.0.0", To: "release/1.0"},
+			expectedCommits: []string{"backport"},
+		},
+		{
+			name:            "Abbreviated_Hash_To_Head",
+			r:               git.Range{From: tagHash[:10]},
+			expectedCommits: []string{"after", "Merge pull request #12 from renovate/foo\n\nUpdate foo to v1.2.3", "side"},
+		},
+		{
+			name:            "Since",
+			r:               git.Range{To: "release/1.0", Since: time.Now().Add(24 * time.Hour)},
+			expectedCommits: []string{},
+		},
+		{
+			name:        "Unknown_Ref",
+			r:           git.Range{From: "does-not-exist"},
+			expectedErr: git.ErrUnknownRef,
+		},
+		{
+			name:        "Flag_As_Ref",
+			r:           git.Range{To: "--all"},
+			expectedErr: git.ErrUnknownRef,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			results := map[git.Backend]interface{}{}
+			for _, b := range backends {
+				commits, err := b.commits(repodir).CommitsInRange(tc.r)
+				if tc.expectedErr != nil {
+					assert.ErrorIsf(t, err, tc.expectedErr, "%s: unexpected error", b.name)
+					continue
+				}
+				if err != nil {
+					t.Fatalf("%s: error fetching commits: %v", b.name, err)
+				}
+
+				messages := make([]string, 0, len(commits))
+				for _, c := range commits {
+					messages = append(messages, c.Message)
+				}
+				assert.ElementsMatchf(t, tc.expectedCommits, messages, "%s: reported commits do not match", b.name)
+
+				results[b.name] = summarize(t, commits)
+			}
+
+			assertSameAcrossBackends(t, results)
+		})
+	}
+}
+
+func TestBackends_Shallow(t *testing.T) {
+	t.Parallel()
+
+	clone, tagHash := shallowClone(t, 3)
+	withinHistory := gitOutput(t, clone, "", "rev-parse", "HEAD~1")
+
+	results := map[git.Backend]interface{}{}
+	for _, b := range backends {
+		_, err := b.commits(clone).Commits(tagHash)
+
+		var shallowErr *git.ShallowError
+		if !errors.As(err, &shallowErr) {
+			t.Fatalf("%s: expected a ShallowError, got %v", b.name, err)
+		}
+		assert.Equalf(t, 3, shallowErr.Depth, "%s: unexpected depth", b.name)
+
+		commits, err := b.commits(clone).Commits(withinHistory)
+		if err != nil {
+			t.Fatalf("%s: error fetching commits within the shallow history: %v", b.name, err)
+		}
+		assert.Lenf(t, commits, 1, "%s: unexpected commits", b.name)
+
+		all, err := git.NewShallowTolerantCommitsGetter(b.commits(clone)).Commits(tagHash)
+		if err != nil {
+			t.Fatalf("%s: error fetching commits: %v", b.name, err)
+		}
+
+		// Parents of the commit at the boundary are not compared, as go-git reports the ones missing from the clone.
+		summaries := summarize(t, all)
+		summaries[len(summaries)-1].Parents = nil
+		// The commit at the boundary is compared to the empty tree, so all the files in its tree are reported.
+		assert.Equalf(t, []string{"a", "file0", "file1", "file2"}, summaries[len(summaries)-1].Files, "%s: unexpected files", b.name)
+
+		results[b.name] = summaries
+	}
+
+	assertSameAcrossBackends(t, results)
+}
+
+func TestBackends_Tags(t *testing.T) {
+	t.Parallel()
+
+	repodir := repoWithAnnotatedTags(t)
+
+	results := map[git.Backend]interface{}{}
+	for _, b := range backends {
+		tagsGetter, err := b.tags(repodir, git.TagsMatchingCommits(b.commits(repodir)))
+		if err != nil {
+			t.Fatalf("%s: error creating tags getter: %v", b.name, err)
+		}
+
+		tags, err := tagsGetter.Tags()
+		if err != nil {
+			t.Fatalf("%s: error fetching tags: %v", b.name, err)
+		}
+
+		byName := map[string]git.Tag{}
+		for _, tag := range tags {
+			byName[tag.Name] = tag
+		}
+
+		assert.NotContainsf(t, byName, "v9.9.9", "%s: tags in other branches should be skipped", b.name)
+		for _, name := range []string{"v1.0.0", "v1.1.0", "v1.1.0-nested", "v1.2.0"} {
+			assert.Equalf(t, gitOutput(t, repodir, "", "rev-parse", name+"^{commit}"), byName[name].Hash, "%s: %s was not peeled to its commit", b.name, name)
+		}
+
+		assert.Nilf(t, byName["v1.0.0"].Annotation, "%s: lightweight tags should not have annotations", b.name)
+
+		signed := byName["v1.2.0"].Annotation
+		if signed == nil {
+			t.Fatalf("%s: signed tag has no annotation", b.name)
+		}
+		assert.Equalf(t, "Signer", signed.TaggerName, "%s: unexpected tagger", b.name)
+		assert.Equalf(t, "signer@user.tld", signed.TaggerEmail, "%s: unexpected tagger email", b.name)
+		assert.Truef(t, time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC).Equal(signed.Date), "%s: unexpected tagger date %v", b.name, signed.Date)
+		assert.Equalf(t, "Signed release\n", signed.Message, "%s: unexpected message", b.name)
+		assert.Equalf(t, testSignature, signed.Signature, "%s: unexpected signature", b.name)
+
+		// Dates are compared as instants, as backends may report them in different locations.
+		for name, tag := range byName {
+			if tag.Annotation != nil {
+				tag.Annotation.Date = tag.Annotation.Date.UTC()
+				byName[name] = tag
+			}
+		}
+		results[b.name] = byName
+	}
+
+	assertSameAcrossBackends(t, results)
+}
+
+func TestBackends_Tags_Without_Tagger(t *testing.T) {
+	t.Parallel()
+
+	repodir := repoWithTags(t, "v1.0.0")
+
+	// Legacy and imported annotated tags may lack a tagger, which git mktag refuses to create.
+	legacyTag := "object " + gitOutput(t, repodir, "", "rev-parse", "HEAD") + "\n" +
+		"type commit\n" +
+		"tag v1.1.0\n" +
+		"\n" +
+		"Legacy release\n"
+	legacyHash := gitOutput(t, repodir, legacyTag, "hash-object", "-t", "tag", "-w", "--literally", "--stdin")
+	executeCMDs(t, []string{"git tag v1.1.0 " + legacyHash}, repodir)
+
+	results := map[git.Backend]interface{}{}
+	for _, b := range backends {
+		tagsGetter, err := b.tags(repodir)
+		if err != nil {
+			t.Fatalf("%s: error creating tags getter: %v", b.name, err)
+		}
+
+		tags, err := tagsGetter.Tags()
+		if err != nil {
+			t.Fatalf("%s: error fetching tags: %v", b.name, err)
+		}
+
+		byName := map[string]git.Tag{}
+		for _, tag := range tags {
+			byName[tag.Name] = tag
+		}
+
+		legacy := byName["v1.1.0"].Annotation
+		if legacy == nil {
+			t.Fatalf("%s: tag without tagger has no annotation", b.name)
+		}
+		assert.Equalf(t, "", legacy.TaggerName, "%s: unexpected tagger", b.name)
+		assert.Truef(t, legacy.Date.IsZero(), "%s: unexpected tagger date %v", b.name, legacy.Date)
+		assert.Equalf(t, "Legacy release\n", legacy.Message, "%s: unexpected message", b.name)
+
+		results[b.name] = byName
+	}
+
+	assertSameAcrossBackends(t, results)
+}
+
+func TestNewRepoWithBackend(t *testing.T) {
+	t.Parallel()
+
+	clone, _ := shallowClone(t, 2)
+
+	for _, b := range backends {
+		repo, err := git.NewRepoWithBackend(clone, b.name)
+		if err != nil {
+			t.Fatalf("%s: error creating repo: %v", b.name, err)
+		}
+
+		shallow, depth, err := repo.Shallow()
+		if err != nil {
+			t.Fatalf("%s: error checking shallow repository: %v", b.name, err)
+		}
+		assert.Truef(t, shallow, "%s: repository should be shallow", b.name)
+		assert.Equalf(t, 2, depth, "%s: unexpected depth", b.name)
+
+		tags, err := repo.Tags()
+		if err != nil {
+			t.Fatalf("%s: error fetching tags: %v", b.name, err)
+		}
+		assert.Emptyf(t, tags, "%s: tags beyond the shallow boundary should not be fetched", b.name)
+	}
+
+	_, err := git.NewRepoWithBackend(clone, "libgit2")
+	assert.ErrorIs(t, err, git.ErrUnknownBackend)
+}
+
+func TestBackends_Authors(t *testing.T) {
+	t.Parallel()
+
+	repodir := repoWithTags(t)
+	writeFile(t, repodir, git.MailmapFile, strings.Join([]string{
+		"# Comments are ignored",
+		"Jane Doe <jane@example.com>",
+		"Jane Doe <jane@example.com> <jane@old.example.com>",
+		"<john@example.com> John <john@personal.tld>",
+	}, "\n"))
+
+	gitOutput(t, repodir, "", "commit", "--allow-empty", "--author", "jdoe <JANE@old.example.com>",
+		"-m", "feat: pair programmed\n\nCo-authored-by: John <john@personal.tld>\n"+
+			"co-authored-by: Bob <bob@example.com>\nCo-authored-by: Jane <jane@example.com>\n"+
+			"Co-authored-by: Bob Smith <bob@example.com>")
+
+	results := map[git.Backend]interface{}{}
+	for _, b := range backends {
+		commits, err := b.commits(repodir).Commits("")
+		if err != nil {
+			t.Fatalf("%s: error fetching commits: %v", b.name, err)
+		}
+
+		summaries := summarize(t, commits)
+		assert.Equalf(t, "Jane Doe <jane@example.com>", summaries[0].Author, "%s: author not mapped", b.name)
+		assert.Equalf(t, []string{"John <john@example.com>", "Bob <bob@example.com>"}, summaries[0].CoAuthors,
+			"%s: co-authors should be mapped, and the author and repeated ones skipped", b.name)
+		assert.Equalf(t, "Test <test@user.tld>", summaries[1].Author, "%s: unmapped author changed", b.name)
+		assert.Emptyf(t, summaries[1].CoAuthors, "%s: unexpected co-authors", b.name)
+
+		results[b.name] = summaries
+	}
+
+	assertSameAcrossBackends(t, results)
+}
@@ -3,8 +3,11 @@ package git
 import (
 	"errors"
 	"fmt"
-	"io"
+	"regexp"
+	"sort"
 	"strings"
+	"sync"
+	"time"
 
 	"gopkg.in/src-d/go-git.v4"
 	"gopkg.in/src-d/go-git.v4/plumbing"
@@ -14,118 +17,510 @@ import (
 // EmptyTreeID is the universal git empty tree sha1.
 const EmptyTreeID = "4b825dc642cb6eb9a060e54bf8d69288fbee4904"
 
-var ErrNonexistentCommitHash = fmt.Errorf("nonexistent commit hash")
+var (
+	ErrNonexistentCommitHash = fmt.Errorf("nonexistent commit hash")
+	ErrUnknownRef            = errors.New("ref does not exist or does not point to a commit")
+	ErrAmbiguousRef          = errors.New("abbreviated hash matches more than one commit")
+)
+
+var (
+	// abbreviatedHashRegex matches strings that can be an abbreviated commit hash.
+	abbreviatedHashRegex = regexp.MustCompile(`^[0-9a-f]{4,39}$`)
+	// githubMergeRegex matches the message of merge commits created by GitHub pull requests, capturing the PR number
+	// and the PR title, which is the first line of the body.
+	githubMergeRegex = regexp.MustCompile(`^Merge pull request #(\d+) from \S+\n\n([^\n]+)`)
+	// gitlabMergeRegex matches the message of merge commits created by GitLab merge requests, capturing the MR title
+	// and the MR number.
+	gitlabMergeRegex = regexp.MustCompile(`^Merge branch '[^']+' into '[^']+'\n\n([^\n]+)(?s:.*)\nSee merge request \S*!(\d+)`)
+)
 
 type Commit struct {
 	Message string
 	Hash    string
-	Author  string
-	Files   []string
+	// Author is the author of the commit as `Name <email>`, mapped to their canonical name and email if the repository
+	// has a .mailmap file.
+	Author string
+	// CoAuthors holds the other authors credited in Co-authored-by trailers of the message, in the same format and
+	// mapped as Author. The author and repeated co-authors are not included.
+	CoAuthors []string
+	// Files holds the files changed by the commit, for getters that know them upfront. Use ChangedFiles to read them.
+	Files []string
+	// Parents holds the hashes of the parents of the commit, with the first parent first.
+	Parents []string
+
+	changes *changedFiles
+	// shallow is true for commits at the boundary of a shallow clone, whose parents are not in the repository.
+	shallow bool
+}
+
+// changedFiles computes the files changed by a commit at most once. It is shared by all copies of a Commit, so
+// callers reading the same commits, e.g. through a CachedCommitsGetter, compute each diff once.
+type changedFiles struct {
+	once  sync.Once
+	load  func() ([]string, error)
+	files []string
+	err   error
+}
+
+// ChangedFiles returns the files changed by the commit. Getters may compute them lazily instead of setting Files,
+// as diffing trees is expensive, so this method should be used instead of reading Files directly.
+func (c Commit) ChangedFiles() ([]string, error) {
+	if c.changes == nil {
+		return c.Files, nil
+	}
+
+	c.changes.once.Do(func() {
+		c.changes.files, c.changes.err = c.changes.load()
+	})
+
+	return c.changes.files, c.changes.err
 }
 
 type CommitsGetter interface {
 	Commits(lastHash string) ([]Commit, error)
 }
 
+// Range selects a set of commits, similarly to `git log --since <Since> <From>..<To>`.
+// From and To can be anything git understands as a commit: full or abbreviated hashes, branches or tags.
+type Range struct {
+	// From is the commit where the range starts, which is not part of it. All commits reachable from To are returned
+	// if it is empty.
+	From string
+	// To is the commit where the range ends, which is part of it. HEAD is used if it is empty.
+	To string
+	// Since excludes commits committed before it, if it is not zero.
+	Since time.Time
+}
+
+// RangeCommitsGetter is a CommitsGetter that can also return the commits in an arbitrary Range.
+type RangeCommitsGetter interface {
+	CommitsGetter
+	CommitsInRange(r Range) ([]Commit, error)
+}
+
 // RepoCommitsGetter gets commits from a git repository.
 type RepoCommitsGetter struct {
+	commitsOptions
 	workDir string
+	// opener returns the repository to read commits from. If it is not set, workDir is opened on every call.
+	opener func() (*git.Repository, error)
+}
+
+// commitsOptions holds the options shared by all the getters reading commits from a repository.
+type commitsOptions struct {
+	firstParent bool
+}
+
+type CommitsOptionFunc func(o *commitsOptions)
+
+// FirstParent returns an option that makes the getter follow only the first parent of merge commits, like
+// `git log --first-parent`. Merge commits are then the unit of change: they are returned instead of the commits they
+// merge, with the author of the merged branch, and the title and number of the pull request they come from formatted as
+// a squashed commit, e.g. `Update foo to v1.2.3 (#123)`.
+func FirstParent() CommitsOptionFunc {
+	return func(o *commitsOptions) {
+		o.firstParent = true
+	}
 }
 
-func NewRepoCommitsGetter(workDir string) *RepoCommitsGetter {
-	return &RepoCommitsGetter{
+func NewRepoCommitsGetter(workDir string, opts ...CommitsOptionFunc) *RepoCommitsGetter {
+	s := &RepoCommitsGetter{
 		workDir: workDir,
 	}
+
+	for _, opt := range opts {
+		opt(&s.commitsOptions)
+	}
+
+	return s
 }
 
 // Commits returns all the commits from Head ordered from top to bottom
 // until LastHash, if lastHash is empty, all commits are returned.
 func (s *RepoCommitsGetter) Commits(lastHash string) ([]Commit, error) {
+	repo, err := s.open()
+	if err != nil {
+		return nil, err
+	}
+
+	return s.commits(repo, plumbing.ZeroHash, lastHash, time.Time{})
+}
+
+// CommitsInRange returns the commits in r ordered from top to bottom.
+func (s *RepoCommitsGetter) CommitsInRange(r Range) ([]Commit, error) {
+	repo, err := s.open()
+	if err != nil {
+		return nil, err
+	}
+
+	to := plumbing.ZeroHash
+	if r.To != "" {
+		to, err = resolveRef(repo, r.To)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var lastHash string
+	if r.From != "" {
+		var from plumbing.Hash
+		from, err = resolveRef(repo, r.From)
+		if err != nil {
+			return nil, err
+		}
+		lastHash = from.String()
+	}
+
+	return s.commits(repo, to, lastHash, r.Since)
+}
+
+func (s *RepoCommitsGetter) open() (*git.Repository, error) {
+	if s.opener != nil {
+		return s.opener()
+	}
+
 	repo, err := git.PlainOpen(s.workDir)
 	if err != nil {
 		return nil, fmt.Errorf("opening git repo at %s: %w", s.workDir, err)
 	}
 
-	commitIter, err := repo.Log(&git.LogOptions{Order: git.LogOrderCommitterTime})
+	return repo, nil
+}
+
+// commits returns the commits reachable from the commit to, or HEAD if it is zero, that are not reachable from
+// lastHash, skipping the ones committed before since.
+func (s *RepoCommitsGetter) commits(repo *git.Repository, to plumbing.Hash, lastHash string, since time.Time) ([]Commit, error) {
+	mailmap, err := ReadMailmap(s.workDir)
 	if err != nil {
-		return nil, fmt.Errorf("getting git commits: %w", err)
+		return nil, err
+	}
+
+	if to.IsZero() {
+		head, err := repo.Head()
+		if err != nil {
+			return nil, fmt.Errorf("getting HEAD: %w", err)
+		}
+		to = head.Hash()
 	}
-	defer commitIter.Close()
 
-	var isLastHash bool
-	gitCommits := make([]*object.Commit, 0)
+	tip, err := repo.CommitObject(to)
+	if err != nil {
+		return nil, fmt.Errorf("getting commit %s: %w", to, err)
+	}
+
+	shallow, err := shallowCommits(repo)
+	if err != nil {
+		return nil, err
+	}
 
-	for !isLastHash {
-		cm, errCommit := commitIter.Next()
-		if errCommit != nil && !errors.Is(errCommit, io.EOF) {
-			return nil, fmt.Errorf("iterating git commits: %w", errCommit)
+	var lastCommit *object.Commit
+	excluded := map[plumbing.Hash]bool{}
+	if lastHash != "" {
+		lastCommit, err = repo.CommitObject(plumbing.NewHash(lastHash))
+		if err == nil {
+			excluded, err = ancestors(lastCommit, shallow)
+			if err != nil {
+				return nil, err
+			}
 		}
+	}
+
+	gitCommits, depth, truncated, err := s.walk(tip, excluded, shallow)
+	if err != nil {
+		return nil, err
+	}
+
+	if lastHash != "" && lastCommit == nil {
+		if len(shallow) > 0 {
+			return nil, &ShallowError{Hash: lastHash, Depth: depth}
+		}
+		return nil, fmt.Errorf("finding commits: %w", ErrNonexistentCommitHash)
+	}
+
+	// Commits past the shallow boundary are needed to tell which ones are not reachable from lastHash.
+	if lastHash != "" && truncated {
+		return nil, &ShallowError{Hash: lastHash, Depth: depth}
+	}
+
+	commits, err := s.toCommits(gitCommits, shallow, mailmap)
+	if err != nil {
+		return nil, err
+	}
+
+	if since.IsZero() {
+		return commits, nil
+	}
+
+	recent := make([]Commit, 0, len(commits))
+	for i, c := range commits {
+		if gitCommits[i].Committer.When.Before(since) {
+			continue
+		}
+		recent = append(recent, c)
+	}
+
+	return recent, nil
+}
+
+// walk returns the commits reachable from tip that are not in excluded. In first-parent mode, only the first parent
+// of merge commits is followed and commits are returned in the order they are found, otherwise they are sorted from
+// the most to the least recently committed.
+// Parents of shallow commits are not followed, as they are not present in the repository. walk also returns the depth
+// of the history it walked, and whether it reached a shallow commit.
+func (s *RepoCommitsGetter) walk(
+	tip *object.Commit, excluded, shallow map[plumbing.Hash]bool,
+) (walked []*object.Commit, depth int, truncated bool, err error) {
+	type pendingCommit struct {
+		commit *object.Commit
+		depth  int
+	}
 
-		// No more commits to iterate
-		if cm == nil {
-			if lastHash != "" {
-				return nil, fmt.Errorf("finding commits: %w", ErrNonexistentCommitHash)
+	seen := map[plumbing.Hash]bool{}
+	pending := []pendingCommit{{commit: tip, depth: 1}}
+
+	for len(pending) > 0 {
+		cm := pending[0].commit
+		cmDepth := pending[0].depth
+		pending = pending[1:]
+
+		if seen[cm.Hash] || excluded[cm.Hash] {
+			continue
+		}
+		seen[cm.Hash] = true
+		walked = append(walked, cm)
+
+		if cmDepth > depth {
+			depth = cmDepth
+		}
+
+		if shallow[cm.Hash] {
+			truncated = true
+			continue
+		}
+
+		parents := cm.NumParents()
+		if s.firstParent && parents > 1 {
+			parents = 1
+		}
+
+		for i := 0; i < parents; i++ {
+			parent, pErr := cm.Parent(i)
+			if pErr != nil {
+				return nil, 0, false, fmt.Errorf("getting parent of commit %s: %w", cm.Hash, pErr)
 			}
-			break
+			pending = append(pending, pendingCommit{commit: parent, depth: cmDepth + 1})
+		}
+	}
+
+	if !s.firstParent {
+		sort.SliceStable(walked, func(i, j int) bool {
+			return walked[i].Committer.When.After(walked[j].Committer.When)
+		})
+	}
+
+	return walked, depth, truncated, nil
+}
+
+// ancestors returns the hashes of commit and all the commits reachable from it, up to the shallow boundary.
+func ancestors(commit *object.Commit, shallow map[plumbing.Hash]bool) (map[plumbing.Hash]bool, error) {
+	reachable := map[plumbing.Hash]bool{}
+	pending := []*object.Commit{commit}
+
+	for len(pending) > 0 {
+		cm := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+
+		if reachable[cm.Hash] {
+			continue
+		}
+		reachable[cm.Hash] = true
+
+		if shallow[cm.Hash] {
+			continue
+		}
+
+		err := cm.Parents().ForEach(func(parent *object.Commit) error {
+			pending = append(pending, parent)
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("walking history of commit %s: %w", commit.Hash, err)
+		}
+	}
+
+	return reachable, nil
+}
+
+// resolveRef returns the hash of the commit ref points to. Besides what go-git's ResolveRevision supports,
+// abbreviated commit hashes are accepted.
+func resolveRef(repo *git.Repository, ref string) (plumbing.Hash, error) {
+	hash, err := repo.ResolveRevision(plumbing.Revision(ref))
+	if err == nil {
+		return *hash, nil
+	}
+
+	if !abbreviatedHashRegex.MatchString(ref) {
+		return plumbing.ZeroHash, fmt.Errorf("resolving %q: %w", ref, ErrUnknownRef)
+	}
+
+	commitIter, err := repo.CommitObjects()
+	if err != nil {
+		return plumbing.ZeroHash, fmt.Errorf("getting git commits: %w", err)
+	}
+	defer commitIter.Close()
+
+	found := plumbing.ZeroHash
+	err = commitIter.ForEach(func(cm *object.Commit) error {
+		if !strings.HasPrefix(cm.Hash.String(), ref) {
+			return nil
+		}
+		if !found.IsZero() {
+			return fmt.Errorf("resolving %q: %w", ref, ErrAmbiguousRef)
+		}
+		found = cm.Hash
+		return nil
+	})
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+
+	if found.IsZero() {
+		return plumbing.ZeroHash, fmt.Errorf("resolving %q: %w", ref, ErrUnknownRef)
+	}
+
+	return found, nil
+}
+
+// toCommits converts go-git commits to Commits, whose changed files are computed only when they are first read.
+func (s *RepoCommitsGetter) toCommits(
+	gitCommits []*object.Commit, shallow map[plumbing.Hash]bool, mailmap *Mailmap,
+) ([]Commit, error) {
+	commits := make([]Commit, 0, len(gitCommits))
+
+	for _, cm := range gitCommits {
+		parents := make([]string, 0, len(cm.ParentHashes))
+		for _, p := range cm.ParentHashes {
+			parents = append(parents, p.String())
 		}
 
-		gitCommits = append(gitCommits, cm)
+		cm := cm
+		commit := Commit{
+			Message:   strings.TrimSuffix(cm.Message, "\n"),
+			Hash:      cm.Hash.String(),
+			Author:    cm.Author.String(),
+			CoAuthors: coAuthors(cm.Message),
+			Parents:   parents,
+			shallow:   shallow[cm.Hash],
+			changes: &changedFiles{
+				load: func() ([]string, error) {
+					return s.changedFiles(cm)
+				},
+			},
+		}
 
-		// Get also LastHash to be able to compare it with last in list
-		if cm.Hash.String() == lastHash {
-			isLastHash = true
+		if s.firstParent && cm.NumParents() > 1 {
+			merged, err := cm.Parent(1)
+			if err != nil {
+				return nil, fmt.Errorf("getting merged parent of commit %s: %w", cm.Hash, err)
+			}
+			commit = asSquashedCommit(commit, merged.Author.String())
 		}
+
+		commits = append(commits, mailmap.withAuthors(commit))
 	}
 
-	return s.commitsWithChangedFiles(lastHash, gitCommits)
+	return commits, nil
 }
 
-// commitsWithChanges iterates the list of commits and populates a list with changed files.
-func (s *RepoCommitsGetter) commitsWithChangedFiles(lastHash string, gitCommits []*object.Commit) ([]Commit, error) {
-	commits := make([]Commit, 0)
+// asSquashedCommit rewrites a merge commit as if the pull request it comes from had been squashed: the message is the
+// title of the pull request followed by its number, and the author is the author of the merged branch.
+// Merge commits whose message is not recognized keep it untouched.
+func asSquashedCommit(commit Commit, mergedAuthor string) Commit {
+	commit.Author = mergedAuthor
+
+	if matches := githubMergeRegex.FindStringSubmatch(commit.Message); matches != nil {
+		commit.Message = fmt.Sprintf("%s (#%s)", matches[2], matches[1])
+	} else if matches := gitlabMergeRegex.FindStringSubmatch(commit.Message); matches != nil {
+		commit.Message = fmt.Sprintf("%s (!%s)", matches[1], matches[2])
+	}
 
-	for k, cm := range gitCommits {
-		currentTree, err := cm.Tree()
+	return commit
+}
+
+// changedFiles returns the files changed by cm with respect to its parents. Root commits are compared to the empty
+// tree. Merge commits are compared only to their first parent in first-parent mode, as they stand for all the changes
+// they merge. Otherwise, only files that differ from all parents are reported, like `git diff --cc` does, as the
+// merged changes are reported by the merged commits themselves.
+func (s *RepoCommitsGetter) changedFiles(cm *object.Commit) ([]string, error) {
+	tree, err := cm.Tree()
+	if err != nil {
+		return nil, fmt.Errorf("getting tree of commit %s: %w", cm.Hash, err)
+	}
+
+	if cm.NumParents() == 0 {
+		return s.diff(&object.Tree{Hash: plumbing.NewHash(EmptyTreeID)}, tree)
+	}
+
+	parents := cm.NumParents()
+	if s.firstParent {
+		parents = 1
+	}
+
+	var files []string
+	for i := 0; i < parents; i++ {
+		parent, err := cm.Parent(i)
+		// Commits at the boundary of a shallow clone are compared to the empty tree, as git does.
+		if errors.Is(err, plumbing.ErrObjectNotFound) {
+			return s.diff(&object.Tree{Hash: plumbing.NewHash(EmptyTreeID)}, tree)
+		}
 		if err != nil {
-			return nil, fmt.Errorf("getting commit tree: %w", err)
+			return nil, fmt.Errorf("getting parent of commit %s: %w", cm.Hash, err)
 		}
 
-		prevTree, err := s.getPreviousCommitTree(k, gitCommits)
+		parentTree, err := parent.Tree()
 		if err != nil {
-			return nil, fmt.Errorf("getting previous commit tree: %w", err)
+			return nil, fmt.Errorf("getting tree of commit %s: %w", parent.Hash, err)
 		}
 
-		// Get changes between current commit and previous
-		changes, err := prevTree.Diff(currentTree)
+		parentFiles, err := s.diff(parentTree, tree)
 		if err != nil {
-			return nil, fmt.Errorf("getting diff between commits: %w", err)
+			return nil, err
 		}
 
-		if cm.Hash.String() == lastHash {
+		if i == 0 {
+			files = parentFiles
 			continue
 		}
+		files = intersect(files, parentFiles)
+	}
 
-		commits = append(commits, Commit{
-			Message: strings.TrimSuffix(cm.Message, "\n"),
-			Hash:    cm.Hash.String(),
-			Author:  cm.Author.String(),
-			Files:   s.getChangedFiles(changes),
-		})
+	return files, nil
+}
+
+func (s *RepoCommitsGetter) diff(from, to *object.Tree) ([]string, error) {
+	changes, err := object.DiffTree(from, to)
+	if err != nil {
+		return nil, fmt.Errorf("getting diff between commits: %w", err)
 	}
 
-	return commits, nil
+	return s.getChangedFiles(changes), nil
 }
 
-//nolint:wrapcheck
-func (s *RepoCommitsGetter) getPreviousCommitTree(positionInList int, commitList []*object.Commit) (*object.Tree, error) {
-	if positionInList < len(commitList)-1 {
-		return commitList[positionInList+1].Tree()
+// intersect returns the elements in a that are also in b.
+func intersect(a, b []string) []string {
+	inB := make(map[string]bool, len(b))
+	for _, e := range b {
+		inB[e] = true
+	}
+
+	both := make([]string, 0, len(a))
+	for _, e := range a {
+		if inB[e] {
+			both = append(both, e)
+		}
 	}
 
-	// When no previous commit exits, we get the empty tree
-	return &object.Tree{
-		Hash: plumbing.NewHash(EmptyTreeID),
-	}, nil
+	return both
 }
 
 func (s *RepoCommitsGetter) getChangedFiles(changes []*object.Change) []string {
@@ -0,0 +1,158 @@
+package git_test
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/newrelic/release-toolkit/src/git"
+)
+
+// syntheticDirs are the directories synthetic commits change files in.
+//
+//nolint:gochecknoglobals
+var syntheticDirs = []string{"agent", "operator", "charts/agent", "charts/operator", "docs"}
+
+// syntheticRepo creates a repository with n commits on master, each changing a file in one of syntheticDirs, where
+// every tenth commit merges a side branch with a commit of its own. The commit `tagged` commits before HEAD is tagged
+// as v1.0.0. The history is built with git fast-import, which is orders of magnitude faster than committing one by one.
+func syntheticRepo(b *testing.B, n, tagged int) string {
+	b.Helper()
+
+	dir := b.TempDir()
+
+	stream := &strings.Builder{}
+	mark := 0
+	prev := 0
+	for i := 0; i < n; i++ {
+		var side int
+		if i%10 == 9 {
+			mark++
+			side = mark
+			writeSyntheticCommit(stream, fmt.Sprintf("refs/heads/side-%d", i), side, prev, 0, i, "side")
+		}
+
+		mark++
+		writeSyntheticCommit(stream, "refs/heads/master", mark, prev, side, i, "master")
+		prev = mark
+
+		if i == n-1-tagged {
+			_, _ = fmt.Fprintf(stream, "reset refs/tags/v1.0.0\nfrom :%d\n\n", mark)
+		}
+	}
+
+	for _, cmdline := range []string{"git init --initial-branch master", "git fast-import --quiet", "git checkout -q master"} {
+		cmdparts := strings.Fields(cmdline)
+		//nolint:gosec // This is a test, we trust hardcoded input.
+		cmd := exec.Command(cmdparts[0], cmdparts[1:]...)
+		cmd.Dir = dir
+		cmd.Stdin = strings.NewReader(stream.String())
+
+		out := strings.Builder{}
+		cmd.Stdout = &out
+		cmd.Stderr = &out
+		if err := cmd.Run(); err != nil {
+			b.Fatalf("Error bootstrapping synthetic repo: %s: %v\n%s", cmdline, err, out.String())
+		}
+	}
+
+	return dir
+}
+
+// writeSyntheticCommit writes a fast-import commit with the given mark, parent and merged marks (0 for none), changing
+// a file in one of syntheticDirs.
+func writeSyntheticCommit(w *strings.Builder, ref string, mark, parent, merge, i int, kind string) {
+	message := fmt.Sprintf("%s commit %d", kind, i)
+	content := fmt.Sprintf("%s %d\n", kind, i)
+	file := fmt.Sprintf("%s/file%d", syntheticDirs[i%len(syntheticDirs)], i%50)
+
+	_, _ = fmt.Fprintf(w, "commit %s\nmark :%d\n", ref, mark)
+	_, _ = fmt.Fprintf(w, "committer Test <test@user.tld> %d +0000\n", 1600000000+mark)
+	_, _ = fmt.Fprintf(w, "data %d\n%s\n", len(message), message)
+	if parent != 0 {
+		_, _ = fmt.Fprintf(w, "from :%d\n", parent)
+	}
+	if merge != 0 {
+		_, _ = fmt.Fprintf(w, "merge :%d\n", merge)
+	}
+	_, _ = fmt.Fprintf(w, "M 644 inline %s\ndata %d\n%s\n", file, len(content), content)
+}
+
+func BenchmarkRepoCommitsGetter(b *testing.B) {
+	for _, size := range []int{1000, 5000} {
+		repodir := syntheticRepo(b, size, 100)
+
+		lastHash := gitOutput(b, repodir, "", "rev-parse", "v1.0.0^{commit}")
+
+		b.Run(fmt.Sprintf("Commits/commits=%d", size), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := git.NewRepoCommitsGetter(repodir).Commits(lastHash); err != nil {
+					b.Fatalf("Error fetching commits: %v", err)
+				}
+			}
+		})
+
+		b.Run(fmt.Sprintf("Exec_Commits/commits=%d", size), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := git.NewExecCommitsGetter(repodir).Commits(lastHash); err != nil {
+					b.Fatalf("Error fetching commits: %v", err)
+				}
+			}
+		})
+
+		b.Run(fmt.Sprintf("Filtered/commits=%d", size), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				filter, err := git.NewCommitFilter(git.NewRepoCommitsGetter(repodir), git.IncludedDirs("agent"))
+				if err != nil {
+					b.Fatalf("Error creating filter: %v", err)
+				}
+
+				if _, err = filter.Commits(lastHash); err != nil {
+					b.Fatalf("Error fetching commits: %v", err)
+				}
+			}
+		})
+
+		// Mimics generate-yaml, where the renovate, dependabot and conventional commits sources filter the same
+		// cached commits.
+		b.Run(fmt.Sprintf("Filtered_Cached_Sources/commits=%d", size), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				cached := git.NewCachedCommitsGetter(git.NewRepoCommitsGetter(repodir))
+				for source := 0; source < 3; source++ {
+					filter, err := git.NewCommitFilter(cached, git.IncludedDirs("agent"), git.ExcludedDirs("docs"))
+					if err != nil {
+						b.Fatalf("Error creating filter: %v", err)
+					}
+
+					if _, err = filter.Commits(lastHash); err != nil {
+						b.Fatalf("Error fetching commits: %v", err)
+					}
+				}
+			}
+		})
+
+		// Mimics generate-yaml with a shared Repo, where tags are looked up once and matched against the history.
+		b.Run(fmt.Sprintf("Repo_Sources/commits=%d", size), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				repo := git.NewRepo(repodir)
+
+				tagsGetter, err := repo.TagsGetter(git.TagsMatchingCommits(repo))
+				if err != nil {
+					b.Fatalf("Error creating tags getter: %v", err)
+				}
+
+				hash, err := git.NewTagsSource(tagsGetter).LastVersionHash()
+				if err != nil {
+					b.Fatalf("Error fetching last version hash: %v", err)
+				}
+
+				for source := 0; source < 3; source++ {
+					if _, err = repo.Commits(hash); err != nil {
+						b.Fatalf("Error fetching commits: %v", err)
+					}
+				}
+			}
+		})
+	}
+}
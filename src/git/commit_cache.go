@@ -0,0 +1,137 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+var ErrRangeNotSupported = errors.New("commits getter does not support ranges")
+
+// CachedCommitsGetter wraps a CommitsGetter, fetching the whole commit history once and serving subsequent calls to
+// Commits from memory. It is useful when the same repository is queried several times, for example to compute the
+// changelogs of several components of a monorepo.
+type CachedCommitsGetter struct {
+	getter CommitsGetter
+
+	once    sync.Once
+	commits []Commit
+	err     error
+
+	rangesMtx sync.Mutex
+	ranges    map[Range][]Commit
+}
+
+func NewCachedCommitsGetter(getter CommitsGetter) *CachedCommitsGetter {
+	return &CachedCommitsGetter{
+		getter: getter,
+		ranges: map[Range][]Commit{},
+	}
+}
+
+// Commits returns the cached commits from Head ordered from top to bottom that are not reachable from lastHash. If
+// lastHash is empty, all commits are returned.
+func (c *CachedCommitsGetter) Commits(lastHash string) ([]Commit, error) {
+	c.once.Do(func() {
+		c.commits, c.err = c.getter.Commits("")
+	})
+
+	if c.err != nil {
+		return nil, fmt.Errorf("caching commits: %w", c.err)
+	}
+
+	if lastHash == "" {
+		return c.commits, nil
+	}
+
+	byHash := make(map[string]Commit, len(c.commits))
+	for _, commit := range c.commits {
+		byHash[commit.Hash] = commit
+	}
+
+	if _, found := byHash[lastHash]; !found {
+		if c.shallow() {
+			return c.uncached(lastHash)
+		}
+		return nil, fmt.Errorf("finding commits: %w", ErrNonexistentCommitHash)
+	}
+
+	// Walk the history from lastHash, following the parents recorded in the cached commits.
+	excluded := map[string]bool{}
+	pending := []string{lastHash}
+	for len(pending) > 0 {
+		hash := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+
+		commit, found := byHash[hash]
+		if !found || excluded[hash] {
+			continue
+		}
+
+		excluded[hash] = true
+		pending = append(pending, commit.Parents...)
+	}
+
+	commits := make([]Commit, 0, len(c.commits))
+	for _, commit := range c.commits {
+		if excluded[commit.Hash] {
+			continue
+		}
+
+		// Reaching the boundary of a shallow clone means the history needed to tell which commits are reachable from
+		// lastHash is missing.
+		if commit.shallow {
+			return c.uncached(lastHash)
+		}
+
+		commits = append(commits, commit)
+	}
+
+	return commits, nil
+}
+
+// shallow returns whether the cached history reaches the boundary of a shallow clone.
+func (c *CachedCommitsGetter) shallow() bool {
+	for _, commit := range c.commits {
+		if commit.shallow {
+			return true
+		}
+	}
+
+	return false
+}
+
+// uncached gets commits since lastHash from the wrapped getter, which is used for the error it returns when lastHash
+// is beyond the boundary of a shallow clone.
+func (c *CachedCommitsGetter) uncached(lastHash string) ([]Commit, error) {
+	commits, err := c.getter.Commits(lastHash)
+	if err != nil {
+		return nil, fmt.Errorf("getting commits past the cached history: %w", err)
+	}
+
+	return commits, nil
+}
+
+// CommitsInRange returns the commits in r, which are fetched from the wrapped getter only the first time a given range
+// is requested. ErrRangeNotSupported is returned if the wrapped getter is not a RangeCommitsGetter.
+func (c *CachedCommitsGetter) CommitsInRange(r Range) ([]Commit, error) {
+	rangeGetter, isRangeGetter := c.getter.(RangeCommitsGetter)
+	if !isRangeGetter {
+		return nil, ErrRangeNotSupported
+	}
+
+	c.rangesMtx.Lock()
+	defer c.rangesMtx.Unlock()
+
+	if commits, found := c.ranges[r]; found {
+		return commits, nil
+	}
+
+	commits, err := rangeGetter.CommitsInRange(r)
+	if err != nil {
+		return nil, fmt.Errorf("caching commits: %w", err)
+	}
+
+	c.ranges[r] = commits
+	return commits, nil
+}
@@ -0,0 +1,118 @@
+package git_test
+
+import (
+	"testing"
+
+	"github.com/newrelic/release-toolkit/src/git"
+	"github.com/stretchr/testify/assert"
+)
+
+type countingSource struct {
+	commits []git.Commit
+	calls   int
+}
+
+func (cs *countingSource) Commits(_ string) ([]git.Commit, error) {
+	cs.calls++
+	return cs.commits, nil
+}
+
+func TestCachedCommitsGetter_Commits(t *testing.T) {
+	t.Parallel()
+
+	source := &countingSource{
+		commits: []git.Commit{
+			{Hash: "c3", Parents: []string{"c2"}},
+			{Hash: "c2", Parents: []string{"c1"}},
+			{Hash: "c1"},
+		},
+	}
+	cached := git.NewCachedCommitsGetter(source)
+
+	commits, err := cached.Commits("")
+	if err != nil {
+		t.Fatalf("Error fetching commits: %v", err)
+	}
+	assert.Equal(t, source.commits, commits)
+
+	commits, err = cached.Commits("c2")
+	if err != nil {
+		t.Fatalf("Error fetching commits: %v", err)
+	}
+	assert.Equal(t, source.commits[:1], commits)
+
+	commits, err = cached.Commits("c3")
+	if err != nil {
+		t.Fatalf("Error fetching commits: %v", err)
+	}
+	assert.Empty(t, commits)
+
+	_, err = cached.Commits("aw3s0m3c0mm17h45h")
+	assert.ErrorIs(t, err, git.ErrNonexistentCommitHash)
+
+	assert.Equal(t, 1, source.calls, "Underlying getter should have been called once")
+}
+
+func TestCachedCommitsGetter_Commits_Merges(t *testing.T) {
+	t.Parallel()
+
+	// m merges the side branch s1 into c2, which was tagged after s1 was branched off c1.
+	source := &countingSource{
+		commits: []git.Commit{
+			{Hash: "m", Parents: []string{"c2", "s1"}},
+			{Hash: "c2", Parents: []string{"c1"}},
+			{Hash: "s1", Parents: []string{"c1"}},
+			{Hash: "c1"},
+		},
+	}
+	cached := git.NewCachedCommitsGetter(source)
+
+	commits, err := cached.Commits("c2")
+	if err != nil {
+		t.Fatalf("Error fetching commits: %v", err)
+	}
+
+	hashes := make([]string, 0, len(commits))
+	for _, c := range commits {
+		hashes = append(hashes, c.Hash)
+	}
+	assert.Equal(t, []string{"m", "s1"}, hashes, "Commits merged after the tag should be included")
+}
+
+type countingRangeSource struct {
+	countingSource
+	ranges []git.Range
+}
+
+func (cs *countingRangeSource) CommitsInRange(r git.Range) ([]git.Commit, error) {
+	cs.ranges = append(cs.ranges, r)
+	return cs.commits, nil
+}
+
+func TestCachedCommitsGetter_CommitsInRange(t *testing.T) {
+	t.Parallel()
+
+	_, err := git.NewCachedCommitsGetter(&countingSource{}).CommitsInRange(git.Range{To: "main"})
+	assert.ErrorIs(t, err, git.ErrRangeNotSupported)
+
+	source := &countingRangeSource{
+		countingSource: countingSource{commits: []git.Commit{{Hash: "c2"}, {Hash: "c1"}}},
+	}
+	cached := git.NewCachedCommitsGetter(source)
+
+	for i := 0; i < 2; i++ {
+		commits, err := cached.CommitsInRange(git.Range{From: "v1.0.0", To: "main"})
+		if err != nil {
+			t.Fatalf("Error fetching commits: %v", err)
+		}
+		assert.Equal(t, source.commits, commits)
+	}
+
+	_, err = git.NewRangedCommitsGetter(cached, git.Range{To: "release"}).Commits("v1.1.0")
+	if err != nil {
+		t.Fatalf("Error fetching commits: %v", err)
+	}
+
+	expectedRanges := []git.Range{{From: "v1.0.0", To: "main"}, {From: "v1.1.0", To: "release"}}
+	assert.Equal(t, expectedRanges, source.ranges, "Underlying getter should have been called once per range")
+}
@@ -119,14 +119,32 @@ func (s *CommitFilter) Commits(lastHash string) ([]Commit, error) {
 
 	filteredCommits := make([]Commit, 0)
 	for _, commit := range commits {
-		if !s.commitExcludedByFiles(commit.Files) && !s.commitExcludedByDependencies(commit.Message) {
-			filteredCommits = append(filteredCommits, commit)
+		if s.commitExcludedByDependencies(commit.Message) {
+			continue
 		}
+
+		// Changed files are expensive to compute, so they are only read when there are rules for them.
+		if s.hasFileRules() {
+			files, err := commit.ChangedFiles()
+			if err != nil {
+				return nil, fmt.Errorf("commit filter, getting files changed by %s: %w", commit.Hash, err)
+			}
+
+			if s.commitExcludedByFiles(files) {
+				continue
+			}
+		}
+
+		filteredCommits = append(filteredCommits, commit)
 	}
 
 	return filteredCommits, nil
 }
 
+func (s *CommitFilter) hasFileRules() bool {
+	return len(s.includedDirs) > 0 || len(s.excludedDirs) > 0 || len(s.includedFiles) > 0 || len(s.excludedFiles) > 0
+}
+
 // commitExcludedByFiles returns true if all changes are excluded or if none of the changes are included.
 // Notice that the exclude-clause takes precedence.
 func (s *CommitFilter) commitExcludedByFiles(files []string) bool {
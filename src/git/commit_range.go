@@ -0,0 +1,32 @@
+package git
+
+import "fmt"
+
+// RangedCommitsGetter is a CommitsGetter that returns the commits in a fixed Range, regardless of the last hash it
+// is asked for. It allows sources that look for commits since the last version to work on an arbitrary range instead.
+type RangedCommitsGetter struct {
+	getter RangeCommitsGetter
+	r      Range
+}
+
+func NewRangedCommitsGetter(getter RangeCommitsGetter, r Range) *RangedCommitsGetter {
+	return &RangedCommitsGetter{
+		getter: getter,
+		r:      r,
+	}
+}
+
+// Commits returns the commits in the range. If the range does not have a From commit, lastHash is used instead.
+func (g *RangedCommitsGetter) Commits(lastHash string) ([]Commit, error) {
+	r := g.r
+	if r.From == "" {
+		r.From = lastHash
+	}
+
+	commits, err := g.getter.CommitsInRange(r)
+	if err != nil {
+		return nil, fmt.Errorf("getting commits in range: %w", err)
+	}
+
+	return commits, nil
+}
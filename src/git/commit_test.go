@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/newrelic/release-toolkit/src/git"
 	"github.com/stretchr/testify/assert"
@@ -127,3 +128,240 @@ func TestCommitSource_Commits(t *testing.T) {
 		})
 	}
 }
+
+func TestCommitSource_CommitsInRange(t *testing.T) {
+	t.Parallel()
+	repodir := repoWithCommitsAndTags(t,
+		testCommitTag{"v1.2.3", []string{"v1.2.3"}},
+		testCommitTag{"v1.3.0", []string{"v1.3.0"}},
+		testCommitTag{"v1.4.0", []string{"v1.4.0"}},
+		testCommitTag{"v1.5.0", []string{"v1.5.0"}},
+	)
+	executeCMDs(t, []string{
+		"git checkout -b release/1.3 v1.3.0",
+		"touch hotfix",
+		"git add hotfix",
+		"git commit -m hotfix",
+		"git checkout -",
+	}, repodir)
+
+	for _, tc := range []struct {
+		name            string
+		r               git.Range
+		expectedCommits []string
+		expectedError   error
+	}{
+		{
+			name:            "Empty_Range",
+			expectedCommits: []string{"v1.5.0", "v1.4.0", "v1.3.0", "v1.2.3"},
+		},
+		{
+			name:            "From_Tag",
+			r:               git.Range{From: "v1.3.0"},
+			expectedCommits: []string{"v1.5.0", "v1.4.0"},
+		},
+		{
+			name:            "From_Abbreviated_Hash",
+			r:               git.Range{From: getVersionCommitHash(t, repodir, "v1.4.0")[:7]},
+			expectedCommits: []string{"v1.5.0"},
+		},
+		{
+			name:            "To_Tag",
+			r:               git.Range{From: "v1.2.3", To: "v1.4.0"},
+			expectedCommits: []string{"v1.4.0", "v1.3.0"},
+		},
+		{
+			name:            "To_Branch",
+			r:               git.Range{From: "v1.2.3", To: "release/1.3"},
+			expectedCommits: []string{"hotfix", "v1.3.0"},
+		},
+		{
+			name:            "Since_Past",
+			r:               git.Range{From: "v1.3.0", Since: time.Now().Add(-time.Hour)},
+			expectedCommits: []string{"v1.5.0", "v1.4.0"},
+		},
+		{
+			name:            "Since_Future",
+			r:               git.Range{Since: time.Now().Add(time.Hour)},
+			expectedCommits: []string{},
+		},
+		{
+			name:          "Unknown_From",
+			r:             git.Range{From: "v9.9.9"},
+			expectedError: git.ErrUnknownRef,
+		},
+		{
+			name:          "Unknown_To",
+			r:             git.Range{To: "nonexistent/branch"},
+			expectedError: git.ErrUnknownRef,
+		},
+		{
+			name:            "From_Other_Branch",
+			r:               git.Range{From: "release/1.3"},
+			expectedCommits: []string{"v1.5.0", "v1.4.0"},
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			commitsGetter := git.NewRepoCommitsGetter(repodir)
+			commits, err := commitsGetter.CommitsInRange(tc.r)
+			if tc.expectedError != nil {
+				assert.ErrorIs(t, err, tc.expectedError)
+				return
+			}
+			if err != nil {
+				t.Fatalf("Error fetching commits: %v", err)
+			}
+
+			strCommits := make([]string, 0, len(commits))
+			for _, c := range commits {
+				strCommits = append(strCommits, c.Message)
+			}
+
+			assert.ElementsMatchf(t, tc.expectedCommits, strCommits, "Reported commits do not match")
+		})
+	}
+}
+
+// repoWithMerges creates a repository where a side branch is merged before v1.0.0 is tagged, and a pull request
+// branched off before the tag is merged after it.
+func repoWithMerges(t *testing.T) string {
+	t.Helper()
+
+	dir := t.TempDir()
+
+	for _, cmdline := range []string{
+		"git init --initial-branch master",
+		"git config user.email test@user.tld",
+		"git config user.name Test",
+		"git config commit.gpgsign false",
+		"git commit --allow-empty -m initial",
+		"git checkout -b early",
+		"git commit --allow-empty -m early-side",
+		"git checkout -b renovate/foo master",
+		"touch foo",
+		"git add foo",
+		"git commit --author 'renovate[bot] <bot@renovateapp.com>' -m side",
+		"git checkout master",
+		"git merge --no-ff early -m 'Merge branch early'",
+		"git tag v1.0.0",
+		"git merge --no-ff renovate/foo -m 'Merge pull request #12 from renovate/foo' -m 'Update foo to v1.2.3'",
+		"git commit --allow-empty -m after",
+	} {
+		cmd := exec.Command("/bin/bash", "-c", cmdline)
+		cmd.Dir = dir
+
+		out := strings.Builder{}
+		cmd.Stdout = &out
+		cmd.Stderr = &out
+		if err := cmd.Run(); err != nil {
+			t.Fatalf("Error bootstrapping test git repo: %s: %v\n%s", cmdline, err, out.String())
+		}
+	}
+
+	return dir
+}
+
+func TestCommitSource_Commits_Merges(t *testing.T) {
+	t.Parallel()
+
+	repodir := repoWithMerges(t)
+	lastHash := getVersionCommitHash(t, repodir, "v1.0.0")
+
+	for _, tc := range []struct {
+		name            string
+		opts            []git.CommitsOptionFunc
+		expectedCommits []string
+		expectedAuthors []string
+	}{
+		{
+			name:            "Reachable_From_Head_Not_From_Tag",
+			expectedCommits: []string{"after", "Merge pull request #12 from renovate/foo\n\nUpdate foo to v1.2.3", "side"},
+			expectedAuthors: []string{"Test", "Test", "renovate[bot]"},
+		},
+		{
+			name:            "First_Parent",
+			opts:            []git.CommitsOptionFunc{git.FirstParent()},
+			expectedCommits: []string{"after", "Update foo to v1.2.3 (#12)"},
+			expectedAuthors: []string{"Test", "renovate[bot]"},
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			commits, err := git.NewRepoCommitsGetter(repodir, tc.opts...).Commits(lastHash)
+			if err != nil {
+				t.Fatalf("Error fetching commits: %v", err)
+			}
+
+			messages := make([]string, 0, len(commits))
+			authors := make([]string, 0, len(commits))
+			for _, c := range commits {
+				messages = append(messages, c.Message)
+				authors = append(authors, strings.Fields(c.Author)[0])
+			}
+
+			assert.ElementsMatchf(t, tc.expectedCommits, messages, "Reported commits do not match")
+			assert.ElementsMatchf(t, tc.expectedAuthors, authors, "Reported authors do not match")
+		})
+	}
+}
+
+func TestCommitSource_ChangedFiles(t *testing.T) {
+	t.Parallel()
+
+	repodir := repoWithMerges(t)
+
+	for _, tc := range []struct {
+		name          string
+		opts          []git.CommitsOptionFunc
+		expectedFiles map[string][]string
+	}{
+		{
+			name: "Merges_Report_Only_Their_Own_Changes",
+			expectedFiles: map[string][]string{
+				"after": {},
+				"Merge pull request #12 from renovate/foo\n\nUpdate foo to v1.2.3": {},
+				"side":               {"foo"},
+				"Merge branch early": {},
+				"early-side":         {},
+				"initial":            {},
+			},
+		},
+		{
+			name: "First_Parent_Merges_Report_Merged_Changes",
+			opts: []git.CommitsOptionFunc{git.FirstParent()},
+			expectedFiles: map[string][]string{
+				"after":                      {},
+				"Update foo to v1.2.3 (#12)": {"foo"},
+				"Merge branch early":         {},
+				"initial":                    {},
+			},
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			commits, err := git.NewRepoCommitsGetter(repodir, tc.opts...).Commits("")
+			if err != nil {
+				t.Fatalf("Error fetching commits: %v", err)
+			}
+
+			files := map[string][]string{}
+			for _, c := range commits {
+				assert.Empty(t, c.Files, "Files should be computed lazily")
+
+				files[c.Message], err = c.ChangedFiles()
+				if err != nil {
+					t.Fatalf("Error getting changed files: %v", err)
+				}
+			}
+
+			assert.Equal(t, tc.expectedFiles, files)
+		})
+	}
+}
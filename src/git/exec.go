@@ -0,0 +1,543 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// logFormat is the format of the commits printed by git log, as fields separated by NUL characters: hash, parent
+// hashes, author, committer date and raw message.
+const logFormat = "%H%x00%P%x00%an <%ae>%x00%ct%x00%B%x00"
+
+// tagFormat is the format of the tags printed by git for-each-ref, as fields separated by NUL characters: ref name,
+// type and hash of the object it points to, type and hash of that object once dereferenced, tagger name, email and
+// date, and the message and signature of the tag object.
+const tagFormat = "%(refname)%00%(objecttype)%00%(objectname)%00%(*objecttype)%00%(*objectname)%00" +
+	"%(taggername)%00%(taggeremail)%00%(taggerdate:raw)%00%(contents)%00%(contents:signature)%00"
+
+// ErrUnexpectedOutput is returned if the output of the git binary cannot be parsed.
+var ErrUnexpectedOutput = errors.New("unexpected output from git")
+
+// gitCmd runs the git binary in a repository.
+type gitCmd struct {
+	workDir string
+}
+
+// gitError is returned when git exits with an error, keeping what it printed to stderr.
+type gitError struct {
+	args   []string
+	stderr string
+	err    error
+}
+
+func (e *gitError) Error() string {
+	return fmt.Sprintf("running git %s: %v: %s", strings.Join(e.args, " "), e.err, e.stderr)
+}
+
+func (e *gitError) Unwrap() error {
+	return e.err
+}
+
+// run runs git with args and returns what it printed to stdout.
+func (g gitCmd) run(args ...string) (string, error) {
+	return g.runWithInput("", args...)
+}
+
+// runWithInput runs git with args, feeding it input, and returns what it printed to stdout.
+func (g gitCmd) runWithInput(input string, args ...string) (string, error) {
+	// Settings that change the output of the commands used are overridden, and messages are not translated, so they
+	// can be matched.
+	fullArgs := append([]string{"-c", "log.showSignature=false", "-c", "color.ui=false"}, args...)
+
+	//nolint:gosec // Arguments are either constant or refs, which are checked not to be flags.
+	cmd := exec.Command("git", fullArgs...)
+	cmd.Dir = g.workDir
+	cmd.Env = append(os.Environ(), "LC_ALL=C", "GIT_TERMINAL_PROMPT=0")
+	cmd.Stdin = strings.NewReader(input)
+
+	stdout := &strings.Builder{}
+	stderr := &strings.Builder{}
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+
+	if err := cmd.Run(); err != nil {
+		return "", &gitError{args: args, stderr: strings.TrimSpace(stderr.String()), err: err}
+	}
+
+	return stdout.String(), nil
+}
+
+// resolve returns the hash of the commit ref points to.
+func (g gitCmd) resolve(ref string) (string, error) {
+	// Refs starting with a dash would be taken as flags.
+	if strings.HasPrefix(ref, "-") {
+		return "", fmt.Errorf("resolving %q: %w", ref, ErrUnknownRef)
+	}
+
+	out, err := g.run("rev-parse", "--verify", ref+"^{commit}")
+
+	var gitErr *gitError
+	if errors.As(err, &gitErr) && strings.Contains(gitErr.stderr, "is ambiguous") {
+		return "", fmt.Errorf("resolving %q: %w", ref, ErrAmbiguousRef)
+	}
+	if err != nil {
+		return "", fmt.Errorf("resolving %q: %w", ref, ErrUnknownRef)
+	}
+
+	return strings.TrimSpace(out), nil
+}
+
+// exists returns whether hash is a commit present in the repository.
+func (g gitCmd) exists(hash string) bool {
+	if strings.HasPrefix(hash, "-") {
+		return false
+	}
+
+	_, err := g.run("cat-file", "-e", hash+"^{commit}")
+	return err == nil
+}
+
+// head returns the hash of the commit HEAD points to.
+func (g gitCmd) head() (string, error) {
+	out, err := g.run("rev-parse", "--verify", "HEAD^{commit}")
+	if err != nil {
+		return "", fmt.Errorf("getting HEAD: %w", err)
+	}
+
+	return strings.TrimSpace(out), nil
+}
+
+// shallowCommits returns the commits at the boundary of a shallow clone. It is empty for complete repositories.
+func (g gitCmd) shallowCommits() (map[string]bool, error) {
+	out, err := g.run("rev-parse", "--git-path", "shallow")
+	if err != nil {
+		return nil, fmt.Errorf("getting path of shallow file: %w", err)
+	}
+
+	path := strings.TrimSpace(out)
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(g.workDir, path)
+	}
+
+	contents, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return map[string]bool{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading shallow commits: %w", err)
+	}
+
+	shallow := map[string]bool{}
+	for _, hash := range strings.Fields(string(contents)) {
+		shallow[hash] = true
+	}
+
+	return shallow, nil
+}
+
+// ExecCommitsGetter gets commits from a git repository by running the git binary, which supports repository features
+// that RepoCommitsGetter does not, such as partial clones, SHA-256 object names, worktrees or safe.directory.
+// It returns the same commits, in the same order, as RepoCommitsGetter.
+type ExecCommitsGetter struct {
+	commitsOptions
+	git gitCmd
+}
+
+func NewExecCommitsGetter(workDir string, opts ...CommitsOptionFunc) *ExecCommitsGetter {
+	g := &ExecCommitsGetter{
+		git: gitCmd{workDir: workDir},
+	}
+
+	for _, opt := range opts {
+		opt(&g.commitsOptions)
+	}
+
+	return g
+}
+
+// Commits returns all the commits from Head ordered from top to bottom until lastHash. If lastHash is empty, all
+// commits are returned.
+func (g *ExecCommitsGetter) Commits(lastHash string) ([]Commit, error) {
+	head, err := g.git.head()
+	if err != nil {
+		return nil, err
+	}
+
+	return g.commits(head, lastHash, time.Time{})
+}
+
+// CommitsInRange returns the commits in r ordered from top to bottom.
+func (g *ExecCommitsGetter) CommitsInRange(r Range) ([]Commit, error) {
+	to := r.To
+	if to == "" {
+		to = "HEAD"
+	}
+
+	tip, err := g.git.resolve(to)
+	if err != nil {
+		return nil, err
+	}
+
+	var lastHash string
+	if r.From != "" {
+		lastHash, err = g.git.resolve(r.From)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return g.commits(tip, lastHash, r.Since)
+}
+
+// logEntry is a commit as printed by git log.
+type logEntry struct {
+	commit    Commit
+	committed time.Time
+}
+
+// commits returns the commits reachable from tip that are not reachable from lastHash, skipping the ones committed
+// before since.
+func (g *ExecCommitsGetter) commits(tip, lastHash string, since time.Time) ([]Commit, error) {
+	shallow, err := g.git.shallowCommits()
+	if err != nil {
+		return nil, err
+	}
+
+	// Authors are mapped by Mailmap rather than with the %aN and %aE placeholders of git, so both backends map them
+	// the same way.
+	mailmap, err := ReadMailmap(g.git.workDir)
+	if err != nil {
+		return nil, err
+	}
+
+	args := []string{"log", "--format=" + logFormat}
+	if g.firstParent {
+		args = append(args, "--first-parent")
+	}
+	args = append(args, tip)
+
+	lastFound := lastHash != "" && g.git.exists(lastHash)
+	if lastFound {
+		args = append(args, "^"+lastHash)
+	}
+
+	out, err := g.git.run(args...)
+	if err != nil {
+		return nil, fmt.Errorf("getting git commits: %w", err)
+	}
+
+	entries, err := parseLog(out)
+	if err != nil {
+		return nil, err
+	}
+
+	ordered, depth, truncated := g.order(tip, entries, shallow)
+
+	if lastHash != "" && !lastFound {
+		if len(shallow) > 0 {
+			return nil, &ShallowError{Hash: lastHash, Depth: depth}
+		}
+		return nil, fmt.Errorf("finding commits: %w", ErrNonexistentCommitHash)
+	}
+
+	// Commits past the shallow boundary are needed to tell which ones are not reachable from lastHash.
+	if lastHash != "" && truncated {
+		return nil, &ShallowError{Hash: lastHash, Depth: depth}
+	}
+
+	if g.firstParent {
+		if err = g.squashMerges(ordered); err != nil {
+			return nil, err
+		}
+	}
+
+	commits := make([]Commit, 0, len(ordered))
+	for _, entry := range ordered {
+		if !since.IsZero() && entry.committed.Before(since) {
+			continue
+		}
+
+		commit := mailmap.withAuthors(entry.commit)
+		commit.shallow = shallow[commit.Hash]
+		commit.changes = &changedFiles{
+			load: func() ([]string, error) {
+				return g.changedFiles(commit)
+			},
+		}
+
+		commits = append(commits, commit)
+	}
+
+	return commits, nil
+}
+
+// parseLog parses the output of git log printed with logFormat.
+func parseLog(out string) ([]*logEntry, error) {
+	const fields = 5
+
+	parts := strings.Split(out, "\x00")
+	entries := make([]*logEntry, 0, len(parts)/fields)
+	for i := 0; i+fields <= len(parts); i += fields {
+		// Commits are separated by a newline, which ends up at the start of the hash of the next one.
+		hash := strings.TrimPrefix(parts[i], "\n")
+
+		committed, err := strconv.ParseInt(parts[i+3], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("parsing date of commit %s: %w", hash, err)
+		}
+
+		entries = append(entries, &logEntry{
+			commit: Commit{
+				Hash:      hash,
+				Parents:   strings.Fields(parts[i+1]),
+				Author:    parts[i+2],
+				CoAuthors: coAuthors(parts[i+4]),
+				Message:   strings.TrimSuffix(parts[i+4], "\n"),
+			},
+			committed: time.Unix(committed, 0),
+		})
+	}
+
+	return entries, nil
+}
+
+// order sorts the commits printed by git log as RepoCommitsGetter.walk does: breadth first from tip, sorted from the
+// most to the least recently committed unless in first-parent mode. It also returns the depth of the history walked,
+// and whether it reached a shallow commit.
+func (g *ExecCommitsGetter) order(
+	tip string, entries []*logEntry, shallow map[string]bool,
+) (ordered []*logEntry, depth int, truncated bool) {
+	byHash := make(map[string]*logEntry, len(entries))
+	for _, entry := range entries {
+		byHash[entry.commit.Hash] = entry
+	}
+
+	depths := map[string]int{tip: 1}
+	pending := []string{tip}
+	for len(pending) > 0 {
+		hash := pending[0]
+		pending = pending[1:]
+
+		entry, found := byHash[hash]
+		if !found {
+			continue
+		}
+		ordered = append(ordered, entry)
+
+		if depths[hash] > depth {
+			depth = depths[hash]
+		}
+
+		if shallow[hash] {
+			truncated = true
+			continue
+		}
+
+		parents := entry.commit.Parents
+		if g.firstParent && len(parents) > 1 {
+			parents = parents[:1]
+		}
+
+		for _, parent := range parents {
+			if _, visited := depths[parent]; !visited {
+				depths[parent] = depths[hash] + 1
+				pending = append(pending, parent)
+			}
+		}
+	}
+
+	if !g.firstParent {
+		sort.SliceStable(ordered, func(i, j int) bool {
+			return ordered[i].committed.After(ordered[j].committed)
+		})
+	}
+
+	return ordered, depth, truncated
+}
+
+// squashMerges rewrites merge commits with asSquashedCommit, reading the authors of the merged branches at once.
+func (g *ExecCommitsGetter) squashMerges(entries []*logEntry) error {
+	var merged []string
+	for _, entry := range entries {
+		if len(entry.commit.Parents) > 1 {
+			merged = append(merged, entry.commit.Parents[1])
+		}
+	}
+
+	if len(merged) == 0 {
+		return nil
+	}
+
+	out, err := g.git.run(append([]string{"log", "--no-walk=unsorted", "--format=%H%x00%an <%ae>%x00"}, merged...)...)
+	if err != nil {
+		return fmt.Errorf("getting merged parents: %w", err)
+	}
+
+	authors := map[string]string{}
+	parts := strings.Split(out, "\x00")
+	for i := 0; i+2 <= len(parts); i += 2 {
+		authors[strings.TrimPrefix(parts[i], "\n")] = parts[i+1]
+	}
+
+	for _, entry := range entries {
+		if len(entry.commit.Parents) > 1 {
+			entry.commit = asSquashedCommit(entry.commit, authors[entry.commit.Parents[1]])
+		}
+	}
+
+	return nil
+}
+
+// changedFiles returns the files changed by commit with the same semantics as RepoCommitsGetter.changedFiles.
+func (g *ExecCommitsGetter) changedFiles(commit Commit) ([]string, error) {
+	// Root commits, and commits at the boundary of a shallow clone, which git takes as root commits, are compared to
+	// the empty tree.
+	if len(commit.Parents) == 0 || commit.shallow {
+		return g.diff("--root", commit.Hash)
+	}
+
+	parents := commit.Parents
+	if g.firstParent {
+		parents = parents[:1]
+	}
+
+	var files []string
+	for i, parent := range parents {
+		parentFiles, err := g.diff(parent, commit.Hash)
+		if err != nil {
+			return nil, err
+		}
+
+		if i == 0 {
+			files = parentFiles
+			continue
+		}
+		files = intersect(files, parentFiles)
+	}
+
+	return files, nil
+}
+
+// diff returns the names of the files changed between the trees given by args to git diff-tree. Renames are reported
+// as a deletion and an addition, as go-git does.
+func (g *ExecCommitsGetter) diff(args ...string) ([]string, error) {
+	out, err := g.git.run(append([]string{"diff-tree", "-r", "-z", "--name-only", "--no-renames", "--no-commit-id"}, args...)...)
+	if err != nil {
+		return nil, fmt.Errorf("getting diff between commits: %w", err)
+	}
+
+	files := make([]string, 0)
+	for _, file := range strings.Split(out, "\x00") {
+		if file != "" {
+			files = append(files, file)
+		}
+	}
+
+	return files, nil
+}
+
+// NewExecTagsGetter returns a RepoTagsGetter, accepting the same options as NewRepoTagsGetter, that reads tags by
+// running the git binary in workDir.
+func NewExecTagsGetter(workDir string, opts ...TagOptionFunc) (*RepoTagsGetter, error) {
+	cmd := gitCmd{workDir: workDir}
+	return newRepoTagsGetter(cmd.tags, opts...)
+}
+
+// tags returns all the tags in the repository, with annotated tags peeled to their commits.
+func (g gitCmd) tags() ([]Tag, error) {
+	const fields = 10
+
+	out, err := g.run("for-each-ref", "--format="+tagFormat, "refs/tags")
+	if err != nil {
+		return nil, fmt.Errorf("getting git tags: %w", err)
+	}
+
+	var tags []Tag
+	var nested []int
+	parts := strings.Split(out, "\x00")
+	for i := 0; i+fields <= len(parts); i += fields {
+		name := strings.TrimPrefix(strings.TrimPrefix(parts[i], "\n"), "refs/tags/")
+
+		if parts[i+1] != "tag" {
+			// Lightweight tags point directly to a commit.
+			tags = append(tags, Tag{Name: name, Hash: parts[i+2]})
+			continue
+		}
+
+		date, err := parseRawDate(parts[i+7])
+		if err != nil {
+			return nil, fmt.Errorf("parsing date of tag %q: %w", name, err)
+		}
+
+		signature := parts[i+9]
+		tags = append(tags, Tag{
+			Name: name,
+			Hash: parts[i+4],
+			Annotation: &Annotation{
+				Hash:        parts[i+2],
+				TaggerName:  parts[i+5],
+				TaggerEmail: strings.TrimSuffix(strings.TrimPrefix(parts[i+6], "<"), ">"),
+				Date:        date,
+				Message:     strings.TrimSuffix(parts[i+8], signature),
+				Signature:   signature,
+			},
+		})
+
+		// Tags pointing to other tag objects are dereferenced once by for-each-ref, so they are peeled afterwards.
+		if parts[i+3] == "tag" {
+			nested = append(nested, len(tags)-1)
+		}
+	}
+
+	if len(nested) == 0 {
+		return tags, nil
+	}
+
+	args := []string{"rev-parse"}
+	for _, i := range nested {
+		args = append(args, "refs/tags/"+tags[i].Name+"^{}")
+	}
+
+	out, err = g.run(args...)
+	if err != nil {
+		return nil, fmt.Errorf("peeling nested tags: %w", err)
+	}
+
+	for n, hash := range strings.Fields(out) {
+		tags[nested[n]].Hash = hash
+	}
+
+	return tags, nil
+}
+
+// parseRawDate parses a date in git's raw format, e.g. `1672531200 +0100`. Empty dates, such as the ones of legacy
+// annotated tags without a tagger, are parsed as the zero time, as go-git does.
+func parseRawDate(raw string) (time.Time, error) {
+	if strings.TrimSpace(raw) == "" {
+		return time.Time{}, nil
+	}
+
+	parts := strings.Fields(raw)
+	if len(parts) != 2 {
+		return time.Time{}, fmt.Errorf("parsing date %q: %w", raw, ErrUnexpectedOutput)
+	}
+
+	seconds, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("parsing timestamp %q: %w", parts[0], err)
+	}
+
+	zone, err := time.Parse("-0700", parts[1])
+	if err != nil {
+		return time.Time{}, fmt.Errorf("parsing time zone %q: %w", parts[1], err)
+	}
+
+	return time.Unix(seconds, 0).In(zone.Location()), nil
+}
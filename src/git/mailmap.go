@@ -0,0 +1,202 @@
+package git
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// MailmapFile is the name of the file, in the root of the repository, that maps the names and emails authors used in
+// commits to their canonical ones.
+const MailmapFile = ".mailmap"
+
+var (
+	// personRegex matches a `Name <email>` string, such as the author of a commit, capturing the name and the email.
+	personRegex = regexp.MustCompile(`^(.*?)\s*<([^<>]*)>$`)
+	// mailmapPartRegex matches the optional name and the email of each half of a mailmap line.
+	mailmapPartRegex = regexp.MustCompile(`\s*([^<>#]*?)\s*<([^<>]*)>`)
+	// coAuthorRegex matches Co-authored-by trailers, capturing the co-author.
+	coAuthorRegex = regexp.MustCompile(`(?mi)^co-authored-by:[ \t]*(.*<[^<>]*>)[ \t]*$`)
+)
+
+// mailmapEntry holds the canonical name and email of an author. Empty fields are not replaced.
+type mailmapEntry struct {
+	name  string
+	email string
+}
+
+// Mailmap maps the names and emails authors used in commits to their canonical ones, following the format of the
+// .mailmap file described in gitmailmap(5). Names and emails are matched case-insensitively.
+type Mailmap struct {
+	// byEmail holds the entries that match an email with any name, keyed by lowercase email.
+	byEmail map[string]mailmapEntry
+	// byNameEmail holds the entries that match only a given name and email, keyed by lowercase name and email.
+	byNameEmail map[[2]string]mailmapEntry
+}
+
+// ParseMailmap reads a mailmap in the format of .mailmap files. Lines that cannot be parsed are ignored, as git does.
+func ParseMailmap(r io.Reader) (*Mailmap, error) {
+	m := &Mailmap{
+		byEmail:     map[string]mailmapEntry{},
+		byNameEmail: map[[2]string]mailmapEntry{},
+	}
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		parts := mailmapPartRegex.FindAllStringSubmatch(line, 2)
+		switch len(parts) {
+		case 1:
+			// `Proper Name <commit@email>` replaces the name of commits with that email.
+			m.add("", parts[0][2], mailmapEntry{name: parts[0][1]})
+		case 2:
+			// `[Proper Name] <proper@email> [Commit Name] <commit@email>` replaces the name, if set, and the email of
+			// commits with that email and, if set, that name.
+			m.add(parts[1][1], parts[1][2], mailmapEntry{name: parts[0][1], email: parts[0][2]})
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading mailmap: %w", err)
+	}
+
+	return m, nil
+}
+
+// ReadMailmap reads MailmapFile from workDir. An empty Mailmap is returned if it does not exist.
+func ReadMailmap(workDir string) (*Mailmap, error) {
+	file, err := os.Open(filepath.Join(workDir, MailmapFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return &Mailmap{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("opening mailmap: %w", err)
+	}
+	defer file.Close()
+
+	return ParseMailmap(file)
+}
+
+// add records the canonical name and email for commits with the given name and email. Fields set by later lines
+// override the ones set by previous lines for the same name and email.
+func (m *Mailmap) add(name, email string, entry mailmapEntry) {
+	email = strings.ToLower(email)
+
+	var existing mailmapEntry
+	if name == "" {
+		existing = m.byEmail[email]
+	} else {
+		existing = m.byNameEmail[[2]string{strings.ToLower(name), email}]
+	}
+
+	if entry.name != "" {
+		existing.name = entry.name
+	}
+	if entry.email != "" {
+		existing.email = entry.email
+	}
+
+	if name == "" {
+		m.byEmail[email] = existing
+	} else {
+		m.byNameEmail[[2]string{strings.ToLower(name), email}] = existing
+	}
+}
+
+// Resolve returns the canonical version of person, a `Name <email>` string such as Commit.Author. Entries matching
+// both the name and the email take precedence over the ones matching only the email. person is returned unchanged if
+// it is not in the mailmap.
+func (m *Mailmap) Resolve(person string) string {
+	if m == nil {
+		return person
+	}
+
+	name, email, ok := SplitPerson(person)
+	if !ok {
+		return person
+	}
+
+	entry, found := m.byNameEmail[[2]string{strings.ToLower(name), strings.ToLower(email)}]
+	if !found {
+		entry, found = m.byEmail[strings.ToLower(email)]
+	}
+	if !found {
+		return person
+	}
+
+	if entry.name != "" {
+		name = entry.name
+	}
+	if entry.email != "" {
+		email = entry.email
+	}
+
+	return fmt.Sprintf("%s <%s>", name, email)
+}
+
+// withAuthors returns commit with its author and co-authors resolved through the mailmap, and without co-authors that
+// are the author themselves or are repeated.
+func (m *Mailmap) withAuthors(commit Commit) Commit {
+	commit.Author = m.Resolve(commit.Author)
+
+	seen := map[string]bool{personEmail(commit.Author): true}
+	coAuthors := make([]string, 0, len(commit.CoAuthors))
+	for _, coAuthor := range commit.CoAuthors {
+		coAuthor = m.Resolve(coAuthor)
+		if seen[personEmail(coAuthor)] {
+			continue
+		}
+		seen[personEmail(coAuthor)] = true
+		coAuthors = append(coAuthors, coAuthor)
+	}
+
+	commit.CoAuthors = nil
+	if len(coAuthors) > 0 {
+		commit.CoAuthors = coAuthors
+	}
+
+	return commit
+}
+
+// coAuthors returns the co-authors credited in the Co-authored-by trailers of a commit message.
+func coAuthors(message string) []string {
+	matches := coAuthorRegex.FindAllStringSubmatch(message, -1)
+	if len(matches) == 0 {
+		return nil
+	}
+
+	authors := make([]string, 0, len(matches))
+	for _, match := range matches {
+		authors = append(authors, strings.TrimSpace(match[1]))
+	}
+
+	return authors
+}
+
+// SplitPerson returns the name and email of a `Name <email>` string.
+func SplitPerson(person string) (name, email string, ok bool) {
+	matches := personRegex.FindStringSubmatch(strings.TrimSpace(person))
+	if matches == nil {
+		return "", "", false
+	}
+
+	return matches[1], matches[2], true
+}
+
+// personEmail returns the lowercase email of a `Name <email>` string, or the whole string if it has no email.
+func personEmail(person string) string {
+	if _, email, ok := SplitPerson(person); ok {
+		return strings.ToLower(email)
+	}
+
+	return strings.ToLower(person)
+}
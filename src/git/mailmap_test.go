@@ -0,0 +1,86 @@
+package git_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/newrelic/release-toolkit/src/git"
+)
+
+func TestMailmap_Resolve(t *testing.T) {
+	t.Parallel()
+
+	mailmap, err := git.ParseMailmap(strings.NewReader(`
+# Replaces the name of commits with this email.
+Jane Doe <jane@example.com>
+# Replaces the email of commits with this email.
+<jane@example.com> <jane@old.example.com>
+# Replaces name and email of commits with this email.
+John Smith <john@example.com> <john@personal.tld>
+# Replaces name and email only of commits with this name and email.
+Bot <bot@example.com> ci <shared@example.com>
+<bot@example.com> CI <shared@example.com>
+not a valid line
+`))
+	if err != nil {
+		t.Fatalf("Error parsing mailmap: %v", err)
+	}
+
+	for _, tc := range []struct {
+		person   string
+		expected string
+	}{
+		{person: "jane <jane@example.com>", expected: "Jane Doe <jane@example.com>"},
+		{person: "jane <JANE@example.com>", expected: "Jane Doe <JANE@example.com>"},
+		{person: "Jane D <jane@old.example.com>", expected: "Jane D <jane@example.com>"},
+		{person: "johnny <john@personal.tld>", expected: "John Smith <john@example.com>"},
+		{person: "CI <shared@example.com>", expected: "Bot <bot@example.com>"},
+		{person: "Someone <shared@example.com>", expected: "Someone <shared@example.com>"},
+		{person: "Unknown <unknown@example.com>", expected: "Unknown <unknown@example.com>"},
+		{person: "No email", expected: "No email"},
+	} {
+		if actual := mailmap.Resolve(tc.person); actual != tc.expected {
+			t.Errorf("Expected %q to resolve to %q, got %q", tc.person, tc.expected, actual)
+		}
+	}
+}
+
+func TestReadMailmap_Missing(t *testing.T) {
+	t.Parallel()
+
+	mailmap, err := git.ReadMailmap(t.TempDir())
+	if err != nil {
+		t.Fatalf("Error reading mailmap: %v", err)
+	}
+
+	if actual := mailmap.Resolve("Jane <jane@example.com>"); actual != "Jane <jane@example.com>" {
+		t.Fatalf("Expected empty mailmap to keep authors, got %q", actual)
+	}
+}
+
+func TestSplitPerson(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		person string
+		name   string
+		email  string
+		ok     bool
+	}{
+		{person: "Jane Doe <jane@example.com>", name: "Jane Doe", email: "jane@example.com", ok: true},
+		{person: "  Jane Doe   <jane@example.com> ", name: "Jane Doe", email: "jane@example.com", ok: true},
+		{person: "<jane@example.com>", email: "jane@example.com", ok: true},
+		{person: "Jane Doe"},
+		{person: "Jane Doe <jane@example.com> extra"},
+	} {
+		tc := tc
+		t.Run(tc.person, func(t *testing.T) {
+			t.Parallel()
+
+			name, email, ok := git.SplitPerson(tc.person)
+			if name != tc.name || email != tc.email || ok != tc.ok {
+				t.Fatalf("Expected (%q, %q, %v), got (%q, %q, %v)", tc.name, tc.email, tc.ok, name, email, ok)
+			}
+		})
+	}
+}
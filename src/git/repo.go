@@ -0,0 +1,158 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"gopkg.in/src-d/go-git.v4"
+)
+
+// Backend selects how a Repo reads the repository.
+type Backend string
+
+const (
+	// BackendNative reads the repository with go-git, without needing the git binary.
+	BackendNative Backend = "native"
+	// BackendExec runs the git binary, which supports repository features go-git does not.
+	BackendExec Backend = "exec"
+)
+
+// ErrUnknownBackend is returned when a Repo is requested for a Backend that does not exist.
+var ErrUnknownBackend = errors.New("unknown git backend")
+
+// Repo gives access to the commits and tags of a git repository that is opened only once, and whose history and tags
+// are read only once no matter how many times they are requested. It is meant to be shared by all the sources and
+// components a command processes.
+// Repo implements CommitsGetter, RangeCommitsGetter and TagsGetter. Filtered TagsGetters, which can be used to build
+// TagsVersionGetters with NewTagsSource, are returned by Repo.TagsGetter.
+type Repo struct {
+	workDir string
+	commits *CachedCommitsGetter
+	// readTags and readHead read all the tags and the commit HEAD points to with the backend of the repository.
+	readTags func() ([]Tag, error)
+	readHead func() (string, error)
+
+	openOnce sync.Once
+	repo     *git.Repository
+	openErr  error
+
+	tagsOnce sync.Once
+	tags     []Tag
+	tagsErr  error
+}
+
+// NewRepo returns a Repo for the git repository at workDir read with BackendNative, which is opened the first time it
+// is needed. Options apply to the commits returned by the repository.
+func NewRepo(workDir string, opts ...CommitsOptionFunc) *Repo {
+	r := &Repo{
+		workDir: workDir,
+	}
+
+	commitsGetter := NewRepoCommitsGetter(workDir, opts...)
+	commitsGetter.opener = r.open
+	r.commits = NewCachedCommitsGetter(commitsGetter)
+	r.readTags = r.nativeTags
+	r.readHead = r.nativeHead
+
+	return r
+}
+
+// NewRepoWithBackend returns a Repo for the git repository at workDir read with backend. Options apply to the commits
+// returned by the repository.
+func NewRepoWithBackend(workDir string, backend Backend, opts ...CommitsOptionFunc) (*Repo, error) {
+	switch backend {
+	case BackendNative:
+		return NewRepo(workDir, opts...), nil
+	case BackendExec:
+		cmd := gitCmd{workDir: workDir}
+		return &Repo{
+			workDir:  workDir,
+			commits:  NewCachedCommitsGetter(NewExecCommitsGetter(workDir, opts...)),
+			readTags: cmd.tags,
+			readHead: cmd.head,
+		}, nil
+	default:
+		return nil, fmt.Errorf("%w %q, expected %q or %q", ErrUnknownBackend, backend, BackendNative, BackendExec)
+	}
+}
+
+// Commits returns the commits reachable from HEAD that are not reachable from lastHash, or all of them if lastHash is
+// empty. The whole history is walked the first time it is called.
+func (r *Repo) Commits(lastHash string) ([]Commit, error) {
+	return r.commits.Commits(lastHash)
+}
+
+// CommitsInRange returns the commits in rng, walking the history only the first time a given range is requested.
+func (r *Repo) CommitsInRange(rng Range) ([]Commit, error) {
+	return r.commits.CommitsInRange(rng)
+}
+
+// Tags returns all the tags in the repository, with annotated tags peeled to their commits.
+func (r *Repo) Tags() ([]Tag, error) {
+	r.tagsOnce.Do(func() {
+		r.tags, r.tagsErr = r.readTags()
+	})
+
+	if r.tagsErr != nil {
+		return nil, r.tagsErr
+	}
+
+	return r.tags, nil
+}
+
+// TagsGetter returns a TagsGetter for the tags of the repository that match opts, the same options accepted by
+// NewRepoTagsGetter. Tags are not read again from the repository.
+func (r *Repo) TagsGetter(opts ...TagOptionFunc) (*RepoTagsGetter, error) {
+	return newRepoTagsGetter(r.Tags, opts...)
+}
+
+// Shallow returns whether the repository is a shallow clone whose history from HEAD is cut, and the depth of the
+// history available.
+func (r *Repo) Shallow() (bool, int, error) {
+	commits, err := r.Commits("")
+	if err != nil {
+		return false, 0, err
+	}
+
+	head, err := r.readHead()
+	if err != nil {
+		return false, 0, err
+	}
+
+	return historyDepth(commits, head)
+}
+
+func (r *Repo) nativeTags() ([]Tag, error) {
+	repo, err := r.open()
+	if err != nil {
+		return nil, err
+	}
+
+	return listTags(repo)
+}
+
+func (r *Repo) nativeHead() (string, error) {
+	repo, err := r.open()
+	if err != nil {
+		return "", err
+	}
+
+	head, err := repo.Head()
+	if err != nil {
+		return "", fmt.Errorf("getting HEAD: %w", err)
+	}
+
+	return head.Hash().String(), nil
+}
+
+func (r *Repo) open() (*git.Repository, error) {
+	r.openOnce.Do(func() {
+		r.repo, r.openErr = git.PlainOpen(r.workDir)
+		if r.openErr != nil {
+			r.openErr = fmt.Errorf("opening git repo at %s: %w", r.workDir, r.openErr)
+		}
+	})
+
+	return r.repo, r.openErr
+}
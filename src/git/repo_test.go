@@ -0,0 +1,87 @@
+package git_test
+
+import (
+	"testing"
+
+	"github.com/newrelic/release-toolkit/src/git"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRepo(t *testing.T) {
+	t.Parallel()
+
+	repodir := repoWithCommitsAndTags(t,
+		testCommitTag{"v1.2.3", []string{"v1.2.3"}},
+		testCommitTag{"v1.3.0", []string{"v1.3.0"}},
+		testCommitTag{"helm-chart-1.3.0", []string{"helm-chart-1.3.0"}},
+	)
+	executeCMDs(t, []string{
+		"git checkout -b other",
+		"git commit --allow-empty -m other",
+		"git tag v9.9.9",
+		"git checkout -",
+	}, repodir)
+
+	repo := git.NewRepo(repodir)
+
+	tagsGetter, err := repo.TagsGetter(git.TagsMatchingRegex("^v"), git.TagsMatchingCommits(repo))
+	if err != nil {
+		t.Fatalf("Error creating tags getter: %v", err)
+	}
+
+	src := git.NewTagsSource(tagsGetter)
+	versions, err := src.Versions()
+	if err != nil {
+		t.Fatalf("Error fetching versions: %v", err)
+	}
+
+	strVersions := make([]string, 0, len(versions))
+	for _, v := range versions {
+		strVersions = append(strVersions, v.String())
+	}
+	assert.Equal(t, []string{"1.3.0", "1.2.3"}, strVersions, "Tags not matching options should be skipped")
+
+	lastHash, err := src.LastVersionHash()
+	if err != nil {
+		t.Fatalf("Error fetching last version hash: %v", err)
+	}
+
+	commits, err := repo.Commits(lastHash)
+	if err != nil {
+		t.Fatalf("Error fetching commits: %v", err)
+	}
+	if len(commits) != 1 || commits[0].Message != "helm-chart-1.3.0" {
+		t.Fatalf("Unexpected commits since %s: %v", lastHash, commits)
+	}
+
+	// History and tags are read once, so changes made to the repository afterwards are not seen.
+	executeCMDs(t, []string{
+		"git commit --allow-empty -m new",
+		"git tag v2.0.0",
+	}, repodir)
+
+	allCommits, err := repo.Commits("")
+	if err != nil {
+		t.Fatalf("Error fetching commits: %v", err)
+	}
+	assert.Len(t, allCommits, 3, "History should have been read only once")
+
+	tags, err := repo.Tags()
+	if err != nil {
+		t.Fatalf("Error fetching tags: %v", err)
+	}
+	assert.Len(t, tags, 4, "Tags should have been read only once")
+}
+
+func TestRepo_Not_A_Repository(t *testing.T) {
+	t.Parallel()
+
+	// Opening the repository is deferred until it is needed.
+	repo := git.NewRepo(t.TempDir())
+
+	_, err := repo.Commits("")
+	assert.Error(t, err)
+
+	_, err = repo.Tags()
+	assert.Error(t, err)
+}
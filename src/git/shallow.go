@@ -0,0 +1,136 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+	"gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/plumbing"
+)
+
+// ErrShallow is matched by ShallowError, returned when the history needed is beyond the boundary of a shallow clone.
+var ErrShallow = errors.New("repository is a shallow clone")
+
+// ShallowError is returned when commits since a given one cannot be listed because the repository is a shallow clone,
+// and that commit is beyond the boundary of the fetched history.
+type ShallowError struct {
+	// Hash is the commit that could not be reached, or empty if no previous version was found at all.
+	Hash string
+	// Depth is the number of commits of history available from the tip.
+	Depth int
+}
+
+func (e *ShallowError) Error() string {
+	target := "no previous version was found in it"
+	if e.Hash != "" {
+		target = fmt.Sprintf("commit %s is beyond it", e.Hash)
+	}
+
+	return fmt.Sprintf(
+		"%v with %d commits of history and %s: fetch a depth greater than %d with `git fetch --deepen`, "+
+			"or the whole history and tags with `git fetch --unshallow --tags` (`fetch-depth: 0` in actions/checkout)",
+		ErrShallow, e.Depth, target, e.Depth,
+	)
+}
+
+func (e *ShallowError) Unwrap() error {
+	return ErrShallow
+}
+
+// shallowCommits returns the commits at the boundary of a shallow clone, whose parents are not in the repository.
+// It is empty for complete repositories.
+func shallowCommits(repo *git.Repository) (map[plumbing.Hash]bool, error) {
+	hashes, err := repo.Storer.Shallow()
+	if err != nil {
+		return nil, fmt.Errorf("reading shallow commits: %w", err)
+	}
+
+	shallow := make(map[plumbing.Hash]bool, len(hashes))
+	for _, h := range hashes {
+		shallow[h] = true
+	}
+
+	return shallow, nil
+}
+
+// ShallowTolerantCommitsGetter wraps a CommitsGetter so that, when commits since the last version cannot be listed
+// because the repository is a shallow clone, all the commits available are returned instead of a ShallowError.
+type ShallowTolerantCommitsGetter struct {
+	getter CommitsGetter
+	err    *ShallowError
+}
+
+func NewShallowTolerantCommitsGetter(getter CommitsGetter) *ShallowTolerantCommitsGetter {
+	return &ShallowTolerantCommitsGetter{
+		getter: getter,
+	}
+}
+
+// Commits returns the commits from the wrapped getter, or all the commits available if it returns a ShallowError.
+func (g *ShallowTolerantCommitsGetter) Commits(lastHash string) ([]Commit, error) {
+	commits, err := g.getter.Commits(lastHash)
+
+	var shallowErr *ShallowError
+	if !errors.As(err, &shallowErr) {
+		//nolint:wrapcheck // Errors are returned as if the wrapped getter was used directly.
+		return commits, err
+	}
+
+	log.Warnf("Using all the commits available: %v", shallowErr)
+	g.err = shallowErr
+
+	commits, err = g.getter.Commits("")
+	if err != nil {
+		return nil, fmt.Errorf("getting commits available in shallow clone: %w", err)
+	}
+
+	return commits, nil
+}
+
+// ShallowErr returns the error returned by the wrapped getter if it hit the boundary of a shallow clone, or nil.
+func (g *ShallowTolerantCommitsGetter) ShallowErr() *ShallowError {
+	return g.err
+}
+
+// historyDepth returns whether commits reach the boundary of a shallow clone and, if they do, the length of the
+// longest path of commits from tip.
+func historyDepth(commits []Commit, tip string) (bool, int, error) {
+	byHash := make(map[string]Commit, len(commits))
+	shallow := false
+	for _, c := range commits {
+		byHash[c.Hash] = c
+		shallow = shallow || c.shallow
+	}
+
+	if !shallow {
+		return false, 0, nil
+	}
+
+	depths := map[string]int{}
+	depth := 0
+	pending := []string{tip}
+	depths[tip] = 1
+	for len(pending) > 0 {
+		hash := pending[0]
+		pending = pending[1:]
+
+		commit, found := byHash[hash]
+		if !found {
+			continue
+		}
+
+		if depths[hash] > depth {
+			depth = depths[hash]
+		}
+
+		for _, parent := range commit.Parents {
+			if _, visited := depths[parent]; !visited {
+				depths[parent] = depths[hash] + 1
+				pending = append(pending, parent)
+			}
+		}
+	}
+
+	return true, depth, nil
+}
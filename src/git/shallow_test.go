@@ -0,0 +1,125 @@
+package git_test
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"testing"
+
+	"github.com/newrelic/release-toolkit/src/git"
+	"github.com/stretchr/testify/assert"
+)
+
+// shallowClone creates a repository with a tagged commit (v1.0.0) followed by five commits, and returns a shallow clone
+// of it with the given depth along with the hash of the tagged commit.
+func shallowClone(t *testing.T, depth int) (string, string) {
+	t.Helper()
+
+	origin := repoWithTags(t, "v1.0.0")
+	for i := 0; i < 5; i++ {
+		executeCMDs(t, []string{
+			fmt.Sprintf("touch file%d", i),
+			fmt.Sprintf("git add file%d", i),
+			fmt.Sprintf("git commit -m commit%d", i),
+		}, origin)
+	}
+
+	clone := path.Join(t.TempDir(), "clone")
+	executeCMDs(t, []string{
+		fmt.Sprintf("git clone --quiet --depth %d file://%s %s", depth, origin, clone),
+	}, t.TempDir())
+
+	return clone, gitOutput(t, origin, "", "rev-parse", "v1.0.0")
+}
+
+func TestRepoCommitsGetter_Shallow(t *testing.T) {
+	t.Parallel()
+
+	clone, tagHash := shallowClone(t, 3)
+
+	_, err := git.NewRepoCommitsGetter(clone).Commits(tagHash)
+	if !errors.Is(err, git.ErrShallow) {
+		t.Fatalf("Expected a shallow error, got %v", err)
+	}
+
+	var shallowErr *git.ShallowError
+	if !errors.As(err, &shallowErr) {
+		t.Fatalf("Expected a ShallowError, got %T", err)
+	}
+	assert.Equal(t, tagHash, shallowErr.Hash)
+	assert.Equal(t, 3, shallowErr.Depth)
+	assert.Contains(t, err.Error(), "greater than 3")
+
+	// Commits within the fetched history are still returned.
+	head := gitOutput(t, clone, "", "rev-parse", "HEAD~1")
+	commits, err := git.NewRepoCommitsGetter(clone).Commits(head)
+	if err != nil {
+		t.Fatalf("Error fetching commits within the shallow history: %v", err)
+	}
+	assert.Len(t, commits, 1)
+
+	// The cache returns the same error.
+	_, err = git.NewCachedCommitsGetter(git.NewRepoCommitsGetter(clone)).Commits(tagHash)
+	assert.ErrorIs(t, err, git.ErrShallow)
+}
+
+func TestRepoCommitsGetter_Shallow_Deep_Enough(t *testing.T) {
+	t.Parallel()
+
+	clone, tagHash := shallowClone(t, 6)
+
+	commits, err := git.NewRepoCommitsGetter(clone).Commits(tagHash)
+	if err != nil {
+		t.Fatalf("Error fetching commits: %v", err)
+	}
+	assert.Len(t, commits, 5)
+}
+
+func TestShallowTolerantCommitsGetter(t *testing.T) {
+	t.Parallel()
+
+	clone, tagHash := shallowClone(t, 3)
+
+	tolerant := git.NewShallowTolerantCommitsGetter(git.NewRepoCommitsGetter(clone))
+	commits, err := tolerant.Commits(tagHash)
+	if err != nil {
+		t.Fatalf("Error fetching commits: %v", err)
+	}
+
+	messages := make([]string, 0, len(commits))
+	for _, c := range commits {
+		messages = append(messages, c.Message)
+	}
+	assert.Equal(t, []string{"commit4", "commit3", "commit2"}, messages)
+
+	// The boundary commit is compared to the empty tree.
+	files, err := commits[2].ChangedFiles()
+	if err != nil {
+		t.Fatalf("Error getting changed files: %v", err)
+	}
+	assert.Contains(t, files, "file2")
+
+	if tolerant.ShallowErr() == nil {
+		t.Fatalf("Expected the shallow error to be kept")
+	}
+	assert.Equal(t, 3, tolerant.ShallowErr().Depth)
+}
+
+func TestRepo_Shallow(t *testing.T) {
+	t.Parallel()
+
+	clone, _ := shallowClone(t, 2)
+
+	shallow, depth, err := git.NewRepo(clone).Shallow()
+	if err != nil {
+		t.Fatalf("Error checking shallow repository: %v", err)
+	}
+	assert.True(t, shallow)
+	assert.Equal(t, 2, depth)
+
+	shallow, _, err = git.NewRepo(repoWithTags(t, "v1.0.0")).Shallow()
+	if err != nil {
+		t.Fatalf("Error checking repository: %v", err)
+	}
+	assert.False(t, shallow)
+}
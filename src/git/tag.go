@@ -1,17 +1,34 @@
 package git
 
 import (
+	"errors"
 	"fmt"
 	"regexp"
+	"time"
 
 	log "github.com/sirupsen/logrus"
 	"gopkg.in/src-d/go-git.v4"
 	"gopkg.in/src-d/go-git.v4/plumbing"
 )
 
+// Tag is a git tag. Hash is always the hash of the commit the tag points to, also for annotated tags.
 type Tag struct {
 	Name string
 	Hash string
+	// Annotation holds the metadata of the tag object for annotated tags, and is nil for lightweight tags.
+	Annotation *Annotation
+}
+
+// Annotation holds the metadata stored in the object of an annotated tag.
+type Annotation struct {
+	// Hash is the hash of the tag object itself.
+	Hash        string
+	TaggerName  string
+	TaggerEmail string
+	Date        time.Time
+	Message     string
+	// Signature is the armored PGP signature of the tag, if it is signed.
+	Signature string
 }
 
 type TagsGetter interface {
@@ -19,7 +36,8 @@ type TagsGetter interface {
 }
 
 type RepoTagsGetter struct {
-	workDir      string
+	// allTags returns all the tags in the repository, before filtering them.
+	allTags      func() ([]Tag, error)
 	matchRegex   *regexp.Regexp
 	matchCommits map[string]bool
 }
@@ -59,8 +77,21 @@ func TagsMatchingCommits(getter CommitsGetter) TagOptionFunc {
 var MatchAllTags = regexp.MustCompile("")
 
 func NewRepoTagsGetter(workDir string, opts ...TagOptionFunc) (*RepoTagsGetter, error) {
+	allTags := func() ([]Tag, error) {
+		repo, err := git.PlainOpen(workDir)
+		if err != nil {
+			return nil, fmt.Errorf("opening git repo at %s: %w", workDir, err)
+		}
+
+		return listTags(repo)
+	}
+
+	return newRepoTagsGetter(allTags, opts...)
+}
+
+func newRepoTagsGetter(allTags func() ([]Tag, error), opts ...TagOptionFunc) (*RepoTagsGetter, error) {
 	s := &RepoTagsGetter{
-		workDir:    workDir,
+		allTags:    allTags,
 		matchRegex: MatchAllTags,
 	}
 
@@ -73,12 +104,35 @@ func NewRepoTagsGetter(workDir string, opts ...TagOptionFunc) (*RepoTagsGetter,
 	return s, nil
 }
 
+// Tags returns the tags in the repository that match the options the getter was created with.
 func (s *RepoTagsGetter) Tags() ([]Tag, error) {
-	repo, err := git.PlainOpen(s.workDir)
+	allTags, err := s.allTags()
 	if err != nil {
-		return nil, fmt.Errorf("opening git repo at %s: %w", s.workDir, err)
+		return nil, err
+	}
+
+	var tags []Tag
+	for _, tag := range allTags {
+		if !s.matchRegex.MatchString(tag.Name) {
+			log.Debugf("skipping tag %q as it does not match %q", tag.Name, s.matchRegex.String())
+			continue
+		}
+
+		if s.matchCommits != nil {
+			if _, ok := s.matchCommits[tag.Hash]; !ok {
+				log.Infof("Ignoring %s since it belongs to a different branch", tag.Name)
+				continue
+			}
+		}
+
+		tags = append(tags, tag)
 	}
 
+	return tags, nil
+}
+
+// listTags returns all the tags in repo, with annotated tags peeled to their commits.
+func listTags(repo *git.Repository) ([]Tag, error) {
 	repoTags, err := repo.Tags()
 	if err != nil {
 		return nil, fmt.Errorf("getting git tags: %w", err)
@@ -93,23 +147,12 @@ func (s *RepoTagsGetter) Tags() ([]Tag, error) {
 			return nil
 		}
 
-		tagName := ref.Short()
-		if !s.matchRegex.MatchString(tagName) {
-			log.Debugf("skipping tag %q as it does not match %q", tagName, s.matchRegex.String())
-			return nil
-		}
-
-		if s.matchCommits != nil {
-			if _, ok := s.matchCommits[reference.Hash().String()]; !ok {
-				log.Infof("Ignoring %s since it belongs to a different branch", reference.Name().Short())
-				return nil
-			}
+		tag, innerErr := peel(repo, ref.Short(), reference.Hash())
+		if innerErr != nil {
+			return innerErr
 		}
 
-		tags = append(tags, Tag{
-			Name: tagName,
-			Hash: reference.Hash().String(),
-		})
+		tags = append(tags, tag)
 
 		return nil
 	})
@@ -119,3 +162,44 @@ func (s *RepoTagsGetter) Tags() ([]Tag, error) {
 
 	return tags, nil
 }
+
+// peel returns the Tag for a reference pointing to hash. If hash is the hash of a tag object, i.e. the tag is annotated,
+// it is followed until a commit is reached and the metadata of the tag object is kept in Tag.Annotation.
+func peel(repo *git.Repository, name string, hash plumbing.Hash) (Tag, error) {
+	tagObj, err := repo.TagObject(hash)
+	if errors.Is(err, plumbing.ErrObjectNotFound) {
+		// Lightweight tags point directly to a commit.
+		return Tag{Name: name, Hash: hash.String()}, nil
+	}
+	if err != nil {
+		return Tag{}, fmt.Errorf("getting tag object for %q: %w", name, err)
+	}
+
+	tag := Tag{
+		Name: name,
+		Annotation: &Annotation{
+			Hash:        tagObj.Hash.String(),
+			TaggerName:  tagObj.Tagger.Name,
+			TaggerEmail: tagObj.Tagger.Email,
+			Date:        tagObj.Tagger.When,
+			Message:     tagObj.Message,
+			Signature:   tagObj.PGPSignature,
+		},
+	}
+
+	// Tags can point to other tag objects, so keep peeling until something that is not a tag is found.
+	target := tagObj
+	for target.TargetType == plumbing.TagObject {
+		target, err = repo.TagObject(target.Target)
+		if err != nil {
+			return Tag{}, fmt.Errorf("peeling tag %q: %w", name, err)
+		}
+	}
+
+	if target.TargetType != plumbing.CommitObject {
+		log.Debugf("Tag %q points to a %s, not to a commit", name, target.TargetType)
+	}
+
+	tag.Hash = target.Target.String()
+	return tag, nil
+}
@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"github.com/Masterminds/semver"
+	"github.com/newrelic/release-toolkit/src/version"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -22,6 +23,8 @@ type TagsVersionGetter interface {
 type TagsSource struct {
 	tagsGetter TagsGetter
 	replacer   *strings.Replacer
+	scheme     version.Scheme
+	constraint *version.Constraint
 }
 
 type TagSourceOptionFunc func(s *TagsSource)
@@ -35,10 +38,26 @@ func TagSourceReplacing(existing, replacement string) TagSourceOptionFunc {
 	}
 }
 
+// TagSourceScheme returns an option that will parse tags using the specified versioning scheme instead of semver.
+func TagSourceScheme(scheme version.Scheme) TagSourceOptionFunc {
+	return func(s *TagsSource) {
+		s.scheme = scheme
+	}
+}
+
+// TagSourceConstraint returns an option that will skip versions not satisfying the constraint, so versions from other
+// release lines, such as v2.3.0 reachable from a 1.x maintenance branch through a merge, are ignored.
+func TagSourceConstraint(constraint *version.Constraint) TagSourceOptionFunc {
+	return func(s *TagsSource) {
+		s.constraint = constraint
+	}
+}
+
 func NewTagsSource(tagsGetter TagsGetter, opts ...TagSourceOptionFunc) *TagsSource {
 	ts := &TagsSource{
 		tagsGetter: tagsGetter,
 		replacer:   strings.NewReplacer(),
+		scheme:     version.Semver{},
 	}
 
 	for _, opt := range opts {
@@ -48,6 +67,9 @@ func NewTagsSource(tagsGetter TagsGetter, opts ...TagSourceOptionFunc) *TagsSour
 	return ts
 }
 
+// Versions returns the versions found in the repository tags, sorted from largest to smallest following semver
+// precedence rules: prereleases sort before their associated release (v2.0.0-rc.2 < v2.0.0), and their numeric
+// identifiers are compared numerically (v2.0.0-rc.10 > v2.0.0-rc.9).
 func (s *TagsSource) Versions() ([]*semver.Version, error) {
 	tags, err := s.tagsGetter.Tags()
 	if err != nil {
@@ -56,17 +78,18 @@ func (s *TagsSource) Versions() ([]*semver.Version, error) {
 
 	versions := make([]*semver.Version, 0, len(tags))
 	for _, tag := range tags {
-		tagName := s.replacer.Replace(tag.Name)
-
-		v, innerErr := semver.NewVersion(tagName)
-		if innerErr != nil {
-			log.Infof("skipping tag %q as it does not conform to semver %v", tagName, innerErr)
+		v, ok := s.version(tag)
+		if !ok {
 			continue
 		}
 
 		versions = append(versions, v)
 	}
 
+	sort.SliceStable(versions, func(i, j int) bool {
+		return greater(versions[i], versions[j])
+	})
+
 	return versions, nil
 }
 
@@ -83,11 +106,8 @@ func (s *TagsSource) LastVersionHash() (string, error) {
 
 	versions := make([]semverTag, 0, len(tags))
 	for _, tag := range tags {
-		tagName := s.replacer.Replace(tag.Name)
-
-		v, innerErr := semver.NewVersion(tagName)
-		if innerErr != nil {
-			log.Infof("skipping tag %q as it does not conform to semver %v", tagName, innerErr)
+		v, ok := s.version(tag)
+		if !ok {
 			continue
 		}
 
@@ -96,9 +116,9 @@ func (s *TagsSource) LastVersionHash() (string, error) {
 		})
 	}
 
-	sort.Slice(versions, func(i, j int) bool {
+	sort.SliceStable(versions, func(i, j int) bool {
 		// Inverted less to sort from newer to older.
-		return versions[i].version.GreaterThan(versions[j].version)
+		return greater(versions[i].version, versions[j].version)
 	})
 
 	if len(versions) == 0 {
@@ -107,3 +127,32 @@ func (s *TagsSource) LastVersionHash() (string, error) {
 
 	return versions[0].tag.Hash, nil
 }
+
+// version parses the version of tag, returning false if it does not conform to the versioning scheme or does not
+// satisfy the constraint.
+func (s *TagsSource) version(tag Tag) (*semver.Version, bool) {
+	tagName := s.replacer.Replace(tag.Name)
+
+	v, err := s.scheme.Parse(tagName)
+	if err != nil {
+		log.Infof("skipping tag %q as it does not conform to the versioning scheme: %v", tagName, err)
+		return nil, false
+	}
+
+	if s.constraint != nil && !s.constraint.Check(v) {
+		log.Infof("skipping tag %q as it does not satisfy the version constraint %q", tagName, s.constraint)
+		return nil, false
+	}
+
+	return v, true
+}
+
+// greater returns whether a has a higher precedence than b. Versions with the same precedence, such as `v1.2.3` and
+// `1.2.3+build`, are sorted by their original string so the order does not depend on the order tags were listed in.
+func greater(a, b *semver.Version) bool {
+	if cmp := a.Compare(b); cmp != 0 {
+		return cmp > 0
+	}
+
+	return a.Original() > b.Original()
+}
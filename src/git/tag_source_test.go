@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/newrelic/release-toolkit/src/git"
+	"github.com/newrelic/release-toolkit/src/version"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -106,6 +107,27 @@ func TestTagSource_Versions(t *testing.T) {
 				"1.3.0",
 			},
 		},
+		{
+			name: "Constraint_Excludes_Next_Major",
+			tagSourceOpts: []git.TagSourceOptionFunc{
+				git.TagSourceConstraint(mustConstraint(t, "<2.0.0")),
+			},
+			expectedTags: []string{
+				"1.5.0",
+				"1.4.0",
+				"1.3.0",
+				"1.2.3",
+			},
+		},
+		{
+			name: "Constraint_Matching_Minor",
+			tagSourceOpts: []git.TagSourceOptionFunc{
+				git.TagSourceConstraint(mustConstraint(t, "~1.3")),
+			},
+			expectedTags: []string{
+				"1.3.0",
+			},
+		},
 	} {
 		tc := tc
 		t.Run(tc.name, func(t *testing.T) {
@@ -133,6 +155,69 @@ func TestTagSource_Versions(t *testing.T) {
 	}
 }
 
+func TestTagSource_Versions_Precedence(t *testing.T) {
+	t.Parallel()
+
+	repodir := repoWithTags(t,
+		"v1.4.0",
+		"v2.0.0-rc.10",
+		"v2.0.0-rc.9",
+		"v2.0.0",
+		"v2.0.0-rc.1",
+		"v2.0.0-beta.2",
+		"v1.5.0-rc.1",
+	)
+
+	tagsGetter, err := git.NewRepoTagsGetter(repodir)
+	if err != nil {
+		t.Fatalf("Error creating git source: %v", err)
+	}
+
+	versions, err := git.NewTagsSource(tagsGetter).Versions()
+	if err != nil {
+		t.Fatalf("Error fetching tags: %v", err)
+	}
+
+	strVersions := make([]string, 0, len(versions))
+	for _, v := range versions {
+		strVersions = append(strVersions, v.Original())
+	}
+
+	expected := []string{
+		"v2.0.0",
+		"v2.0.0-rc.10",
+		"v2.0.0-rc.9",
+		"v2.0.0-rc.1",
+		"v2.0.0-beta.2",
+		"v1.5.0-rc.1",
+		"v1.4.0",
+	}
+	assert.Equal(t, expected, strVersions, "Versions are not sorted by precedence")
+}
+
+func TestTagSource_Versions_Scheme(t *testing.T) {
+	t.Parallel()
+
+	repodir := repoWithTags(t, "v1.2.3", "v2024.2.0", "v2024.3.1", "v2024.13.0")
+
+	tagsGetter, err := git.NewRepoTagsGetter(repodir)
+	if err != nil {
+		t.Fatalf("Error creating git source: %v", err)
+	}
+
+	versions, err := git.NewTagsSource(tagsGetter, git.TagSourceScheme(version.CalVer{})).Versions()
+	if err != nil {
+		t.Fatalf("Error fetching tags: %v", err)
+	}
+
+	strVersions := make([]string, 0, len(versions))
+	for _, v := range versions {
+		strVersions = append(strVersions, v.Original())
+	}
+
+	assert.Equal(t, []string{"v2024.3.1", "v2024.2.0"}, strVersions, "Tags not following the scheme were not skipped")
+}
+
 func TestTagSource_DifferentBranch(t *testing.T) {
 	t.Parallel()
 
@@ -229,6 +314,23 @@ func TestRepoTagsSource_LastVersionHash(t *testing.T) {
 				git.TagsMatchingRegex("^helm-chart-"),
 			),
 		},
+		{
+			name: "Constraint_Excludes_Next_Major",
+			tagSourceOpts: []git.TagSourceOptionFunc{
+				git.TagSourceConstraint(mustConstraint(t, "<2.0.0")),
+			},
+			expectedHash: getVersionCommitHash(t, repodir, "1.5.0"),
+		},
+		{
+			name: "Constraint_Matching_Minor",
+			tagOpts: []git.TagOptionFunc{
+				git.TagsMatchingRegex("^v"),
+			},
+			tagSourceOpts: []git.TagSourceOptionFunc{
+				git.TagSourceConstraint(mustConstraint(t, "~1.3")),
+			},
+			expectedHash: getVersionCommitHash(t, repodir, "v1.3.0"),
+		},
 		{
 			name: "No_Versions_Found",
 			tagOpts: []git.TagOptionFunc{
@@ -257,6 +359,17 @@ func TestRepoTagsSource_LastVersionHash(t *testing.T) {
 	}
 }
 
+func mustConstraint(t *testing.T, constraint string) *version.Constraint {
+	t.Helper()
+
+	c, err := version.NewConstraint(constraint)
+	if err != nil {
+		t.Fatalf("Error parsing constraint: %v", err)
+	}
+
+	return c
+}
+
 func getVersionCommitHash(t *testing.T, repodir, version string, opts ...git.TagOptionFunc) string {
 	t.Helper()
 
@@ -0,0 +1,169 @@
+package git_test
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/newrelic/release-toolkit/src/git"
+	"github.com/stretchr/testify/assert"
+)
+
+const testSignature = `-----BEGIN PGP SIGNATURE-----
+
+iQEzBAABCAAdFiEEtestsignaturetestsignaturetestsignatureAAoJEAAAAAAA
+=test
+-----END PGP SIGNATURE-----
+`
+
+// repoWithAnnotatedTags creates a repository with two commits. The first one is tagged with a lightweight tag
+// (v1.0.0), an annotated tag (v1.1.0) and an annotated tag pointing to the latter (v1.1.0-nested). The second one is
+// tagged with a signed tag (v1.2.0) created with git mktag, and an annotated tag in a different branch (v9.9.9).
+func repoWithAnnotatedTags(t *testing.T) string {
+	t.Helper()
+
+	repodir := repoWithTags(t, "v1.0.0")
+	executeCMDs(t, []string{
+		"git tag -a v1.1.0 -m Release",
+		"git tag -a v1.1.0-nested v1.1.0 -m Nested",
+		"touch b",
+		"git add b",
+		"git commit -m second",
+	}, repodir)
+
+	signedTag := "object " + gitOutput(t, repodir, "", "rev-parse", "HEAD") + "\n" +
+		"type commit\n" +
+		"tag v1.2.0\n" +
+		"tagger Signer <signer@user.tld> 1672531200 +0100\n" +
+		"\n" +
+		"Signed release\n" +
+		testSignature
+	signedHash := gitOutput(t, repodir, signedTag, "mktag")
+
+	executeCMDs(t, []string{
+		"git tag v1.2.0 " + signedHash,
+		"git checkout -b different/branch",
+		"touch c",
+		"git add c",
+		"git commit -m third",
+		"git tag -a v9.9.9 -m Other",
+		"git checkout master",
+	}, repodir)
+
+	return repodir
+}
+
+// gitOutput runs git with args in dir, feeding it stdin, and returns its trimmed standard output.
+func gitOutput(t testing.TB, dir, stdin string, args ...string) string {
+	t.Helper()
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Stdin = strings.NewReader(stdin)
+
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("Error running git %v: %v", args, err)
+	}
+
+	return strings.TrimSpace(string(out))
+}
+
+func TestRepoTagsGetter_Annotated_Tags(t *testing.T) {
+	t.Parallel()
+
+	repodir := repoWithAnnotatedTags(t)
+
+	tagsGetter, err := git.NewRepoTagsGetter(repodir)
+	if err != nil {
+		t.Fatalf("Error creating tags getter: %v", err)
+	}
+
+	tags, err := tagsGetter.Tags()
+	if err != nil {
+		t.Fatalf("Error fetching tags: %v", err)
+	}
+
+	byName := map[string]git.Tag{}
+	for _, tag := range tags {
+		byName[tag.Name] = tag
+	}
+
+	for _, name := range []string{"v1.0.0", "v1.1.0", "v1.1.0-nested", "v1.2.0", "v9.9.9"} {
+		tag, found := byName[name]
+		if !found {
+			t.Fatalf("Tag %q not found in %v", name, tags)
+		}
+
+		assert.Equalf(t, gitOutput(t, repodir, "", "rev-parse", name+"^{commit}"), tag.Hash, "%s was not peeled to its commit", name)
+	}
+
+	assert.Nil(t, byName["v1.0.0"].Annotation, "Lightweight tags should not have annotations")
+
+	annotation := byName["v1.1.0"].Annotation
+	if annotation == nil {
+		t.Fatalf("Annotated tag has no annotation")
+	}
+	assert.Equal(t, gitOutput(t, repodir, "", "rev-parse", "v1.1.0"), annotation.Hash)
+	assert.Equal(t, "Test", annotation.TaggerName)
+	assert.Equal(t, "test@user.tld", annotation.TaggerEmail)
+	assert.Equal(t, "Release\n", annotation.Message)
+	assert.Empty(t, annotation.Signature)
+	assert.False(t, annotation.Date.IsZero(), "Tagger date should be set")
+
+	nested := byName["v1.1.0-nested"].Annotation
+	if nested == nil {
+		t.Fatalf("Nested annotated tag has no annotation")
+	}
+	assert.Equal(t, "Nested\n", nested.Message, "Nested tag should keep its own metadata")
+
+	signed := byName["v1.2.0"].Annotation
+	if signed == nil {
+		t.Fatalf("Signed tag has no annotation")
+	}
+	assert.Equal(t, "Signer", signed.TaggerName)
+	assert.Equal(t, "signer@user.tld", signed.TaggerEmail)
+	assert.True(t, time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC).Equal(signed.Date), "Unexpected tagger date %v", signed.Date)
+	assert.Equal(t, "Signed release\n", signed.Message)
+	assert.Equal(t, testSignature, signed.Signature)
+}
+
+func TestRepoTagsGetter_Annotated_Tags_Matching_Commits(t *testing.T) {
+	t.Parallel()
+
+	repodir := repoWithAnnotatedTags(t)
+
+	tagsGetter, err := git.NewRepoTagsGetter(repodir, git.TagsMatchingCommits(git.NewRepoCommitsGetter(repodir)))
+	if err != nil {
+		t.Fatalf("Error creating tags getter: %v", err)
+	}
+
+	src := git.NewTagsSource(tagsGetter)
+
+	versions, err := src.Versions()
+	if err != nil {
+		t.Fatalf("Error fetching versions: %v", err)
+	}
+
+	strVersions := make([]string, 0, len(versions))
+	for _, v := range versions {
+		strVersions = append(strVersions, v.String())
+	}
+
+	assert.Equal(t, []string{"1.2.0", "1.1.0", "1.1.0-nested", "1.0.0"}, strVersions, "Annotated tags in the branch should be kept")
+
+	hash, err := src.LastVersionHash()
+	if err != nil {
+		t.Fatalf("Error fetching last version hash: %v", err)
+	}
+
+	assert.Equal(t, gitOutput(t, repodir, "", "rev-parse", "HEAD"), hash, "Last version hash should be a commit hash")
+
+	commits, err := git.NewRepoCommitsGetter(repodir).Commits(hash)
+	if err != nil {
+		t.Fatalf("Error fetching commits: %v", err)
+	}
+
+	assert.Empty(t, commits, "There should be no commits since the last annotated tag")
+}
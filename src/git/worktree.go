@@ -0,0 +1,220 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+var (
+	// ErrNothingToCommit is returned by Worktree.Commit if none of the files to commit changed.
+	ErrNothingToCommit = errors.New("no changes to commit")
+	// ErrTagExists is returned by Worktree.Tag and Worktree.CheckTag if a tag with the same name already exists.
+	ErrTagExists = errors.New("tag already exists")
+	// ErrInvalidTagName is returned by Worktree.Tag and Worktree.CheckTag if the name is not a valid tag name.
+	ErrInvalidTagName = errors.New("invalid tag name")
+)
+
+// Worktree creates commits and tags in a local repository. It runs the git binary, so commits and tags are signed
+// with the keys and formats, GPG or SSH, configured in git.
+type Worktree struct {
+	git gitCmd
+}
+
+// NewWorktree returns a Worktree for the repository containing workDir.
+func NewWorktree(workDir string) *Worktree {
+	return &Worktree{
+		git: gitCmd{workDir: workDir},
+	}
+}
+
+// writeOptions holds the options shared by operations creating objects in the repository.
+type writeOptions struct {
+	sign   bool
+	key    string
+	format string
+}
+
+// WriteOptionFunc configures how Worktree writes commits and tags.
+type WriteOptionFunc func(o *writeOptions)
+
+// Signed returns an option that signs the commit or tag with key, in format, which is one of the values accepted by
+// the gpg.format setting of git, such as gpg or ssh. The ones configured in git, user.signingKey and gpg.format, are
+// used if they are empty.
+func Signed(format, key string) WriteOptionFunc {
+	return func(o *writeOptions) {
+		o.sign = true
+		o.key = key
+		o.format = format
+	}
+}
+
+func newWriteOptions(opts []WriteOptionFunc) writeOptions {
+	o := writeOptions{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return o
+}
+
+// config returns the git flags that set the signing format, if any.
+func (o writeOptions) config() []string {
+	if o.format == "" {
+		return nil
+	}
+
+	return []string{"-c", "gpg.format=" + o.format}
+}
+
+// Root returns the absolute path of the top-level directory of the worktree, with symlinks resolved.
+func (w *Worktree) Root() (string, error) {
+	out, err := w.git.run("rev-parse", "--show-toplevel")
+	if err != nil {
+		return "", fmt.Errorf("getting worktree root: %w", err)
+	}
+
+	return strings.TrimSpace(out), nil
+}
+
+// Changes returns the paths, relative to Root, of the tracked files that are modified, deleted or renamed in the
+// worktree or in the index. Untracked files are not reported.
+func (w *Worktree) Changes() ([]string, error) {
+	out, err := w.git.run("status", "--porcelain=v1", "-z", "--untracked-files=no")
+	if err != nil {
+		return nil, fmt.Errorf("getting worktree status: %w", err)
+	}
+
+	var changes []string
+	entries := strings.Split(out, "\x00")
+	for i := 0; i < len(entries); i++ {
+		entry := entries[i]
+		// Entries are two status letters, a space and the path.
+		if len(entry) < 4 {
+			continue
+		}
+
+		changes = append(changes, entry[3:])
+
+		// Renamed and copied entries are followed by the original path.
+		if entry[0] == 'R' || entry[0] == 'C' {
+			i++
+			if i < len(entries) {
+				changes = append(changes, entries[i])
+			}
+		}
+	}
+
+	return changes, nil
+}
+
+// Commit stages files, which are relative to the working directory or absolute, and commits them with message,
+// returning the hash of the new commit. Files not passed are not committed, even if they are staged.
+// ErrNothingToCommit is returned if none of the files changed.
+func (w *Worktree) Commit(message string, files []string, opts ...WriteOptionFunc) (string, error) {
+	o := newWriteOptions(opts)
+
+	if _, err := w.git.run(append([]string{"add", "--"}, files...)...); err != nil {
+		return "", fmt.Errorf("staging files: %w", err)
+	}
+
+	_, err := w.git.run(append([]string{"diff", "--cached", "--quiet", "--"}, files...)...)
+	if err == nil {
+		return "", ErrNothingToCommit
+	}
+
+	var gitErr *gitError
+	if !errors.As(err, &gitErr) || gitErr.stderr != "" {
+		return "", fmt.Errorf("checking staged files: %w", err)
+	}
+
+	args := append(o.config(), "commit", "--file=-", "--cleanup=whitespace")
+	if o.sign && o.key != "" {
+		args = append(args, "--gpg-sign="+o.key)
+	} else if o.sign {
+		args = append(args, "--gpg-sign")
+	}
+	args = append(args, "--only", "--")
+	args = append(args, files...)
+
+	if _, err = w.git.runWithInput(message, args...); err != nil {
+		return "", fmt.Errorf("committing files: %w", err)
+	}
+
+	return w.git.head()
+}
+
+// Head returns the hash of the commit HEAD points to.
+func (w *Worktree) Head() (string, error) {
+	return w.git.head()
+}
+
+// CheckTag returns ErrInvalidTagName if name is not a valid tag name, or ErrTagExists if the tag already exists, so
+// the tag can be checked before creating the commit it will point to.
+func (w *Worktree) CheckTag(name string) error {
+	// Names starting with a dash would be taken as flags.
+	if _, err := w.git.run("check-ref-format", "refs/tags/"+name); err != nil || strings.HasPrefix(name, "-") {
+		return fmt.Errorf("creating tag %q: %w", name, ErrInvalidTagName)
+	}
+
+	if _, err := w.git.run("rev-parse", "--verify", "--quiet", "refs/tags/"+name); err == nil {
+		return fmt.Errorf("creating tag %q: %w", name, ErrTagExists)
+	}
+
+	return nil
+}
+
+// Tag creates an annotated tag named name pointing to HEAD, with message as its message. Messages are kept verbatim,
+// so markdown headers are not taken as comments. ErrTagExists is returned if the tag already exists.
+func (w *Worktree) Tag(name, message string, opts ...WriteOptionFunc) error {
+	o := newWriteOptions(opts)
+
+	if err := w.CheckTag(name); err != nil {
+		return err
+	}
+
+	args := append(o.config(), "tag", "--annotate", "--file=-", "--cleanup=verbatim")
+	if o.sign {
+		args = append(args, "--sign")
+		if o.key != "" {
+			args = append(args, "--local-user="+o.key)
+		}
+	}
+	args = append(args, name, "HEAD")
+
+	if _, err := w.git.runWithInput(message, args...); err != nil {
+		return fmt.Errorf("creating tag %q: %w", name, err)
+	}
+
+	return nil
+}
+
+// Rel returns path, relative to the working directory or absolute, relative to Root.
+func (w *Worktree) Rel(path string) (string, error) {
+	root, err := w.Root()
+	if err != nil {
+		return "", err
+	}
+
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(w.git.workDir, path)
+	}
+
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", fmt.Errorf("getting absolute path of %q: %w", path, err)
+	}
+
+	// The root is reported with symlinks resolved, so path must be resolved too for them to share a prefix.
+	if resolved, rErr := filepath.EvalSymlinks(abs); rErr == nil {
+		abs = resolved
+	}
+
+	rel, err := filepath.Rel(root, abs)
+	if err != nil {
+		return "", fmt.Errorf("getting path of %q relative to %q: %w", abs, root, err)
+	}
+
+	return filepath.ToSlash(rel), nil
+}
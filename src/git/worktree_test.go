@@ -0,0 +1,166 @@
+package git_test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/newrelic/release-toolkit/src/git"
+	"github.com/stretchr/testify/assert"
+)
+
+// repoWithFiles creates a repository with a commit adding CHANGELOG.md, VERSION and other.
+func repoWithFiles(t *testing.T) string {
+	t.Helper()
+
+	dir := repoWithTags(t, "v1.0.0")
+	for _, file := range []string{"CHANGELOG.md", "VERSION", "other"} {
+		writeFile(t, dir, file, "initial\n")
+	}
+	executeCMDs(t, []string{
+		"git add CHANGELOG.md VERSION other",
+		"git commit -m files",
+	}, dir)
+
+	return dir
+}
+
+func writeFile(t *testing.T, dir, name, contents string) {
+	t.Helper()
+
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(contents), 0o600); err != nil {
+		t.Fatalf("Error writing %s: %v", name, err)
+	}
+}
+
+func TestWorktree_Changes(t *testing.T) {
+	t.Parallel()
+
+	dir := repoWithFiles(t)
+	writeFile(t, dir, "CHANGELOG.md", "changed\n")
+	writeFile(t, dir, "untracked", "untracked\n")
+	executeCMDs(t, []string{
+		"git mv other renamed",
+	}, dir)
+
+	changes, err := git.NewWorktree(dir).Changes()
+	if err != nil {
+		t.Fatalf("Error getting changes: %v", err)
+	}
+
+	assert.ElementsMatch(t, []string{"CHANGELOG.md", "renamed", "other"}, changes, "Untracked files should not be reported")
+}
+
+func TestWorktree_Commit(t *testing.T) {
+	t.Parallel()
+
+	dir := repoWithFiles(t)
+	writeFile(t, dir, "CHANGELOG.md", "changed\n")
+	writeFile(t, dir, "VERSION", "changed\n")
+	writeFile(t, dir, "new", "new\n")
+
+	worktree := git.NewWorktree(dir)
+
+	hash, err := worktree.Commit("Release v1.1.0\n\n# Not a comment\n", []string{"CHANGELOG.md", filepath.Join(dir, "new")})
+	if err != nil {
+		t.Fatalf("Error committing: %v", err)
+	}
+
+	assert.Equal(t, gitOutput(t, dir, "", "rev-parse", "HEAD"), hash)
+	assert.Equal(t, "Release v1.1.0\n\n# Not a comment", gitOutput(t, dir, "", "log", "-1", "--format=%B"))
+	assert.Equal(t, "CHANGELOG.md\nnew", gitOutput(t, dir, "", "show", "--format=", "--name-only", "HEAD"))
+
+	changes, err := worktree.Changes()
+	if err != nil {
+		t.Fatalf("Error getting changes: %v", err)
+	}
+	assert.Equal(t, []string{"VERSION"}, changes, "Files not passed should not be committed")
+
+	_, err = worktree.Commit("Release", []string{"CHANGELOG.md"})
+	assert.ErrorIs(t, err, git.ErrNothingToCommit)
+}
+
+func TestWorktree_Tag(t *testing.T) {
+	t.Parallel()
+
+	dir := repoWithFiles(t)
+	worktree := git.NewWorktree(dir)
+
+	notes := "## v1.1.0 - 2023-01-01\n\n### Enhancements\n- Something\n"
+	if err := worktree.Tag("v1.1.0", notes); err != nil {
+		t.Fatalf("Error tagging: %v", err)
+	}
+
+	tags, err := git.NewExecTagsGetter(dir, git.TagsMatchingRegex("^v1.1.0$"))
+	if err != nil {
+		t.Fatalf("Error creating tags getter: %v", err)
+	}
+	tagged, err := tags.Tags()
+	if err != nil {
+		t.Fatalf("Error fetching tags: %v", err)
+	}
+
+	if len(tagged) != 1 || tagged[0].Annotation == nil {
+		t.Fatalf("Expected an annotated tag, got %v", tagged)
+	}
+	assert.Equal(t, gitOutput(t, dir, "", "rev-parse", "HEAD"), tagged[0].Hash)
+	assert.Equal(t, notes, tagged[0].Annotation.Message, "Markdown headers should be kept")
+
+	assert.ErrorIs(t, worktree.Tag("v1.1.0", notes), git.ErrTagExists)
+	assert.ErrorIs(t, worktree.Tag("--force", notes), git.ErrInvalidTagName)
+	assert.ErrorIs(t, worktree.Tag("v1..1", notes), git.ErrInvalidTagName)
+
+	assert.NoError(t, worktree.CheckTag("v1.2.0"))
+	assert.ErrorIs(t, worktree.CheckTag("v1.1.0"), git.ErrTagExists)
+	assert.ErrorIs(t, worktree.CheckTag("v1..2"), git.ErrInvalidTagName)
+
+	head, err := worktree.Head()
+	assert.NoError(t, err)
+	assert.Equal(t, gitOutput(t, dir, "", "rev-parse", "HEAD"), head)
+}
+
+func TestWorktree_Signed(t *testing.T) {
+	t.Parallel()
+
+	if _, err := exec.LookPath("ssh-keygen"); err != nil {
+		t.Skip("ssh-keygen is needed to create a signing key")
+	}
+
+	dir := repoWithFiles(t)
+	key := filepath.Join(t.TempDir(), "key")
+	//nolint:gosec // This is a test, we trust hardcoded input.
+	if out, err := exec.Command("ssh-keygen", "-q", "-t", "ed25519", "-N", "", "-C", "test", "-f", key).CombinedOutput(); err != nil {
+		t.Fatalf("Error creating signing key: %v\n%s", err, out)
+	}
+
+	pub, err := os.ReadFile(key + ".pub")
+	if err != nil {
+		t.Fatalf("Error reading public key: %v", err)
+	}
+
+	signersDir := t.TempDir()
+	writeFile(t, signersDir, "allowed_signers", "test@user.tld "+string(pub))
+	executeCMDs(t, []string{
+		"git config gpg.ssh.allowedSignersFile " + filepath.Join(signersDir, "allowed_signers"),
+	}, dir)
+
+	writeFile(t, dir, "CHANGELOG.md", "changed\n")
+	worktree := git.NewWorktree(dir)
+
+	if _, err := worktree.Commit("Release v1.1.0", []string{"CHANGELOG.md"}, git.Signed("ssh", key)); err != nil {
+		t.Fatalf("Error committing: %v", err)
+	}
+	if err := worktree.Tag("v1.1.0", "Notes\n", git.Signed("ssh", key)); err != nil {
+		t.Fatalf("Error tagging: %v", err)
+	}
+
+	for _, args := range [][]string{{"verify-commit", "HEAD"}, {"verify-tag", "v1.1.0"}} {
+		//nolint:gosec // This is a test, we trust hardcoded input.
+		cmd := exec.Command("git", append([]string{"-c", "gpg.format=ssh"}, args...)...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("Error verifying signature with %v: %v\n%s", args, err, out)
+		}
+	}
+}
@@ -0,0 +1,151 @@
+// Package monorepo holds the manifest describing the components of a monorepo, and the summary that tracks which of
+// them need to be released.
+package monorepo
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// DefaultSummary is the path of the summary file, relative to the manifest, if none is specified.
+	DefaultSummary = "release-summary.yaml"
+
+	defaultMarkdown        = "CHANGELOG.md"
+	defaultPartialMarkdown = "CHANGELOG.partial.md"
+	defaultYAML            = "changelog.yaml"
+)
+
+var (
+	ErrNoComponents       = errors.New("manifest does not define any component")
+	ErrMissingName        = errors.New("component name cannot be empty")
+	ErrDuplicatedName     = errors.New("component name is duplicated")
+	ErrMissingTagPrefix   = errors.New("component tag prefix cannot be empty")
+	ErrInvalidPath        = errors.New(`component path cannot be empty, ".", absolute, or point outside the repository`)
+	ErrDuplicatedPrefix   = errors.New("component tag prefix is duplicated")
+	ErrComponentNotListed = errors.New("component is not listed in the manifest")
+)
+
+// Manifest lists the components of a monorepo.
+// Paths to files are relative to the directory containing the manifest, while component paths are relative to the
+// root of the git repository, as they are used to filter commits.
+type Manifest struct {
+	// Summary is the path to the file where the release summary is written.
+	Summary    string      `yaml:"summary"`
+	Components []Component `yaml:"components"`
+}
+
+// Component is a part of a monorepo that is versioned and released on its own.
+type Component struct {
+	Name string `yaml:"name"`
+	// Path is the directory, relative to the repository root, where the component lives. Only commits changing files
+	// in this directory are considered part of the component's history.
+	Path string `yaml:"path"`
+	// TagPrefix is prepended to versions when tagging releases of this component, e.g. `agent-` for `agent-v1.2.3`.
+	TagPrefix string `yaml:"tag-prefix"`
+	// Markdown is the path to the component's CHANGELOG.md. Defaults to `<path>/CHANGELOG.md`.
+	Markdown string `yaml:"markdown"`
+	// PartialMarkdown is the path where the release notes for the component are rendered.
+	// Defaults to `<path>/CHANGELOG.partial.md`.
+	PartialMarkdown string `yaml:"partial-markdown"`
+	// YAML is the path to the component's changelog.yaml. Defaults to `<path>/changelog.yaml`.
+	YAML string `yaml:"yaml"`
+}
+
+// Load reads and validates a manifest from the specified path, filling defaults and resolving file paths relative to
+// the current working directory.
+func Load(path string) (*Manifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading manifest %q: %w", path, err)
+	}
+
+	m := &Manifest{}
+	if err = yaml.Unmarshal(data, m); err != nil {
+		return nil, fmt.Errorf("unmarshalling manifest %q: %w", path, err)
+	}
+
+	if err = m.validate(); err != nil {
+		return nil, fmt.Errorf("validating manifest %q: %w", path, err)
+	}
+
+	m.resolve(filepath.Dir(path))
+
+	return m, nil
+}
+
+// Component returns the component with the given name.
+func (m *Manifest) Component(name string) (Component, error) {
+	for _, c := range m.Components {
+		if c.Name == name {
+			return c, nil
+		}
+	}
+
+	return Component{}, fmt.Errorf("%q: %w", name, ErrComponentNotListed)
+}
+
+func (m *Manifest) validate() error {
+	if len(m.Components) == 0 {
+		return ErrNoComponents
+	}
+
+	names := map[string]bool{}
+	prefixes := map[string]bool{}
+
+	for _, c := range m.Components {
+		if c.Name == "" {
+			return ErrMissingName
+		}
+		if names[c.Name] {
+			return fmt.Errorf("%q: %w", c.Name, ErrDuplicatedName)
+		}
+		names[c.Name] = true
+
+		if c.TagPrefix == "" {
+			return fmt.Errorf("%q: %w", c.Name, ErrMissingTagPrefix)
+		}
+		if prefixes[c.TagPrefix] {
+			return fmt.Errorf("%q: %w", c.TagPrefix, ErrDuplicatedPrefix)
+		}
+		prefixes[c.TagPrefix] = true
+
+		clean := filepath.Clean(c.Path)
+		if c.Path == "" || clean == "." || filepath.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, "../") {
+			return fmt.Errorf("%q: %w", c.Name, ErrInvalidPath)
+		}
+	}
+
+	return nil
+}
+
+// resolve fills in default values and makes file paths relative to the current directory instead of dir.
+func (m *Manifest) resolve(dir string) {
+	m.Summary = resolveFile(dir, m.Summary, DefaultSummary)
+
+	for i := range m.Components {
+		c := &m.Components[i]
+		c.Path = filepath.Clean(c.Path)
+
+		c.Markdown = resolveFile(dir, c.Markdown, filepath.Join(c.Path, defaultMarkdown))
+		c.PartialMarkdown = resolveFile(dir, c.PartialMarkdown, filepath.Join(c.Path, defaultPartialMarkdown))
+		c.YAML = resolveFile(dir, c.YAML, filepath.Join(c.Path, defaultYAML))
+	}
+}
+
+func resolveFile(dir, path, fallback string) string {
+	if path == "" {
+		path = fallback
+	}
+
+	if filepath.IsAbs(path) {
+		return path
+	}
+
+	return filepath.Join(dir, path)
+}
@@ -0,0 +1,240 @@
+package monorepo_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/newrelic/release-toolkit/src/monorepo"
+)
+
+//nolint:funlen
+func TestLoad(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name          string
+		manifest      string
+		expected      *monorepo.Manifest
+		errorExpected error
+	}{
+		{
+			name: "Defaults",
+			manifest: `
+components:
+- name: agent
+  path: cmd/agent/
+  tag-prefix: agent-
+- name: operator
+  path: operator
+  tag-prefix: operator-
+  markdown: docs/OPERATOR.md
+  yaml: /tmp/operator.yaml
+`,
+			expected: &monorepo.Manifest{
+				Summary: "{dir}/release-summary.yaml",
+				Components: []monorepo.Component{
+					{
+						Name:            "agent",
+						Path:            "cmd/agent",
+						TagPrefix:       "agent-",
+						Markdown:        "{dir}/cmd/agent/CHANGELOG.md",
+						PartialMarkdown: "{dir}/cmd/agent/CHANGELOG.partial.md",
+						YAML:            "{dir}/cmd/agent/changelog.yaml",
+					},
+					{
+						Name:            "operator",
+						Path:            "operator",
+						TagPrefix:       "operator-",
+						Markdown:        "{dir}/docs/OPERATOR.md",
+						PartialMarkdown: "{dir}/operator/CHANGELOG.partial.md",
+						YAML:            "/tmp/operator.yaml",
+					},
+				},
+			},
+		},
+		{
+			name: "Absolute_Summary",
+			manifest: `
+summary: /tmp/summary.yaml
+components:
+- name: agent
+  path: agent
+  tag-prefix: agent-
+`,
+			expected: &monorepo.Manifest{
+				Summary: "/tmp/summary.yaml",
+				Components: []monorepo.Component{
+					{
+						Name:            "agent",
+						Path:            "agent",
+						TagPrefix:       "agent-",
+						Markdown:        "{dir}/agent/CHANGELOG.md",
+						PartialMarkdown: "{dir}/agent/CHANGELOG.partial.md",
+						YAML:            "{dir}/agent/changelog.yaml",
+					},
+				},
+			},
+		},
+		{
+			name:          "No_Components",
+			manifest:      `summary: foo.yaml`,
+			errorExpected: monorepo.ErrNoComponents,
+		},
+		{
+			name: "Missing_Name",
+			manifest: `
+components:
+- path: agent
+  tag-prefix: agent-
+`,
+			errorExpected: monorepo.ErrMissingName,
+		},
+		{
+			name: "Duplicated_Name",
+			manifest: `
+components:
+- name: agent
+  path: agent
+  tag-prefix: agent-
+- name: agent
+  path: other
+  tag-prefix: other-
+`,
+			errorExpected: monorepo.ErrDuplicatedName,
+		},
+		{
+			name: "Missing_Prefix",
+			manifest: `
+components:
+- name: agent
+  path: agent
+`,
+			errorExpected: monorepo.ErrMissingTagPrefix,
+		},
+		{
+			name: "Duplicated_Prefix",
+			manifest: `
+components:
+- name: agent
+  path: agent
+  tag-prefix: agent-
+- name: other
+  path: other
+  tag-prefix: agent-
+`,
+			errorExpected: monorepo.ErrDuplicatedPrefix,
+		},
+		{
+			name: "Root_Path",
+			manifest: `
+components:
+- name: agent
+  path: ./
+  tag-prefix: agent-
+`,
+			errorExpected: monorepo.ErrInvalidPath,
+		},
+		{
+			name: "Outside_Path",
+			manifest: `
+components:
+- name: agent
+  path: agent/../../foo
+  tag-prefix: agent-
+`,
+			errorExpected: monorepo.ErrInvalidPath,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			dir := t.TempDir()
+			manifestPath := filepath.Join(dir, "monorepo.yaml")
+			if err := os.WriteFile(manifestPath, []byte(tc.manifest), 0o600); err != nil {
+				t.Fatalf("Error writing manifest: %v", err)
+			}
+
+			manifest, err := monorepo.Load(manifestPath)
+			if !errors.Is(err, tc.errorExpected) {
+				t.Fatalf("Expected error %v, got %v", tc.errorExpected, err)
+			}
+
+			if tc.expected == nil {
+				return
+			}
+
+			if rel, ok := cutDir(tc.expected.Summary); ok {
+				tc.expected.Summary = filepath.Join(dir, rel)
+			}
+			for i := range tc.expected.Components {
+				c := &tc.expected.Components[i]
+				for _, p := range []*string{&c.Markdown, &c.PartialMarkdown, &c.YAML} {
+					if rel, ok := cutDir(*p); ok {
+						*p = filepath.Join(dir, rel)
+					}
+				}
+			}
+
+			if diff := cmp.Diff(tc.expected, manifest); diff != "" {
+				t.Fatalf("Manifest is not as expected:\n%s", diff)
+			}
+		})
+	}
+}
+
+// cutDir strips the {dir}/ placeholder used in test cases to refer to the directory holding the manifest.
+func cutDir(path string) (string, bool) {
+	const placeholder = "{dir}/"
+	if !strings.HasPrefix(path, placeholder) {
+		return path, false
+	}
+
+	return strings.TrimPrefix(path, placeholder), true
+}
+
+func TestSummary(t *testing.T) {
+	t.Parallel()
+
+	summaryPath := filepath.Join(t.TempDir(), "summary.yaml")
+
+	summary, err := monorepo.ReadSummary(summaryPath)
+	if err != nil {
+		t.Fatalf("Reading nonexistent summary should not fail: %v", err)
+	}
+
+	summary.Component("agent").Release = true
+	summary.Component("operator").Empty = true
+	summary.Component("agent").Next = "v1.2.3"
+
+	if err = summary.Write(summaryPath); err != nil {
+		t.Fatalf("Error writing summary: %v", err)
+	}
+
+	read, err := monorepo.ReadSummary(summaryPath)
+	if err != nil {
+		t.Fatalf("Error reading summary: %v", err)
+	}
+
+	expected := &monorepo.Summary{
+		Components: []monorepo.ComponentSummary{
+			{Name: "agent", Next: "v1.2.3", Release: true},
+			{Name: "operator", Empty: true},
+		},
+	}
+	if diff := cmp.Diff(expected, read); diff != "" {
+		t.Fatalf("Summary is not as expected:\n%s", diff)
+	}
+
+	manifest := &monorepo.Manifest{
+		Components: []monorepo.Component{{Name: "operator"}, {Name: "agent"}},
+	}
+	releases := manifest.Releases(read)
+	if len(releases) != 1 || releases[0].Name != "agent" || releases[0].Version != "v1.2.3" {
+		t.Fatalf("Unexpected releases %v", releases)
+	}
+}
@@ -0,0 +1,112 @@
+package monorepo
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+const summaryFilePermissions = os.FileMode(0o644)
+
+// Summary tracks the release status of each component of a monorepo. It is built incrementally by the different
+// commands: generate-yaml records whether changelogs are empty or held, and next-version records the versions.
+type Summary struct {
+	Components []ComponentSummary `yaml:"components"`
+}
+
+// ComponentSummary holds the release status of a single component.
+type ComponentSummary struct {
+	Name    string `yaml:"name"`
+	Empty   bool   `yaml:"empty"`
+	Held    bool   `yaml:"held"`
+	Current string `yaml:"current,omitempty"`
+	Next    string `yaml:"next,omitempty"`
+	// Release is true if the component has changes that should be released.
+	Release bool `yaml:"release"`
+	// NeedsInitialVersion is true if the component has no version yet, so one must be tagged before it can be
+	// released.
+	NeedsInitialVersion bool `yaml:"needs-initial-version,omitempty"`
+}
+
+// ReadSummary reads a summary from path. If the file does not exist, an empty summary is returned.
+func ReadSummary(path string) (*Summary, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return &Summary{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading summary %q: %w", path, err)
+	}
+
+	s := &Summary{}
+	if err = yaml.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("unmarshalling summary %q: %w", path, err)
+	}
+
+	return s, nil
+}
+
+// Write writes the summary to path, replacing it if it exists.
+func (s *Summary) Write(path string) error {
+	data, err := yaml.Marshal(s)
+	if err != nil {
+		return fmt.Errorf("marshalling summary: %w", err)
+	}
+
+	if err = os.WriteFile(path, data, summaryFilePermissions); err != nil {
+		return fmt.Errorf("writing summary %q: %w", path, err)
+	}
+
+	return nil
+}
+
+// Component returns the summary for the named component, adding an empty one if it does not exist yet.
+func (s *Summary) Component(name string) *ComponentSummary {
+	for i := range s.Components {
+		if s.Components[i].Name == name {
+			return &s.Components[i]
+		}
+	}
+
+	s.Components = append(s.Components, ComponentSummary{Name: name})
+
+	return &s.Components[len(s.Components)-1]
+}
+
+// Releasing returns the names of the components that should be released, in the order they were added.
+func (s *Summary) Releasing() []string {
+	var names []string
+	for _, c := range s.Components {
+		if c.Release {
+			names = append(names, c.Name)
+		}
+	}
+
+	return names
+}
+
+// Release is a component that should be released, along with the version it should be released as.
+type Release struct {
+	Component
+	// Version is the next version of the component, as computed by next-version. It might be empty if next-version
+	// has not been run.
+	Version string
+}
+
+// Releases returns the components of the manifest that are marked for release in the summary, in manifest order.
+func (m *Manifest) Releases(s *Summary) []Release {
+	var releases []Release
+
+	for _, c := range m.Components {
+		for _, cs := range s.Components {
+			if cs.Name == c.Name && cs.Release {
+				releases = append(releases, Release{Component: c, Version: cs.Next})
+			}
+		}
+	}
+
+	return releases
+}
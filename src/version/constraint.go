@@ -0,0 +1,41 @@
+package version
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Masterminds/semver"
+)
+
+var ErrConstraintNotValid = errors.New("version constraint is not valid")
+
+// Constraint restricts versions to a range, such as `~1.4` or `<2.0.0`, to work on a maintenance line.
+// Prereleases are checked as the version they precede, so `v1.5.0-rc.1` satisfies `~1.5` but not `<1.5.0`.
+type Constraint struct {
+	constraints *semver.Constraints
+	original    string
+}
+
+// NewConstraint parses a constraint in the syntax of github.com/Masterminds/semver.
+func NewConstraint(c string) (*Constraint, error) {
+	constraints, err := semver.NewConstraint(c)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %q: %v", ErrConstraintNotValid, c, err)
+	}
+
+	return &Constraint{constraints: constraints, original: c}, nil
+}
+
+// Check returns whether v satisfies the constraint.
+func (c *Constraint) Check(v *semver.Version) bool {
+	// Errors are only returned for invalid prerelease and metadata strings, and empty strings are always valid.
+	base, _ := v.SetPrerelease("")
+	base, _ = base.SetMetadata("")
+
+	return c.constraints.Check(&base)
+}
+
+// String returns the constraint as it was written.
+func (c *Constraint) String() string {
+	return c.original
+}
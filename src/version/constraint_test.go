@@ -0,0 +1,55 @@
+package version_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Masterminds/semver"
+	"github.com/newrelic/release-toolkit/src/version"
+)
+
+func TestConstraint_Check(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		constraint string
+		version    string
+		expected   bool
+	}{
+		{constraint: "~1.4", version: "v1.4.0", expected: true},
+		{constraint: "~1.4", version: "v1.4.7", expected: true},
+		{constraint: "~1.4", version: "v1.5.0", expected: false},
+		{constraint: "~1.4", version: "v1.4.1-rc.1", expected: true},
+		{constraint: "~1.4", version: "v1.5.0-rc.1", expected: false},
+		{constraint: "<2.0.0", version: "v1.99.0", expected: true},
+		{constraint: "<2.0.0", version: "v2.0.0-rc.1", expected: false},
+		{constraint: "<2.0.0", version: "v2.3.0", expected: false},
+		{constraint: "1.x", version: "1.2.3+build", expected: true},
+	} {
+		tc := tc
+		t.Run(tc.constraint+"_"+tc.version, func(t *testing.T) {
+			t.Parallel()
+
+			c, err := version.NewConstraint(tc.constraint)
+			if err != nil {
+				t.Fatalf("Error parsing constraint: %v", err)
+			}
+
+			if actual := c.Check(semver.MustParse(tc.version)); actual != tc.expected {
+				t.Fatalf("Expected %q to satisfy %q to be %v", tc.version, tc.constraint, tc.expected)
+			}
+
+			if c.String() != tc.constraint {
+				t.Fatalf("Expected constraint to be printed as %q, got %q", tc.constraint, c.String())
+			}
+		})
+	}
+}
+
+func TestNewConstraint_Invalid(t *testing.T) {
+	t.Parallel()
+
+	if _, err := version.NewConstraint("~one.four"); !errors.Is(err, version.ErrConstraintNotValid) {
+		t.Fatalf("Expected %v, got %v", version.ErrConstraintNotValid, err)
+	}
+}
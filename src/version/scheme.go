@@ -0,0 +1,175 @@
+package version
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Masterminds/semver"
+	"github.com/newrelic/release-toolkit/src/bump"
+)
+
+type SchemeName string
+
+const (
+	SemverName = SchemeName("semver")
+	CalVerName = SchemeName("calver")
+)
+
+var (
+	ErrSchemeNotValid = errors.New("versioning scheme is not valid")
+	ErrNotCalVer      = errors.New("version does not follow the YYYY.MM.MICRO format")
+)
+
+// Scheme defines how versions are parsed from strings and how they are bumped.
+type Scheme interface {
+	// Parse parses a version string, returning an error if it does not conform to the scheme.
+	Parse(v string) (*semver.Version, error)
+	// Bump returns the version that follows current, given the bump required by the changes since current.
+	// It must return current itself if the bump type does not produce a new version.
+	Bump(current *semver.Version, bt bump.Type) *semver.Version
+}
+
+// Semver is the default scheme, following the semantic versioning spec.
+type Semver struct{}
+
+//nolint:wrapcheck // Semver is a thin wrapper around the semver package.
+func (Semver) Parse(v string) (*semver.Version, error) {
+	return semver.NewVersion(v)
+}
+
+func (Semver) Bump(current *semver.Version, bt bump.Type) *semver.Version {
+	return bump.Bump(current, bt)
+}
+
+// CalVer implements calendar versioning in the YYYY.MM.MICRO format, where YYYY and MM are the year and month the
+// version was released on, and MICRO is a counter that resets every month.
+// Versions are stored in the major, minor and patch fields of semver.Version, so they can still be compared.
+// The month of new versions is zero-padded, as in `2024.03.0`, if it is in the current version.
+type CalVer struct {
+	// Now returns the date used to compute the next version. It defaults to time.Now.
+	Now func() time.Time
+	// PadMonth zero-pads the month of new versions even if the current one is not, which cannot be told apart for
+	// October, November and December.
+	PadMonth bool
+}
+
+// Parse parses v as a semver and checks that its major and minor components are a plausible year and month.
+func (c CalVer) Parse(v string) (*semver.Version, error) {
+	version, err := semver.NewVersion(v)
+	if err != nil {
+		return nil, fmt.Errorf("parsing %q: %w", v, err)
+	}
+
+	if version.Major() < 1000 || version.Minor() < 1 || version.Minor() > 12 {
+		return nil, fmt.Errorf("parsing %q: %w", v, ErrNotCalVer)
+	}
+
+	return version, nil
+}
+
+// Bump returns the first version of the current month if current was released on a previous month, or the next MICRO
+// version otherwise. The magnitude of the bump is not relevant for CalVer, as long as it is not bump.None.
+func (c CalVer) Bump(current *semver.Version, bt bump.Type) *semver.Version {
+	if bt == bump.None {
+		return current
+	}
+
+	now := time.Now
+	if c.Now != nil {
+		now = c.Now
+	}
+
+	year, month, _ := now().Date()
+	if int64(year) > current.Major() || (int64(year) == current.Major() && int64(month) > current.Minor()) {
+		return c.version(current, int64(year), int64(month), 0)
+	}
+
+	// Like IncPatch, bumping a prerelease yields its release.
+	if current.Prerelease() != "" {
+		return c.version(current, current.Major(), current.Minor(), current.Patch())
+	}
+
+	return c.version(current, current.Major(), current.Minor(), current.Patch()+1)
+}
+
+// version builds a new version preserving the leading "v" and the zero-padding of the month of the current one, if any.
+func (c CalVer) version(current *semver.Version, year, month, micro int64) *semver.Version {
+	prefix := ""
+	if strings.HasPrefix(current.Original(), "v") {
+		prefix = "v"
+	}
+
+	monthFormat := "%d"
+	if c.PadMonth || paddedMinor(current) {
+		monthFormat = "%02d"
+	}
+
+	// A version built from three integers is always valid.
+	v, _ := semver.NewVersion(fmt.Sprintf("%s%d."+monthFormat+".%d", prefix, year, month, micro))
+
+	return v
+}
+
+// Format returns v without the leading "v", like semver.Version.String, but keeping the zero-padding of its minor
+// component if it was parsed from a padded string, as the month of CalVer versions is, e.g. `2024.03.0`.
+func Format(v *semver.Version) string {
+	if !paddedMinor(v) {
+		return v.String()
+	}
+
+	formatted := fmt.Sprintf("%d.%02d.%d", v.Major(), v.Minor(), v.Patch())
+	if v.Prerelease() != "" {
+		formatted += "-" + v.Prerelease()
+	}
+	if v.Metadata() != "" {
+		formatted += "+" + v.Metadata()
+	}
+
+	return formatted
+}
+
+// paddedMinor returns whether the minor component of v was zero-padded in the string it was parsed from.
+func paddedMinor(v *semver.Version) bool {
+	parts := strings.SplitN(strings.TrimPrefix(v.Original(), "v"), ".", 3)
+
+	return len(parts) > 1 && len(parts[1]) > 1 && strings.HasPrefix(parts[1], "0")
+}
+
+// SchemeOptionFunc configures the scheme returned by SchemeFor. Options only affect CalVer, as Semver has none.
+type SchemeOptionFunc func(c *CalVer)
+
+// CalVerNow sets the function returning the date CalVer versions are computed for. Nil means time.Now.
+func CalVerNow(now func() time.Time) SchemeOptionFunc {
+	return func(c *CalVer) {
+		c.Now = now
+	}
+}
+
+// CalVerPadMonth sets whether the month of new CalVer versions is always zero-padded. See CalVer.PadMonth.
+func CalVerPadMonth(pad bool) SchemeOptionFunc {
+	return func(c *CalVer) {
+		c.PadMonth = pad
+	}
+}
+
+// SchemeFor returns the scheme corresponding to the given name, which should be one of the SchemeName constants,
+// configured with opts.
+//
+//nolint:ireturn // Returning the interface is the whole point of this function.
+func SchemeFor(name string, opts ...SchemeOptionFunc) (Scheme, error) {
+	switch SchemeName(strings.ToLower(name)) {
+	case SemverName, "":
+		return Semver{}, nil
+	case CalVerName:
+		calver := CalVer{}
+		for _, opt := range opts {
+			opt(&calver)
+		}
+
+		return calver, nil
+	default:
+		return nil, fmt.Errorf("the name %s is not in %q or %q: %w", name, SemverName, CalVerName, ErrSchemeNotValid)
+	}
+}
@@ -0,0 +1,142 @@
+package version_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Masterminds/semver"
+	"github.com/newrelic/release-toolkit/src/bump"
+	"github.com/newrelic/release-toolkit/src/version"
+)
+
+func TestCalVer_Parse(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		version       string
+		errorExpected error
+	}{
+		{version: "v2024.3.1"},
+		{version: "2024.12.0"},
+		{version: "2024.03.0"},
+		{version: "v1.2.3", errorExpected: version.ErrNotCalVer},
+		{version: "v2024.13.0", errorExpected: version.ErrNotCalVer},
+		{version: "v2024.0.0", errorExpected: version.ErrNotCalVer},
+	} {
+		tc := tc
+		t.Run(tc.version, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := version.CalVer{}.Parse(tc.version)
+			if !errors.Is(err, tc.errorExpected) {
+				t.Fatalf("Expected error %v, got %v", tc.errorExpected, err)
+			}
+		})
+	}
+}
+
+func TestCalVer_Bump(t *testing.T) {
+	t.Parallel()
+
+	calver := version.CalVer{
+		Now: func() time.Time {
+			return time.Date(2024, time.March, 31, 23, 0, 0, 0, time.UTC)
+		},
+	}
+
+	for _, tc := range []struct {
+		name     string
+		current  string
+		bump     bump.Type
+		expected string
+	}{
+		{name: "None", current: "v2023.1.4", bump: bump.None, expected: "v2023.1.4"},
+		{name: "Same_Month", current: "v2024.3.4", bump: bump.Patch, expected: "v2024.3.5"},
+		{name: "Same_Month_Major", current: "v2024.3.4", bump: bump.Major, expected: "v2024.3.5"},
+		{name: "Previous_Month", current: "v2024.2.4", bump: bump.Minor, expected: "v2024.3.0"},
+		{name: "Previous_Year", current: "2023.11.1", bump: bump.Patch, expected: "2024.3.0"},
+		{name: "Future_Month", current: "v2024.4.1", bump: bump.Patch, expected: "v2024.4.2"},
+		{name: "Prerelease", current: "v2024.3.1-rc.1", bump: bump.Patch, expected: "v2024.3.1"},
+		{name: "Padded_Same_Month", current: "2024.03.4", bump: bump.Patch, expected: "2024.03.5"},
+		{name: "Padded_Previous_Month", current: "v2024.01.3", bump: bump.Patch, expected: "v2024.03.0"},
+		{name: "Padded_Prerelease", current: "2024.03.1-rc.1", bump: bump.Patch, expected: "2024.03.1"},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			next := calver.Bump(semver.MustParse(tc.current), tc.bump)
+			if next.Original() != tc.expected {
+				t.Fatalf("Expected %v, got %v", tc.expected, next.Original())
+			}
+		})
+	}
+}
+
+func TestCalVer_Bump_PadMonth(t *testing.T) {
+	t.Parallel()
+
+	calver := version.CalVer{
+		Now: func() time.Time {
+			return time.Date(2025, time.January, 2, 0, 0, 0, 0, time.UTC)
+		},
+		PadMonth: true,
+	}
+
+	if next := calver.Bump(semver.MustParse("2024.12.3"), bump.Patch); next.Original() != "2025.01.0" {
+		t.Fatalf("Expected 2025.01.0, got %v", next.Original())
+	}
+}
+
+func TestSchemeFor(t *testing.T) {
+	t.Parallel()
+
+	if _, err := version.SchemeFor("semver"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if s, err := version.SchemeFor("CalVer"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	} else if _, ok := s.(version.CalVer); !ok {
+		t.Fatalf("Expected CalVer scheme, got %T", s)
+	}
+
+	now := func() time.Time { return time.Date(2025, time.January, 2, 0, 0, 0, 0, time.UTC) }
+	s, err := version.SchemeFor("calver", version.CalVerNow(now), version.CalVerPadMonth(true))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if next := s.Bump(semver.MustParse("2024.12.3"), bump.Patch); next.Original() != "2025.01.0" {
+		t.Fatalf("Expected options to be applied to CalVer, got %v", next.Original())
+	}
+
+	if _, err := version.SchemeFor("romannumerals"); !errors.Is(err, version.ErrSchemeNotValid) {
+		t.Fatalf("Expected %v, got %v", version.ErrSchemeNotValid, err)
+	}
+}
+
+func TestFormat(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		version  string
+		expected string
+	}{
+		{version: "v1.2.3", expected: "1.2.3"},
+		{version: "1.2", expected: "1.2.0"},
+		{version: "v2024.3.0", expected: "2024.3.0"},
+		{version: "v2024.03.0", expected: "2024.03.0"},
+		{version: "2024.10.1", expected: "2024.10.1"},
+		{version: "2024.03.1-rc.1+build.2", expected: "2024.03.1-rc.1+build.2"},
+	} {
+		tc := tc
+		t.Run(tc.version, func(t *testing.T) {
+			t.Parallel()
+
+			if actual := version.Format(semver.MustParse(tc.version)); actual != tc.expected {
+				t.Fatalf("Expected %v, got %v", tc.expected, actual)
+			}
+		})
+	}
+}